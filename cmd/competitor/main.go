@@ -10,6 +10,7 @@ import (
 	baseconfig "amazonpilot/internal/pkg/config"
 	"amazonpilot/internal/pkg/constants"
 	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/middleware"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/rest"
@@ -50,6 +51,9 @@ func main() {
 	server := rest.MustNewServer(c.RestConf)
 	defer server.Stop()
 
+	// 请求头中的工作区ID放入context，供业务逻辑校验成员角色
+	server.Use(middleware.NewWorkspaceMiddleware().Handle)
+
 	ctx := svc.NewServiceContext(c)
 	handler.RegisterHandlers(server, ctx)
 
@@ -81,6 +81,8 @@ func main() {
 			ResponseHeaderTimeout: 10 * time.Minute, // 10分钟超时
 			IdleConnTimeout:       15 * time.Minute,
 		}
+		// text/event-stream 响应会被立即刷新，其余响应每100ms刷新一次，流式下载不在网关缓冲
+		proxy.FlushInterval = 100 * time.Millisecond
 
 		proxies[service] = proxy
 		metrics.SetServiceHealth(service, true)
@@ -160,8 +162,8 @@ func main() {
 
 func setCORSHeaders(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "http://localhost:4000")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, X-Workspace-ID")
 	w.Header().Set("Access-Control-Allow-Credentials", "true")
 	w.Header().Set("Access-Control-Max-Age", "86400")
 }
@@ -179,3 +181,15 @@ func (rw *responseWriter) WriteHeader(code int) {
 func (rw *responseWriter) Write(b []byte) (int, error) {
 	return rw.ResponseWriter.Write(b)
 }
+
+// Flush 支持流式响应 (SSE) 逐条刷新到客户端
+func (rw *responseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap 供 http.ResponseController 访问底层连接
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -7,6 +7,7 @@ import (
 	baseconfig "amazonpilot/internal/pkg/config"
 	"amazonpilot/internal/pkg/constants"
 	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/middleware"
 	"flag"
 	"log/slog"
 
@@ -49,6 +50,9 @@ func main() {
 	server := rest.MustNewServer(c.RestConf)
 	defer server.Stop()
 
+	// 请求头中的工作区ID放入context，供业务逻辑校验成员角色
+	server.Use(middleware.NewWorkspaceMiddleware().Handle)
+
 	ctx := svc.NewServiceContext(c)
 	handler.RegisterHandlers(server, ctx)
 
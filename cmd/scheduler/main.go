@@ -1,7 +1,7 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -12,8 +12,10 @@ import (
 	"amazonpilot/internal/pkg/constants"
 	"amazonpilot/internal/pkg/logger"
 	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/tasks"
 
 	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
 	"github.com/robfig/cron/v3"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -45,13 +47,17 @@ func main() {
 		Addr: envCfg.Redis.Addr,
 		DB:   envCfg.Redis.DB,
 	})
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: envCfg.Redis.Addr,
+		DB:   envCfg.Redis.DB,
+	})
 
 	// 创建cron调度器
 	cronScheduler := cron.New(cron.WithSeconds())
 
-	// 添加产品更新任务 - 根据环境变量配置的间隔执行
+	// 添加产品更新任务 - 根据环境变量配置的间隔检查到期的追踪产品
 	_, err = cronScheduler.AddFunc("@every "+envCfg.Scheduler.ProductUpdateInterval, func() {
-		scheduleProductUpdates(db, asynqClient)
+		scheduleProductUpdates(db, asynqClient, redisClient)
 	})
 	if err != nil {
 		slog.Error("Failed to add cron job", "error", err)
@@ -72,13 +78,17 @@ func main() {
 	slog.Info("Scheduler shutdown complete")
 }
 
-// scheduleProductUpdates 调度所有活跃产品的更新任务
-func scheduleProductUpdates(db *gorm.DB, client *asynq.Client) {
+// scheduleProductUpdates 调度到期 (next_check_at <= now) 的活跃追踪产品的更新任务，并按追踪频率推迟下次检查时间
+func scheduleProductUpdates(db *gorm.DB, client *asynq.Client, rdb *redis.Client) {
+	ctx := context.Background()
+	now := time.Now()
 
-	// 查询所有活跃的追踪产品
+	// 查询到期的活跃追踪产品，next_check_at 为空的旧记录立即调度
 	var trackedProducts []models.TrackedProduct
-	if err := db.Where("is_active = ?", true).Preload("Product").Find(&trackedProducts).Error; err != nil {
-		slog.Error("Failed to fetch tracked products", "error", err)
+	if err := db.Where("is_active = ? AND (next_check_at IS NULL OR next_check_at <= ?)", true, now).
+		Preload("Product").
+		Find(&trackedProducts).Error; err != nil {
+		slog.Error("Failed to fetch due tracked products", "error", err)
 		return
 	}
 
@@ -87,27 +97,23 @@ func scheduleProductUpdates(db *gorm.DB, client *asynq.Client) {
 	// 为每个产品创建更新任务
 	successCount := 0
 	for _, tp := range trackedProducts {
-		// 创建任务payload
-		taskPayload := map[string]interface{}{
-			"product_id":   tp.ProductID,
-			"tracked_id":   tp.ID,
-			"asin":         tp.Product.ASIN,
-			"user_id":      tp.UserID,
-			"scheduled_at": time.Now().Format(time.RFC3339),
-		}
-
-		payloadBytes, err := json.Marshal(taskPayload)
+		// 创建任务并加入队列，任务ID记入追踪产品的最近刷新索引
+		info, err := tasks.EnqueueRefreshTask(ctx, client, rdb, tasks.RefreshProductDataPayload{
+			ProductID:   tp.ProductID,
+			TrackedID:   tp.ID,
+			ASIN:        tp.Product.ASIN,
+			UserID:      tp.UserID,
+			RequestedAt: now.Format(time.RFC3339),
+		})
 		if err != nil {
-			slog.Error("Failed to marshal task payload", "product_id", tp.ProductID, "error", err)
+			slog.Error("Failed to enqueue refresh task", "product_id", tp.ProductID, "asin", tp.Product.ASIN, "error", err)
 			continue
 		}
 
-		// 创建任务并加入队列
-		task := asynq.NewTask("refresh:product:data", payloadBytes)
-		info, err := client.Enqueue(task, asynq.Queue("apify"))
-		if err != nil {
-			slog.Error("Failed to enqueue refresh task", "product_id", tp.ProductID, "asin", tp.Product.ASIN, "error", err)
-			continue
+		// 入队后立即推迟下次检查时间，避免任务执行期间被重复调度
+		if err := db.Model(&models.TrackedProduct{}).Where("id = ?", tp.ID).
+			Update("next_check_at", tasks.NextCheckTime(tp.TrackingFrequency, now)).Error; err != nil {
+			slog.Error("Failed to advance next check time", "tracked_id", tp.ID, "error", err)
 		}
 
 		successCount++
@@ -118,4 +124,4 @@ func scheduleProductUpdates(db *gorm.DB, client *asynq.Client) {
 		"total_products", len(trackedProducts),
 		"successful_tasks", successCount,
 	)
-}
\ No newline at end of file
+}
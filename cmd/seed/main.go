@@ -0,0 +1,245 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"time"
+
+	baseconfig "amazonpilot/internal/pkg/config"
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/database"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/seed"
+	"amazonpilot/internal/pkg/workspace"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 生成数据写入的分区表 (按月分区，分区不存在时由本工具创建)
+var historyTables = []string{
+	"product_price_history",
+	"product_ranking_history",
+	"product_review_history",
+	"product_buybox_history",
+}
+
+// seededNextCheckDelay 生成的追踪记录的下次检查时间距最新数据的间隔
+const seededNextCheckDelay = 100 * 365 * 24 * time.Hour
+
+var (
+	productCount = flag.Int("products", 10, "number of synthetic products")
+	days         = flag.Int("days", 180, "days of history to generate, ending now")
+	interval     = flag.Duration("interval", 24*time.Hour, "interval between records")
+	randomSeed   = flag.Int64("seed", 1, "random seed, the same seed generates the same data")
+	anomalyRate  = flag.Float64("anomaly-rate", 0, "probability of injecting a price/BSR anomaly per record")
+	userEmail    = flag.String("user", "", "track the generated products for this user (email)")
+	currency     = flag.String("currency", "USD", "currency of generated prices")
+	reset        = flag.Bool("reset", true, "delete previously seeded history of the products first")
+	batchSize    = flag.Int("batch", 1000, "insert batch size")
+)
+
+// main 为演示、压测和异常检测调参生成多月的合成历史数据
+//
+//	go run cmd/seed/main.go -products 50 -days 365 -anomaly-rate 0.02 -user demo@example.com
+func main() {
+	flag.Parse()
+	serviceName := constants.ServiceSeed
+
+	// 初始化结构化日志
+	logger.InitStructuredLogger(serviceName)
+
+	// 加载环境变量配置
+	envCfg := baseconfig.MustLoadEnvConfig(serviceName)
+
+	if *productCount <= 0 || *days <= 0 || *interval <= 0 {
+		panic("products, days and interval must be positive")
+	}
+
+	db, err := database.NewConnectionWithDSN(envCfg.Database.DSN, &database.Config{
+		MaxIdleConns:    2,
+		MaxOpenConns:    4,
+		ConnMaxLifetime: 3600,
+	})
+	if err != nil {
+		panic("Failed to connect to database: " + err.Error())
+	}
+
+	var user *models.User
+	var workspaceID string
+	if *userEmail != "" {
+		user = &models.User{}
+		if err := db.Where("email = ?", *userEmail).First(user).Error; err != nil {
+			panic(fmt.Sprintf("Failed to find user %s: %v", *userEmail, err))
+		}
+		// 生成的产品追踪在用户的个人工作区
+		if workspaceID, err = workspace.PersonalWorkspaceID(db, user.ID); err != nil {
+			panic(fmt.Sprintf("Failed to find personal workspace of %s: %v", *userEmail, err))
+		}
+	}
+
+	points := int((time.Duration(*days) * 24 * time.Hour) / *interval)
+	end := time.Now().UTC().Truncate(*interval)
+	cfg := seed.Config{
+		Start:       end.Add(-time.Duration(points-1) * *interval),
+		Points:      points,
+		Interval:    *interval,
+		AnomalyRate: *anomalyRate,
+		Currency:    *currency,
+	}
+
+	if err := ensurePartitions(db, cfg.Start, end); err != nil {
+		panic("Failed to create history partitions: " + err.Error())
+	}
+
+	slog.Info("Seeding synthetic history",
+		"products", *productCount,
+		"points_per_product", points,
+		"start", cfg.Start,
+		"end", end,
+		"seed", *randomSeed,
+		"anomaly_rate", *anomalyRate,
+	)
+
+	generator := seed.NewGenerator(*randomSeed, nil)
+	totalRecords, totalAnomalies := 0, 0
+	for i := 1; i <= *productCount; i++ {
+		profile := generator.Profile(i)
+		records, anomalies, err := seedProduct(db, generator, profile, cfg, user, workspaceID)
+		if err != nil {
+			slog.Error("Failed to seed product", "asin", profile.ASIN, "error", err)
+			continue
+		}
+		totalRecords += records
+		totalAnomalies += anomalies
+		slog.Info("Product seeded", "asin", profile.ASIN, "category", profile.Category, "records", records, "anomalies", anomalies)
+	}
+
+	slog.Info("Seeding completed",
+		"products", *productCount,
+		"records", totalRecords,
+		"anomalies", totalAnomalies,
+	)
+}
+
+// seedProduct 在一个事务中写入单个产品的历史数据、注入的异常事件和最新快照
+func seedProduct(db *gorm.DB, generator *seed.Generator, profile seed.Profile, cfg seed.Config, user *models.User, workspaceID string) (int, int, error) {
+	var records, anomalies int
+	err := db.Transaction(func(tx *gorm.DB) error {
+		product := models.Product{ASIN: profile.ASIN}
+		if err := tx.Where(models.Product{ASIN: profile.ASIN}).
+			Attrs(models.Product{
+				Title:      &profile.Title,
+				Brand:      &profile.Brand,
+				Category:   &profile.Category,
+				DataSource: seed.DataSource,
+			}).
+			FirstOrCreate(&product).Error; err != nil {
+			return err
+		}
+
+		if *reset {
+			if err := deleteSeededHistory(tx, product.ID); err != nil {
+				return err
+			}
+		}
+
+		history := generator.Generate(product.ID, profile, cfg)
+		if err := tx.CreateInBatches(&history.Prices, *batchSize).Error; err != nil {
+			return err
+		}
+		if err := tx.CreateInBatches(&history.Rankings, *batchSize).Error; err != nil {
+			return err
+		}
+		if err := tx.CreateInBatches(&history.Reviews, *batchSize).Error; err != nil {
+			return err
+		}
+		if err := tx.CreateInBatches(&history.BuyBoxes, *batchSize).Error; err != nil {
+			return err
+		}
+		if events := history.AnomalyEvents(product.ID, product.ASIN); len(events) > 0 {
+			if err := tx.CreateInBatches(&events, *batchSize).Error; err != nil {
+				return err
+			}
+		}
+
+		snapshot, ok := history.LatestSnapshot()
+		if ok {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "product_id"}},
+				UpdateAll: true,
+			}).Create(&snapshot).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Product{}).Where("id = ?", product.ID).
+				Update("last_updated_at", snapshot.RecordedAt).Error; err != nil {
+				return err
+			}
+		}
+
+		if user != nil {
+			// 合成产品的 ASIN 在 Amazon 上不存在，下次检查时间设为远期，避免调度器为其调用 Apify
+			nextCheck := snapshot.RecordedAt.Add(seededNextCheckDelay)
+			tracked := models.TrackedProduct{}
+			if err := tx.Where(models.TrackedProduct{WorkspaceID: workspaceID, ProductID: product.ID}).
+				Attrs(models.TrackedProduct{UserID: user.ID, IsActive: true, TrackingFrequency: "daily", LastCheckedAt: &snapshot.RecordedAt, NextCheckAt: &nextCheck}).
+				FirstOrCreate(&tracked).Error; err != nil {
+				return err
+			}
+		}
+
+		records = len(history.Prices)
+		anomalies = len(history.Anomalies)
+		return nil
+	})
+	return records, anomalies, err
+}
+
+// deleteSeededHistory 删除产品之前生成的数据，不影响真实抓取的数据
+func deleteSeededHistory(tx *gorm.DB, productID string) error {
+	for _, table := range historyTables {
+		if err := tx.Table(table).Where("product_id = ? AND data_source = ?", productID, seed.DataSource).
+			Delete(nil).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Where("product_id = ? AND metadata->>'source' = ?", productID, seed.DataSource).
+		Delete(&models.AnomalyEvent{}).Error
+}
+
+// ensurePartitions 为 [start, end] 覆盖的月份创建历史表分区，与 create_monthly_partitions() 的命名和索引一致
+// 表未分区 (例如本地 AutoMigrate 创建) 时跳过
+func ensurePartitions(db *gorm.DB, start, end time.Time) error {
+	for _, table := range historyTables {
+		var partitioned bool
+		if err := db.Raw(`SELECT EXISTS (
+			SELECT 1 FROM pg_partitioned_table pt JOIN pg_class c ON c.oid = pt.partrelid WHERE c.relname = ?
+		)`, table).Scan(&partitioned).Error; err != nil {
+			return err
+		}
+		if !partitioned {
+			continue
+		}
+
+		month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+		for !month.After(end) {
+			next := month.AddDate(0, 1, 0)
+			name := fmt.Sprintf("%s_%s", table, month.Format("2006_01"))
+			statements := []string{
+				fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %q PARTITION OF %q FOR VALUES FROM ('%s') TO ('%s')`,
+					name, table, month.Format("2006-01-02"), next.Format("2006-01-02")),
+				fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %q ON %q (product_id)`, "idx_"+name+"_product_id", name),
+				fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %q ON %q (recorded_at)`, "idx_"+name+"_recorded_at", name),
+			}
+			for _, statement := range statements {
+				if err := db.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			month = next
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"log/slog"
 	"os"
@@ -9,6 +10,8 @@ import (
 
 	baseconfig "amazonpilot/internal/pkg/config"
 	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/estimation"
+	"amazonpilot/internal/pkg/fx"
 	"amazonpilot/internal/pkg/logger"
 	"amazonpilot/internal/pkg/tasks"
 
@@ -52,17 +55,36 @@ func main() {
 		},
 	})
 
+	// 加载汇率数据 (报告中的价格换算)
+	fxConverter, err := fx.LoadConverter(envCfg.FX.RatesFile)
+	if err != nil {
+		panic("Failed to load fx rates: " + err.Error())
+	}
+	fx.StartProviderSync(context.Background(), fxConverter, envCfg.FX.ProviderURL)
+
+	// 加载 BSR -> 销量估算曲线
+	salesModel, err := estimation.LoadModel(envCfg.Estimation.CurvesFile)
+	if err != nil {
+		panic("Failed to load sales curves: " + err.Error())
+	}
+
 	// 创建任务处理器
 	processor := tasks.NewApifyTaskProcessor(
 		envCfg.Database.DSN,
 		envCfg.APIKeys.ApifyToken,
 		envCfg.Redis.Addr,
+		fxConverter,
 	)
+	processor.SetOffersActor(envCfg.Worker.OffersActor)
+	processor.SetSalesModel(salesModel)
+	processor.SetExportConfig(envCfg.Export.Dir, envCfg.Export.Retention)
 
 	// 注册任务处理函数
 	mux := asynq.NewServeMux()
 	mux.HandleFunc(tasks.TypeRefreshProductData, processor.HandleRefreshProductData)
 	mux.HandleFunc(tasks.TypeGenerateReport, processor.HandleGenerateReport)
+	mux.HandleFunc(tasks.TypeBulkImport, processor.HandleBulkImport)
+	mux.HandleFunc(tasks.TypeHistoryExport, processor.HandleHistoryExport)
 
 	// 优雅关闭处理
 	go func() {
@@ -0,0 +1,27 @@
+-- 007_add_product_variations.sql
+-- 支持 Amazon 父/子变体分组 (颜色、尺寸等子ASIN共享评论和评分)
+
+-- 父ASIN：actor 返回 parentAsin 时直接使用，否则使用变体家族中最小的子ASIN作为分组键
+ALTER TABLE products
+ADD COLUMN IF NOT EXISTS parent_asin VARCHAR(10);
+
+-- 当前ASIN的变体属性，例如 {"color_name": "Black", "size_name": "M"}
+ALTER TABLE products
+ADD COLUMN IF NOT EXISTS variation_attributes JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_products_parent_asin
+ON products(parent_asin)
+WHERE parent_asin IS NOT NULL;
+
+-- 追踪父产品时是否自动追踪发现的子变体
+ALTER TABLE tracked_products
+ADD COLUMN IF NOT EXISTS track_variations BOOLEAN DEFAULT false;
+
+COMMENT ON COLUMN products.parent_asin IS '变体父ASIN，同一家族的子ASIN共享该值';
+COMMENT ON COLUMN products.variation_attributes IS '变体属性 (variationName -> value)';
+COMMENT ON COLUMN tracked_products.track_variations IS '刷新时自动追踪新发现的子变体';
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('007', NOW())
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,25 @@
+-- 008_add_product_content_snapshots.sql
+-- 记录产品Listing内容（标题、描述、五点描述、图片）的历史版本，用于检测竞品改版
+
+CREATE TABLE IF NOT EXISTS product_content_snapshots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    title TEXT,
+    description TEXT,
+    bullet_points JSONB,
+    images JSONB,
+    content_hash VARCHAR(64) NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    data_source VARCHAR(50) DEFAULT 'apify'
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_content_snapshots_product_recorded
+ON product_content_snapshots(product_id, recorded_at DESC);
+
+COMMENT ON TABLE product_content_snapshots IS 'Listing内容快照，仅在内容变化时写入新版本';
+COMMENT ON COLUMN product_content_snapshots.content_hash IS '标题/描述/五点描述/图片的SHA-256摘要，用于快速判断内容是否变化';
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('008', NOW())
+ON CONFLICT (version) DO NOTHING;
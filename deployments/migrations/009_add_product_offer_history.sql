@@ -0,0 +1,38 @@
+-- 009_add_product_offer_history.sql
+-- 记录每次刷新时 Listing 上的全部卖家报价，用于卖家数量、Buy Box 占有率和最低报价分析
+
+CREATE TABLE IF NOT EXISTS product_offer_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    seller_name VARCHAR(255) NOT NULL,
+    seller_id VARCHAR(50),
+    price DECIMAL(10,2) NOT NULL,
+    shipping_price DECIMAL(10,2) DEFAULT 0,
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    condition VARCHAR(50) DEFAULT 'new',
+    is_fba BOOLEAN DEFAULT false,
+    is_prime BOOLEAN DEFAULT false,
+    is_buy_box_winner BOOLEAN DEFAULT false,
+    recorded_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    data_source VARCHAR(50) DEFAULT 'apify'
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_offer_history_product_recorded
+ON product_offer_history(product_id, recorded_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_product_offer_history_seller_id
+ON product_offer_history(seller_id)
+WHERE seller_id IS NOT NULL;
+
+-- Buy Box 赢家的卖家ID (sellerId)，卖家名称可能变化，ID 更稳定
+ALTER TABLE product_buybox_history
+ADD COLUMN IF NOT EXISTS winner_seller_id VARCHAR(50);
+
+COMMENT ON TABLE product_offer_history IS '卖家报价历史，同一次刷新的报价共享 recorded_at';
+COMMENT ON COLUMN product_offer_history.is_buy_box_winner IS '该报价是否赢得 Buy Box';
+COMMENT ON COLUMN product_buybox_history.winner_seller_id IS 'Buy Box 赢家的卖家ID';
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('009', NOW())
+ON CONFLICT (version) DO NOTHING;
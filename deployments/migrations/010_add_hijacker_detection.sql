@@ -0,0 +1,35 @@
+-- 010_add_hijacker_detection.sql
+-- 跟卖检测：用户声明自有卖家账号，并可为每个追踪产品配置授权卖家白名单
+-- 跟卖事件记录所属用户，不向其他追踪同一产品的用户展示
+
+CREATE TABLE IF NOT EXISTS user_seller_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    seller_name VARCHAR(255) NOT NULL,
+    seller_id VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_seller_accounts_user_id
+ON user_seller_accounts(user_id);
+
+-- 授权卖家白名单，例如 [{"seller_name": "AnkerDirect", "seller_id": "A294P4X9EWVXLJ"}]
+ALTER TABLE tracked_products
+ADD COLUMN IF NOT EXISTS authorized_sellers JSONB;
+
+-- 跟卖事件依赖用户私有的卖家配置，只属于产生它的用户；user_id 为空的事件对所有追踪该产品的用户可见
+ALTER TABLE product_anomaly_events
+ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_product_anomaly_events_user
+ON product_anomaly_events(user_id, product_id, created_at DESC)
+WHERE user_id IS NOT NULL;
+
+COMMENT ON TABLE user_seller_accounts IS '用户自有卖家账号，跟卖检测时视为授权卖家';
+COMMENT ON COLUMN tracked_products.authorized_sellers IS '该产品的授权卖家白名单 (seller_name/seller_id)';
+COMMENT ON COLUMN product_anomaly_events.user_id IS '私有事件所属用户，为空时对所有追踪该产品的用户可见';
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('010', NOW())
+ON CONFLICT (version) DO NOTHING;
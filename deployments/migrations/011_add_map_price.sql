@@ -0,0 +1,17 @@
+-- 011_add_map_price.sql
+-- MAP (最低广告价) 监控：每个追踪产品可配置 MAP，低于 MAP 的报价记录为 map_violation 事件
+
+ALTER TABLE tracked_products
+ADD COLUMN IF NOT EXISTS map_price DECIMAL(10,2);
+
+-- MAP 违规报告按追踪记录和时间查询事件
+CREATE INDEX IF NOT EXISTS idx_product_anomaly_events_map_violation
+ON product_anomaly_events(product_id, created_at DESC)
+WHERE event_type = 'map_violation';
+
+COMMENT ON COLUMN tracked_products.map_price IS '最低广告价 (MAP)，为空时不监控';
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('011', NOW())
+ON CONFLICT (version) DO NOTHING;
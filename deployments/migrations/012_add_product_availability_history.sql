@@ -0,0 +1,25 @@
+-- 012_add_product_availability_history.sql
+-- 将 warehouseAvailability 文本解析为标准化库存状态并按时间序列保存
+
+CREATE TABLE IF NOT EXISTS product_availability_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    state VARCHAR(20) NOT NULL,
+    stock_quantity INTEGER,
+    availability_text VARCHAR(255),
+    recorded_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    data_source VARCHAR(50) DEFAULT 'apify',
+    CONSTRAINT product_availability_history_state_check
+        CHECK (state IN ('in_stock', 'low_stock', 'out_of_stock', 'back_order', 'unavailable', 'unknown'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_availability_history_product_recorded
+ON product_availability_history(product_id, recorded_at DESC);
+
+COMMENT ON TABLE product_availability_history IS '库存状态历史，状态变化时产生 out_of_stock / back_in_stock 事件';
+COMMENT ON COLUMN product_availability_history.stock_quantity IS '低库存时页面显示的剩余数量 (Only N left in stock)';
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('012', NOW())
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,21 @@
+-- 013_add_price_history_deals.sql
+-- 价格历史记录标价、秒杀/活动类型和优惠券，用于折扣与促销分析
+-- product_price_history 为分区表，在父表上添加的列会自动应用到所有分区
+
+ALTER TABLE product_price_history
+ADD COLUMN IF NOT EXISTS list_price DECIMAL(10,2);
+
+ALTER TABLE product_price_history
+ADD COLUMN IF NOT EXISTS deal_type VARCHAR(50);
+
+ALTER TABLE product_price_history
+ADD COLUMN IF NOT EXISTS coupon_text VARCHAR(255);
+
+COMMENT ON COLUMN product_price_history.list_price IS '标价 (retailPrice)，discount_percentage 相对该价格计算';
+COMMENT ON COLUMN product_price_history.deal_type IS '活动类型: lightning_deal, prime_day, limited_time_deal, deal_of_the_day, deal';
+COMMENT ON COLUMN product_price_history.coupon_text IS '优惠券描述，例如 Save 10% with coupon';
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('013', NOW())
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,13 @@
+-- 014_add_ranking_sales_estimate.sql
+-- 排名历史记录按类目销量曲线估算的日销量，用于销量和收入估算
+-- product_ranking_history 为分区表，在父表上添加的列会自动应用到所有分区
+
+ALTER TABLE product_ranking_history
+ADD COLUMN IF NOT EXISTS est_units_per_day DECIMAL(12,2);
+
+COMMENT ON COLUMN product_ranking_history.est_units_per_day IS '根据 BSR 和类目幂律曲线估算的日销量 (units/day)';
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('014', NOW())
+ON CONFLICT (version) DO NOTHING;
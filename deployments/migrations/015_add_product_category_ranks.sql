@@ -0,0 +1,26 @@
+-- 015_add_product_category_ranks.sql
+-- 每次排名快照保存根类目和所有小类目 (小類別) 的BSR排名
+-- product_ranking_history 为分区表，无法直接建立外键，通过 ranking_history_id 关联
+
+CREATE TABLE IF NOT EXISTS product_category_ranks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ranking_history_id UUID NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    category VARCHAR(255) NOT NULL,
+    rank INTEGER NOT NULL,
+    is_root BOOLEAN DEFAULT false,
+    recorded_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_category_ranks_product_category_recorded
+ON product_category_ranks(product_id, category, recorded_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_product_category_ranks_ranking_history_id
+ON product_category_ranks(ranking_history_id);
+
+COMMENT ON TABLE product_category_ranks IS '排名快照中的类目排名，is_root 为根类目 (与 product_ranking_history.bsr_rank 一致)';
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('015', NOW())
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,55 @@
+-- 016_add_tags_and_import_jobs.sql
+-- 追踪产品标签 (多对多) 以及批量导入任务
+
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(20),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name
+ON tags(user_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS tracked_product_tags (
+    tracked_product_id UUID NOT NULL REFERENCES tracked_products(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    PRIMARY KEY (tracked_product_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tracked_product_tags_tag_id
+ON tracked_product_tags(tag_id);
+
+CREATE TABLE IF NOT EXISTS tracking_import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    format VARCHAR(10),
+    total_rows INTEGER DEFAULT 0,
+    processed_rows INTEGER DEFAULT 0,
+    created_rows INTEGER DEFAULT 0,
+    existing_rows INTEGER DEFAULT 0,
+    failed_rows INTEGER DEFAULT 0,
+    rows JSONB,
+    results JSONB,
+    error_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT tracking_import_jobs_status_check
+        CHECK (status IN ('queued', 'processing', 'completed', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_tracking_import_jobs_user_created
+ON tracking_import_jobs(user_id, created_at DESC);
+
+COMMENT ON TABLE tags IS '用户自定义标签，同一用户下名称不区分大小写唯一';
+COMMENT ON TABLE tracking_import_jobs IS '批量导入追踪产品任务，results 保存逐行导入结果';
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('016', NOW())
+ON CONFLICT (version) DO NOTHING;
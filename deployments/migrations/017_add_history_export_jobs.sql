@@ -0,0 +1,36 @@
+-- 017_add_history_export_jobs.sql
+-- 历史数据导出任务 (大范围导出在后台生成文件后下载)
+
+CREATE TABLE IF NOT EXISTS history_export_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    format VARCHAR(10) NOT NULL,
+    params JSONB NOT NULL,
+    file_name VARCHAR(255),
+    file_size BIGINT DEFAULT 0,
+    row_count INTEGER DEFAULT 0,
+    error_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT history_export_jobs_status_check
+        CHECK (status IN ('queued', 'processing', 'completed', 'failed', 'expired')),
+    CONSTRAINT history_export_jobs_format_check
+        CHECK (format IN ('csv', 'ndjson', 'xlsx'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_history_export_jobs_user_created
+ON history_export_jobs(user_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_history_export_jobs_expires
+ON history_export_jobs(expires_at)
+WHERE status = 'completed';
+
+COMMENT ON TABLE history_export_jobs IS '历史数据导出任务，文件保存在 EXPORT_DIR 下，过期后删除';
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('017', NOW())
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,36 @@
+-- 018_add_tracked_list_indexes.sql
+-- 追踪产品列表的筛选、排序和搜索索引
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- 按用户筛选状态并按创建时间排序 (默认排序)
+CREATE INDEX IF NOT EXISTS idx_tracked_products_user_active_created
+ON tracked_products(user_id, is_active, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_tracked_products_user_created
+ON tracked_products(user_id, created_at DESC);
+
+-- 最新价格、24小时前价格和最新排名的查询 (分区表上创建的索引会自动建立到每个分区)
+CREATE INDEX IF NOT EXISTS idx_product_price_history_product_recorded
+ON product_price_history(product_id, recorded_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_product_ranking_history_product_recorded
+ON product_ranking_history(product_id, recorded_at DESC);
+
+-- 标题、品牌、ASIN 和别名的模糊搜索 (ILIKE '%keyword%')
+CREATE INDEX IF NOT EXISTS idx_products_title_trgm
+ON products USING gin (title gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_products_brand_trgm
+ON products USING gin (brand gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_products_asin_trgm
+ON products USING gin (asin gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_tracked_products_alias_trgm
+ON tracked_products USING gin (alias gin_trgm_ops);
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('018', NOW())
+ON CONFLICT (version) DO NOTHING;
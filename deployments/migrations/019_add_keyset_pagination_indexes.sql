@@ -0,0 +1,22 @@
+-- 019_add_keyset_pagination_indexes.sql
+-- 游标分页 (键集分页) 使用的 (时间, id) 复合索引
+
+CREATE INDEX IF NOT EXISTS idx_tracked_products_user_created_id
+ON tracked_products(user_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_product_anomaly_events_created_id
+ON product_anomaly_events(created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_product_content_snapshots_product_recorded_id
+ON product_content_snapshots(product_id, recorded_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_competitor_analysis_groups_user_created_id
+ON competitor_analysis_groups(user_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_optimization_analyses_user_started_id
+ON optimization_analyses(user_id, started_at DESC, id DESC);
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('019', NOW())
+ON CONFLICT (version) DO NOTHING;
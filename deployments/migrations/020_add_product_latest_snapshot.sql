@@ -0,0 +1,85 @@
+-- 020_add_product_latest_snapshot.sql
+-- 每个产品一行的最新数据快照，由 worker 在刷新事务中更新
+-- 列表、详情和报告通过一次关联读取最新数据，不再逐个查询分区历史表
+
+CREATE TABLE IF NOT EXISTS product_latest_snapshot (
+    product_id UUID PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    price DECIMAL(10,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    buy_box_price DECIMAL(10,2),
+    bsr_rank INTEGER,
+    bsr_category VARCHAR(255),
+    rating DECIMAL(3,2),
+    review_count INTEGER DEFAULT 0,
+    availability_state VARCHAR(20) NOT NULL DEFAULT 'unknown',
+    availability_text VARCHAR(255),
+    price_change_24h DECIMAL(10,2),
+    bsr_change_24h INTEGER,
+    rating_change_24h DECIMAL(4,2),
+    review_count_change_24h INTEGER,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+-- 按最新数据筛选和排序追踪列表
+CREATE INDEX IF NOT EXISTS idx_product_latest_snapshot_price
+ON product_latest_snapshot(price);
+
+CREATE INDEX IF NOT EXISTS idx_product_latest_snapshot_rating
+ON product_latest_snapshot(rating);
+
+COMMENT ON TABLE product_latest_snapshot IS '产品最新数据快照，与刷新写入的历史记录在同一事务中更新';
+COMMENT ON COLUMN product_latest_snapshot.price_change_24h IS '相对24小时前最后一个价格的变化百分比，24小时前没有数据时为空';
+COMMENT ON COLUMN product_latest_snapshot.bsr_change_24h IS '相对24小时前最后一个排名的变化 (正数表示排名下降)';
+
+-- 用现有历史数据回填
+INSERT INTO product_latest_snapshot (
+    product_id, price, currency, buy_box_price, bsr_rank, bsr_category, rating, review_count,
+    availability_state, availability_text, price_change_24h, bsr_change_24h, rating_change_24h,
+    review_count_change_24h, recorded_at
+)
+SELECT
+    p.id,
+    lp.price,
+    lp.currency,
+    lp.buy_box_price,
+    lr.bsr_rank,
+    lr.category,
+    lr.rating,
+    COALESCE(lr.review_count, 0),
+    COALESCE(la.state, 'unknown'),
+    la.availability_text,
+    CASE WHEN dp.price > 0 THEN ROUND((lp.price - dp.price) / dp.price * 100, 2) END,
+    lr.bsr_rank - dr.bsr_rank,
+    lr.rating - dr.rating,
+    lr.review_count - dr.review_count,
+    GREATEST(lp.recorded_at, COALESCE(lr.recorded_at, lp.recorded_at))
+FROM products p
+JOIN LATERAL (
+    SELECT price, currency, buy_box_price, recorded_at FROM product_price_history
+    WHERE product_id = p.id ORDER BY recorded_at DESC LIMIT 1
+) lp ON true
+LEFT JOIN LATERAL (
+    SELECT bsr_rank, category, rating, review_count, recorded_at FROM product_ranking_history
+    WHERE product_id = p.id ORDER BY recorded_at DESC LIMIT 1
+) lr ON true
+LEFT JOIN LATERAL (
+    SELECT state, availability_text FROM product_availability_history
+    WHERE product_id = p.id ORDER BY recorded_at DESC LIMIT 1
+) la ON true
+LEFT JOIN LATERAL (
+    SELECT price FROM product_price_history
+    WHERE product_id = p.id AND recorded_at <= lp.recorded_at - INTERVAL '24 hours'
+    ORDER BY recorded_at DESC LIMIT 1
+) dp ON true
+LEFT JOIN LATERAL (
+    SELECT bsr_rank, rating, review_count FROM product_ranking_history
+    WHERE product_id = p.id AND recorded_at <= lr.recorded_at - INTERVAL '24 hours'
+    ORDER BY recorded_at DESC LIMIT 1
+) dr ON true
+ON CONFLICT (product_id) DO NOTHING;
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('020', NOW())
+ON CONFLICT (version) DO NOTHING;
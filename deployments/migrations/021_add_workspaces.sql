@@ -0,0 +1,142 @@
+-- 021_add_workspaces.sql
+-- 团队工作区：追踪产品、标签、导入导出任务、卖家账号和分析组归属工作区，成员按角色 (owner/editor/viewer) 共享
+-- 已有用户各自获得一个个人工作区，原有数据迁入其中；user_id 保留为创建人
+
+CREATE TABLE IF NOT EXISTS workspaces (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    is_personal BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_workspaces_owner_id
+ON workspaces(owner_id);
+
+-- 每个用户只有一个个人工作区
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workspaces_personal_owner
+ON workspaces(owner_id) WHERE is_personal;
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'viewer',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    UNIQUE (workspace_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workspace_members_user_id
+ON workspace_members(user_id);
+
+CREATE TABLE IF NOT EXISTS workspace_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'viewer',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    invited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_workspace_invitations_workspace_id
+ON workspace_invitations(workspace_id);
+
+CREATE INDEX IF NOT EXISTS idx_workspace_invitations_email
+ON workspace_invitations(LOWER(email)) WHERE status = 'pending';
+
+-- 为已有用户创建个人工作区及所有者成员关系
+INSERT INTO workspaces (name, owner_id, is_personal)
+SELECT COALESCE(NULLIF(u.company_name, ''), 'Personal'), u.id, true
+FROM users u
+WHERE NOT EXISTS (
+    SELECT 1 FROM workspaces w WHERE w.owner_id = u.id AND w.is_personal
+);
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT w.id, w.owner_id, 'owner'
+FROM workspaces w
+WHERE w.is_personal
+ON CONFLICT (workspace_id, user_id) DO NOTHING;
+
+-- 资源表增加 workspace_id，并按 user_id 回填到个人工作区
+ALTER TABLE tracked_products
+ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE tags
+ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE tracking_import_jobs
+ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE history_export_jobs
+ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE user_seller_accounts
+ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE competitor_analysis_groups
+ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE optimization_analyses
+ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE tracked_products t SET workspace_id = w.id
+FROM workspaces w WHERE w.owner_id = t.user_id AND w.is_personal AND t.workspace_id IS NULL;
+UPDATE tags t SET workspace_id = w.id
+FROM workspaces w WHERE w.owner_id = t.user_id AND w.is_personal AND t.workspace_id IS NULL;
+UPDATE tracking_import_jobs t SET workspace_id = w.id
+FROM workspaces w WHERE w.owner_id = t.user_id AND w.is_personal AND t.workspace_id IS NULL;
+UPDATE history_export_jobs t SET workspace_id = w.id
+FROM workspaces w WHERE w.owner_id = t.user_id AND w.is_personal AND t.workspace_id IS NULL;
+UPDATE user_seller_accounts t SET workspace_id = w.id
+FROM workspaces w WHERE w.owner_id = t.user_id AND w.is_personal AND t.workspace_id IS NULL;
+UPDATE competitor_analysis_groups t SET workspace_id = w.id
+FROM workspaces w WHERE w.owner_id = t.user_id AND w.is_personal AND t.workspace_id IS NULL;
+UPDATE optimization_analyses t SET workspace_id = w.id
+FROM workspaces w WHERE w.owner_id = t.user_id AND w.is_personal AND t.workspace_id IS NULL;
+
+ALTER TABLE tracked_products ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE tags ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE tracking_import_jobs ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE history_export_jobs ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE user_seller_accounts ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE competitor_analysis_groups ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE optimization_analyses ALTER COLUMN workspace_id SET NOT NULL;
+
+-- 唯一约束从用户维度改为工作区维度
+ALTER TABLE tracked_products DROP CONSTRAINT IF EXISTS tracked_products_user_product_unique;
+ALTER TABLE tracked_products DROP CONSTRAINT IF EXISTS tracked_products_workspace_product_unique;
+ALTER TABLE tracked_products
+ADD CONSTRAINT tracked_products_workspace_product_unique UNIQUE (workspace_id, product_id);
+
+DROP INDEX IF EXISTS idx_tags_user_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_workspace_name
+ON tags(workspace_id, LOWER(name));
+
+-- 工作区维度的列表查询
+CREATE INDEX IF NOT EXISTS idx_tracked_products_workspace_created_id
+ON tracked_products(workspace_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_tracking_import_jobs_workspace_id
+ON tracking_import_jobs(workspace_id);
+
+CREATE INDEX IF NOT EXISTS idx_history_export_jobs_workspace_id
+ON history_export_jobs(workspace_id);
+
+CREATE INDEX IF NOT EXISTS idx_user_seller_accounts_workspace_id
+ON user_seller_accounts(workspace_id);
+
+CREATE INDEX IF NOT EXISTS idx_competitor_analysis_groups_workspace_created_id
+ON competitor_analysis_groups(workspace_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_optimization_analyses_workspace_started_id
+ON optimization_analyses(workspace_id, started_at DESC, id DESC);
+
+COMMENT ON TABLE workspaces IS '团队工作区，每个用户注册时自动创建一个个人工作区';
+COMMENT ON TABLE workspace_members IS '工作区成员及角色 (owner/editor/viewer)';
+COMMENT ON TABLE workspace_invitations IS '按邮箱发出的工作区邀请，7天内有效';
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('021', NOW())
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,26 @@
+-- 022_scope_anomaly_events_to_workspace.sql
+-- 跟卖、MAP 违规等依赖工作区私有配置 (自有卖家、授权白名单、MAP 价格) 的异常事件只属于产生它的工作区
+-- workspace_id 为空的事件 (价格、BSR、库存等) 对所有追踪该产品的工作区可见，取代按用户划分的 user_id
+
+ALTER TABLE product_anomaly_events
+ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+
+-- 按 metadata 中的追踪记录回填已有的跟卖和 MAP 违规事件
+UPDATE product_anomaly_events ae
+SET workspace_id = tp.workspace_id
+FROM tracked_products tp
+WHERE ae.workspace_id IS NULL
+  AND ae.event_type IN ('hijacker_detected', 'map_violation')
+  AND tp.id::text = ae.metadata->>'tracked_id';
+
+CREATE INDEX IF NOT EXISTS idx_product_anomaly_events_workspace
+ON product_anomaly_events(workspace_id, product_id, created_at DESC)
+WHERE workspace_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_product_anomaly_events_user;
+ALTER TABLE product_anomaly_events DROP COLUMN IF EXISTS user_id;
+
+-- 记录迁移版本
+INSERT INTO schema_migrations (version, executed_at)
+VALUES ('022', NOW())
+ON CONFLICT (version) DO NOTHING;
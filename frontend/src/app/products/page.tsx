@@ -7,7 +7,7 @@ import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/com
 import { Badge } from '@/components/ui/badge'
 import { Table, TableBody, TableCell, TableHead, TableHeader, TableRow } from '@/components/ui/table'
 import { Dialog, DialogContent, DialogDescription, DialogHeader, DialogTitle, DialogTrigger } from '@/components/ui/dialog'
-import { Loader2, RefreshCw, Trash2, Search, List, Package, BarChart3, Home, Star, Plus, AlertTriangle, TestTube } from 'lucide-react'
+import { Loader2, RefreshCw, Trash2, Search, List, Package, BarChart3, Home, Star, Plus, AlertTriangle } from 'lucide-react'
 import { Input } from '@/components/ui/input'
 import { Label } from '@/components/ui/label'
 import { useToast } from '@/hooks/use-toast'
@@ -40,10 +40,6 @@ export default function ProductsPage() {
   const [selectedProduct, setSelectedProduct] = useState<TrackedProduct | null>(null)
   const [historyData, setHistoryData] = useState<any>(null)
   const [loadingHistory, setLoadingHistory] = useState(false)
-  const [mockPriceDialogOpen, setMockPriceDialogOpen] = useState(false)
-  const [selectedTrackedId, setSelectedTrackedId] = useState<string>('')
-  const [mockPrice, setMockPrice] = useState<string>('')
-  const [addingMockPrice, setAddingMockPrice] = useState(false)
   const router = useRouter()
   const { toast } = useToast()
 
@@ -164,41 +160,6 @@ export default function ProductsPage() {
     }
   }
 
-  const openMockPriceDialog = (trackedId: string) => {
-    setSelectedTrackedId(trackedId)
-    setMockPrice('')
-    setMockPriceDialogOpen(true)
-  }
-
-  const addMockPriceHistory = async () => {
-    if (!mockPrice || !selectedTrackedId) return
-
-    setAddingMockPrice(true)
-    try {
-      await productAPI.addMockPriceHistory(selectedTrackedId, {
-        price: parseFloat(mockPrice),
-        currency: 'USD'
-      })
-
-      toast({
-        title: "测试价格已添加",
-        description: `已添加测试价格 $${mockPrice}，现在可以执行刷新来模拟异常检测`,
-      })
-
-      setMockPriceDialogOpen(false)
-      setMockPrice('')
-      setSelectedTrackedId('')
-    } catch (error: any) {
-      toast({
-        title: "添加失败",
-        description: error.response?.data?.message || "添加测试价格失败",
-        variant: "destructive"
-      })
-    } finally {
-      setAddingMockPrice(false)
-    }
-  }
-
   const onSubmit = async (data: AddTrackingForm) => {
     setSubmitting(true)
     try {
@@ -550,15 +511,6 @@ export default function ProductsPage() {
                                   <RefreshCw className="w-4 h-4" />
                                 )}
                               </Button>
-                              <Button
-                                size="sm"
-                                variant="outline"
-                                onClick={() => openMockPriceDialog(product.id)}
-                                className="text-orange-600 border-orange-200 hover:bg-orange-50"
-                                title="添加测试价格（用于异常检测测试）"
-                              >
-                                <TestTube className="w-4 h-4" />
-                              </Button>
                               <Button
                                 size="sm"
                                 variant="destructive"
@@ -1165,61 +1117,6 @@ export default function ProductsPage() {
           {renderContent()}
         </div>
       </div>
-
-      {/* 测试价格对话框 */}
-      <Dialog open={mockPriceDialogOpen} onOpenChange={setMockPriceDialogOpen}>
-        <DialogContent>
-          <DialogHeader>
-            <DialogTitle>添加测试价格</DialogTitle>
-            <DialogDescription>
-              为产品添加一个测试价格历史记录，用于模拟异常检测场景。
-              建议设置与当前价格差异较大的值（超过10%变化）。
-            </DialogDescription>
-          </DialogHeader>
-          <div className="space-y-4">
-            <div className="space-y-2">
-              <Label htmlFor="mock_price">测试价格 (USD)</Label>
-              <Input
-                id="mock_price"
-                type="number"
-                step="0.01"
-                placeholder="例如: 99.99"
-                value={mockPrice}
-                onChange={(e) => setMockPrice(e.target.value)}
-              />
-              <p className="text-xs text-muted-foreground">
-                价格变动超过阈值时会触发异常检测。建议输入与当前价格相差10%以上的价格。
-              </p>
-            </div>
-            <div className="flex gap-3">
-              <Button
-                onClick={addMockPriceHistory}
-                disabled={addingMockPrice || !mockPrice}
-                className="flex-1"
-              >
-                {addingMockPrice ? (
-                  <>
-                    <Loader2 className="w-4 h-4 mr-2 animate-spin" />
-                    添加中...
-                  </>
-                ) : (
-                  <>
-                    <TestTube className="w-4 h-4 mr-2" />
-                    添加测试价格
-                  </>
-                )}
-              </Button>
-              <Button
-                variant="outline"
-                onClick={() => setMockPriceDialogOpen(false)}
-                disabled={addingMockPrice}
-              >
-                取消
-              </Button>
-            </div>
-          </div>
-        </DialogContent>
-      </Dialog>
     </div>
   )
 }
\ No newline at end of file
@@ -17,6 +17,11 @@ api.interceptors.request.use(
     if (token) {
       config.headers.Authorization = `Bearer ${token}`
     }
+    // 当前选择的工作区，缺省时后端使用个人工作区
+    const workspaceId = Cookies.get('workspace_id')
+    if (workspaceId) {
+      config.headers['X-Workspace-ID'] = workspaceId
+    }
     return config
   },
   (error) => {
@@ -130,8 +130,4 @@ export const productAPI = {
   // 获取异常变化事件
   getAnomalyEvents: (params?: GetAnomalyEventsRequest): Promise<GetAnomalyEventsResponse> =>
     api.get('/product/products/anomaly-events', { params }).then(res => res.data),
-
-  // 添加模拟价格历史（测试用）
-  addMockPriceHistory: (trackedId: string, data: { price: number; currency?: string }) =>
-    api.post(`/product/products/tracked/${trackedId}/add-mock-price-history`, data).then(res => res.data),
 }
\ No newline at end of file
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+
+	"amazonpilot/internal/auth/logic"
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/utils"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func acceptInvitationHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.AcceptInvitationRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			utils.HandleError(w, err)
+			return
+		}
+
+		l := logic.NewAcceptInvitationLogic(r.Context(), svcCtx)
+		resp, err := l.AcceptInvitation(&req)
+		if err != nil {
+			utils.HandleError(w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
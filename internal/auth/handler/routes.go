@@ -60,6 +60,66 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 					Path:    "/users/profile",
 					Handler: updateProfileHandler(serverCtx),
 				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/users/invitations",
+					Handler: listMyInvitationsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/users/invitations/:invitation_id/accept",
+					Handler: acceptInvitationHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/workspaces",
+					Handler: listWorkspacesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/workspaces",
+					Handler: createWorkspaceHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/workspaces/:workspace_id",
+					Handler: updateWorkspaceHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/workspaces/:workspace_id",
+					Handler: deleteWorkspaceHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/workspaces/:workspace_id/members",
+					Handler: listWorkspaceMembersHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/workspaces/:workspace_id/members/:user_id",
+					Handler: updateWorkspaceMemberHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/workspaces/:workspace_id/members/:user_id",
+					Handler: removeWorkspaceMemberHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/workspaces/:workspace_id/invitations",
+					Handler: createInvitationHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/workspaces/:workspace_id/invitations",
+					Handler: listInvitationsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/workspaces/:workspace_id/invitations/:invitation_id",
+					Handler: revokeInvitationHandler(serverCtx),
+				},
 			}...,
 		),
 		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
@@ -0,0 +1,94 @@
+package logic
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type AcceptInvitationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewAcceptInvitationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AcceptInvitationLogic {
+	return &AcceptInvitationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// AcceptInvitation 接受发送到当前用户邮箱的邀请，以邀请中的角色加入工作区
+func (l *AcceptInvitationLogic) AcceptInvitation(req *types.AcceptInvitationRequest) (resp *types.Workspace, err error) {
+	user, err := currentUser(l.ctx, l.svcCtx.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	var invitation models.WorkspaceInvitation
+	if err := l.svcCtx.DB.Where("id = ? AND status = ?", req.InvitationID, workspace.InvitationPending).
+		First(&invitation).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound
+		}
+		utils.LogError(l.ctx, "Failed to load workspace invitation", "error", err, "invitation_id", req.InvitationID)
+		return nil, errors.ErrInternalServer
+	}
+	// 发给其他邮箱的邀请对当前用户不可见
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		return nil, errors.ErrNotFound
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		return nil, errors.NewBadRequestError("Invitation has expired")
+	}
+
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		member := models.WorkspaceMember{
+			WorkspaceID: invitation.WorkspaceID,
+			UserID:      user.ID,
+			Role:        invitation.Role,
+		}
+		// 已是成员时保留现有角色
+		if err := tx.Where("workspace_id = ? AND user_id = ?", member.WorkspaceID, member.UserID).
+			FirstOrCreate(&member).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		return tx.Model(&invitation).Updates(map[string]interface{}{
+			"status":      workspace.InvitationAccepted,
+			"accepted_at": &now,
+		}).Error
+	})
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to accept workspace invitation", "error", err, "invitation_id", invitation.ID)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp, err = loadWorkspace(l.svcCtx.DB, invitation.WorkspaceID, user.ID)
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to load joined workspace", "error", err, "workspace_id", invitation.WorkspaceID)
+		return nil, errors.ErrInternalServer
+	}
+
+	logger.GlobalLogger(constants.ServiceAuth).LogBusinessOperation(l.ctx, "accept_invitation", "workspace", invitation.WorkspaceID, "success",
+		"user_id", user.ID,
+		"invitation_id", invitation.ID,
+		"role", resp.Role,
+	)
+	return resp, nil
+}
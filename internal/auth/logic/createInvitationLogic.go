@@ -0,0 +1,107 @@
+package logic
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type CreateInvitationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateInvitationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateInvitationLogic {
+	return &CreateInvitationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateInvitation 按邮箱邀请成员加入工作区，仅所有者可操作
+// 同一邮箱已有待接受的邀请时更新角色并重新计算有效期
+func (l *CreateInvitationLogic) CreateInvitation(req *types.CreateInvitationRequest) (resp *types.WorkspaceInvitation, err error) {
+	access, err := workspace.AuthorizeWorkspace(l.ctx, l.svcCtx.DB, req.WorkspaceID, workspace.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if !isValidEmail(email) {
+		return nil, errors.NewValidationError("Invalid email format", []errors.FieldError{
+			{Field: "email", Message: "Please provide a valid email address"},
+		})
+	}
+	if !workspace.ValidRole(req.Role) {
+		return nil, errors.NewValidationError("Invalid role", []errors.FieldError{
+			{Field: "role", Message: "Role must be one of: owner, editor, viewer"},
+		})
+	}
+
+	var ws models.Workspace
+	if err := l.svcCtx.DB.Where("id = ?", access.WorkspaceID).First(&ws).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to load workspace", "error", err, "workspace_id", access.WorkspaceID)
+		return nil, errors.ErrInternalServer
+	}
+
+	var members int64
+	if err := l.svcCtx.DB.Table("workspace_members m").
+		Joins("JOIN users u ON u.id = m.user_id").
+		Where("m.workspace_id = ? AND LOWER(u.email) = ?", access.WorkspaceID, email).
+		Count(&members).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to check workspace membership", "error", err, "workspace_id", access.WorkspaceID)
+		return nil, errors.ErrInternalServer
+	}
+	if members > 0 {
+		return nil, errors.NewConflictError("This user is already a member of the workspace")
+	}
+
+	expiresAt := time.Now().Add(workspace.InvitationTTL)
+	var invitation models.WorkspaceInvitation
+	err = l.svcCtx.DB.Where("workspace_id = ? AND email = ? AND status = ?", access.WorkspaceID, email, workspace.InvitationPending).
+		First(&invitation).Error
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		invitation = models.WorkspaceInvitation{
+			WorkspaceID: access.WorkspaceID,
+			Email:       email,
+			Role:        req.Role,
+			Status:      workspace.InvitationPending,
+			InvitedBy:   access.UserID,
+			ExpiresAt:   expiresAt,
+		}
+		err = l.svcCtx.DB.Create(&invitation).Error
+	case err == nil:
+		invitation.Role = req.Role
+		invitation.InvitedBy = access.UserID
+		invitation.ExpiresAt = expiresAt
+		err = l.svcCtx.DB.Save(&invitation).Error
+	}
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to save workspace invitation", "error", err, "workspace_id", access.WorkspaceID)
+		return nil, errors.ErrInternalServer
+	}
+
+	logger.GlobalLogger(constants.ServiceAuth).LogBusinessOperation(l.ctx, "create_invitation", "workspace", access.WorkspaceID, "success",
+		"user_id", access.UserID,
+		"invitation_id", invitation.ID,
+		"role", invitation.Role,
+	)
+
+	result := toWorkspaceInvitation(&invitation, ws.Name)
+	return &result, nil
+}
@@ -0,0 +1,69 @@
+package logic
+
+import (
+	"context"
+
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type CreateWorkspaceLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateWorkspaceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateWorkspaceLogic {
+	return &CreateWorkspaceLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateWorkspace 创建团队工作区，创建者成为所有者
+func (l *CreateWorkspaceLogic) CreateWorkspace(req *types.CreateWorkspaceRequest) (resp *types.Workspace, err error) {
+	// 从JWT context获取用户ID
+	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := validateWorkspaceName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	ws := models.Workspace{Name: name, OwnerID: userIDStr}
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&ws).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.WorkspaceMember{WorkspaceID: ws.ID, UserID: userIDStr, Role: workspace.RoleOwner}).Error
+	})
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to create workspace", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp, err = loadWorkspace(l.svcCtx.DB, ws.ID, userIDStr)
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to load created workspace", "error", err, "workspace_id", ws.ID)
+		return nil, errors.ErrInternalServer
+	}
+
+	logger.GlobalLogger(constants.ServiceAuth).LogBusinessOperation(l.ctx, "create_workspace", "workspace", ws.ID, "success",
+		"user_id", userIDStr,
+		"name", name,
+	)
+	return resp, nil
+}
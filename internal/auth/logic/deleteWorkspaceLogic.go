@@ -0,0 +1,59 @@
+package logic
+
+import (
+	"context"
+
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteWorkspaceLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteWorkspaceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteWorkspaceLogic {
+	return &DeleteWorkspaceLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// DeleteWorkspace 删除团队工作区，其中的追踪产品、标签、分析组等资源随之删除 (外键级联)
+// 个人工作区不可删除
+func (l *DeleteWorkspaceLogic) DeleteWorkspace(req *types.WorkspaceRequest) (resp *types.WorkspaceMessageResponse, err error) {
+	access, err := workspace.AuthorizeWorkspace(l.ctx, l.svcCtx.DB, req.WorkspaceID, workspace.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	var ws models.Workspace
+	if err := l.svcCtx.DB.Where("id = ?", access.WorkspaceID).First(&ws).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to load workspace", "error", err, "workspace_id", access.WorkspaceID)
+		return nil, errors.ErrInternalServer
+	}
+	if ws.IsPersonal {
+		return nil, errors.NewBadRequestError("Personal workspace cannot be deleted")
+	}
+
+	if err := l.svcCtx.DB.Delete(&ws).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to delete workspace", "error", err, "workspace_id", ws.ID)
+		return nil, errors.ErrInternalServer
+	}
+
+	logger.GlobalLogger(constants.ServiceAuth).LogBusinessOperation(l.ctx, "delete_workspace", "workspace", ws.ID, "success",
+		"user_id", access.UserID,
+		"name", ws.Name,
+	)
+	return &types.WorkspaceMessageResponse{Message: "Workspace deleted successfully"}, nil
+}
@@ -0,0 +1,52 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListInvitationsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListInvitationsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListInvitationsLogic {
+	return &ListInvitationsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListInvitations 返回工作区中未过期的待接受邀请，仅所有者可见
+func (l *ListInvitationsLogic) ListInvitations(req *types.WorkspaceRequest) (resp *types.ListInvitationsResponse, err error) {
+	access, err := workspace.AuthorizeWorkspace(l.ctx, l.svcCtx.DB, req.WorkspaceID, workspace.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	var invitations []models.WorkspaceInvitation
+	if err := l.svcCtx.DB.Where("workspace_id = ? AND status = ? AND expires_at > ?", access.WorkspaceID, workspace.InvitationPending, time.Now()).
+		Preload("Workspace").
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to list workspace invitations", "error", err, "workspace_id", access.WorkspaceID)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp = &types.ListInvitationsResponse{Invitations: make([]types.WorkspaceInvitation, len(invitations))}
+	for i := range invitations {
+		resp.Invitations[i] = toWorkspaceInvitation(&invitations[i], "")
+	}
+	return resp, nil
+}
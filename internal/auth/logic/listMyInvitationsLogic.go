@@ -0,0 +1,71 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type ListMyInvitationsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListMyInvitationsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListMyInvitationsLogic {
+	return &ListMyInvitationsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListMyInvitations 返回发送到当前用户邮箱、尚未过期的待接受邀请
+func (l *ListMyInvitationsLogic) ListMyInvitations() (resp *types.ListInvitationsResponse, err error) {
+	user, err := currentUser(l.ctx, l.svcCtx.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	var invitations []models.WorkspaceInvitation
+	if err := l.svcCtx.DB.Where("LOWER(email) = LOWER(?) AND status = ? AND expires_at > ?", user.Email, workspace.InvitationPending, time.Now()).
+		Preload("Workspace").
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to list user invitations", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp = &types.ListInvitationsResponse{Invitations: make([]types.WorkspaceInvitation, len(invitations))}
+	for i := range invitations {
+		resp.Invitations[i] = toWorkspaceInvitation(&invitations[i], "")
+	}
+	return resp, nil
+}
+
+// currentUser 查询JWT中的当前用户
+func currentUser(ctx context.Context, db *gorm.DB) (*models.User, error) {
+	userIDStr, err := utils.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var user models.User
+	if err := db.Where("id = ?", userIDStr).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound
+		}
+		utils.LogError(ctx, "Failed to load user", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+	return &user, nil
+}
@@ -0,0 +1,105 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type ListWorkspaceMembersLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListWorkspaceMembersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListWorkspaceMembersLogic {
+	return &ListWorkspaceMembersLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListWorkspaceMembers 返回工作区成员及角色，所有成员可见
+func (l *ListWorkspaceMembersLogic) ListWorkspaceMembers(req *types.WorkspaceRequest) (resp *types.ListWorkspaceMembersResponse, err error) {
+	access, err := workspace.AuthorizeWorkspace(l.ctx, l.svcCtx.DB, req.WorkspaceID, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []models.WorkspaceMember
+	if err := l.svcCtx.DB.Where("workspace_id = ?", access.WorkspaceID).
+		Preload("User").
+		Order("created_at ASC").
+		Find(&members).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to list workspace members", "error", err, "workspace_id", access.WorkspaceID)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp = &types.ListWorkspaceMembersResponse{Members: make([]types.WorkspaceMember, len(members))}
+	for i := range members {
+		resp.Members[i] = toWorkspaceMember(&members[i])
+	}
+	return resp, nil
+}
+
+// toWorkspaceMember 转换工作区成员，需预加载 User
+func toWorkspaceMember(member *models.WorkspaceMember) types.WorkspaceMember {
+	result := types.WorkspaceMember{
+		UserID:   member.UserID,
+		Email:    member.User.Email,
+		Role:     member.Role,
+		JoinedAt: member.CreatedAt.Format(time.RFC3339),
+	}
+	if member.User.CompanyName != nil {
+		result.CompanyName = *member.User.CompanyName
+	}
+	return result
+}
+
+// findWorkspaceMember 查询工作区中的成员及其所属工作区
+func findWorkspaceMember(ctx context.Context, db *gorm.DB, workspaceID, userID string) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+	err := db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Preload("User").
+		Preload("Workspace").
+		First(&member).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(ctx, "Failed to load workspace member", "error", err, "workspace_id", workspaceID, "member_id", userID)
+		return nil, errors.ErrInternalServer
+	}
+	return &member, nil
+}
+
+// checkOwnerRemains 成员将失去所有者角色时，确认工作区仍有其他所有者；个人工作区的创建者始终是所有者
+func checkOwnerRemains(ctx context.Context, db *gorm.DB, member *models.WorkspaceMember) error {
+	if member.Workspace.IsPersonal && member.UserID == member.Workspace.OwnerID {
+		return errors.NewBadRequestError("The owner of a personal workspace cannot be removed or demoted")
+	}
+	if member.Role != workspace.RoleOwner {
+		return nil
+	}
+
+	var owners int64
+	if err := db.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND role = ?", member.WorkspaceID, workspace.RoleOwner).
+		Count(&owners).Error; err != nil {
+		utils.LogError(ctx, "Failed to count workspace owners", "error", err, "workspace_id", member.WorkspaceID)
+		return errors.ErrInternalServer
+	}
+	if owners <= 1 {
+		return errors.NewBadRequestError("A workspace must have at least one owner")
+	}
+	return nil
+}
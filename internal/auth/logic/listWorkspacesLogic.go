@@ -0,0 +1,130 @@
+package logic
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+// 工作区名称最大长度
+const maxWorkspaceNameLength = 100
+
+type ListWorkspacesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListWorkspacesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListWorkspacesLogic {
+	return &ListWorkspacesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListWorkspaces 返回当前用户加入的所有工作区及其角色，个人工作区排在第一位
+func (l *ListWorkspacesLogic) ListWorkspaces() (resp *types.ListWorkspacesResponse, err error) {
+	// 从JWT context获取用户ID
+	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []workspaceRow
+	if err := userWorkspaces(l.svcCtx.DB, userIDStr).
+		Order("w.is_personal DESC, w.created_at ASC").
+		Scan(&rows).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to list workspaces", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp = &types.ListWorkspacesResponse{Workspaces: make([]types.Workspace, len(rows))}
+	for i, row := range rows {
+		resp.Workspaces[i] = row.toWorkspace()
+	}
+	return resp, nil
+}
+
+// workspaceRow 工作区及当前用户的角色
+type workspaceRow struct {
+	ID          string
+	Name        string
+	OwnerID     string
+	IsPersonal  bool
+	CreatedAt   time.Time
+	Role        string
+	MemberCount int
+}
+
+func (r workspaceRow) toWorkspace() types.Workspace {
+	return types.Workspace{
+		ID:          r.ID,
+		Name:        r.Name,
+		OwnerID:     r.OwnerID,
+		IsPersonal:  r.IsPersonal,
+		Role:        r.Role,
+		MemberCount: r.MemberCount,
+		CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// userWorkspaces 查询用户加入的工作区、用户的角色和成员数
+func userWorkspaces(db *gorm.DB, userID string) *gorm.DB {
+	return db.Table("workspaces w").
+		Select("w.id, w.name, w.owner_id, w.is_personal, w.created_at, m.role, "+
+			"(SELECT COUNT(*) FROM workspace_members c WHERE c.workspace_id = w.id) AS member_count").
+		Joins("JOIN workspace_members m ON m.workspace_id = w.id").
+		Where("m.user_id = ?", userID)
+}
+
+// loadWorkspace 查询用户加入的单个工作区
+func loadWorkspace(db *gorm.DB, workspaceID, userID string) (*types.Workspace, error) {
+	var row workspaceRow
+	result := userWorkspaces(db, userID).Where("w.id = ?", workspaceID).Limit(1).Scan(&row)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.ErrNotFound
+	}
+	workspace := row.toWorkspace()
+	return &workspace, nil
+}
+
+// validateWorkspaceName 校验并规范化工作区名称
+func validateWorkspaceName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxWorkspaceNameLength {
+		return "", errors.NewValidationError("Invalid workspace name", []errors.FieldError{
+			{Field: "name", Message: "Name is required and must be at most 100 characters"},
+		})
+	}
+	return name, nil
+}
+
+// toWorkspaceInvitation 转换邀请，workspaceName 为空时使用预加载的工作区名称
+func toWorkspaceInvitation(invitation *models.WorkspaceInvitation, workspaceName string) types.WorkspaceInvitation {
+	if workspaceName == "" {
+		workspaceName = invitation.Workspace.Name
+	}
+	return types.WorkspaceInvitation{
+		ID:            invitation.ID,
+		WorkspaceID:   invitation.WorkspaceID,
+		WorkspaceName: workspaceName,
+		Email:         invitation.Email,
+		Role:          invitation.Role,
+		Status:        invitation.Status,
+		ExpiresAt:     invitation.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:     invitation.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"amazonpilot/internal/auth/types"
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/workspace"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
@@ -86,8 +87,14 @@ func (l *RegisterLogic) Register(req *types.RegisterRequest) (resp *types.Regist
 		return nil, errors.ErrInternalServer
 	}
 
-	// 保存用户
-	if err := l.svcCtx.DB.Create(&user).Error; err != nil {
+	// 保存用户并创建个人工作区
+	if err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		_, err := workspace.CreatePersonal(tx, &user)
+		return err
+	}); err != nil {
 		l.Errorf("Failed to create user: %v", err)
 		return nil, errors.ErrInternalServer
 	}
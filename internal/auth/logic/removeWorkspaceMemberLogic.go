@@ -0,0 +1,68 @@
+package logic
+
+import (
+	"context"
+
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RemoveWorkspaceMemberLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRemoveWorkspaceMemberLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RemoveWorkspaceMemberLogic {
+	return &RemoveWorkspaceMemberLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RemoveWorkspaceMember 移除成员，所有者可移除任何成员，其他成员只能移除自己 (退出工作区)
+// 成员创建的追踪产品等资源保留在工作区中
+func (l *RemoveWorkspaceMemberLogic) RemoveWorkspaceMember(req *types.RemoveWorkspaceMemberRequest) (resp *types.WorkspaceMessageResponse, err error) {
+	access, err := workspace.AuthorizeWorkspace(l.ctx, l.svcCtx.DB, req.WorkspaceID, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	leaving := req.UserID == access.UserID
+	if !leaving && !access.Can(workspace.RoleOwner) {
+		return nil, errors.NewForbiddenError("Only workspace owners can remove other members")
+	}
+
+	member, err := findWorkspaceMember(l.ctx, l.svcCtx.DB, access.WorkspaceID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOwnerRemains(l.ctx, l.svcCtx.DB, member); err != nil {
+		return nil, err
+	}
+
+	if err := l.svcCtx.DB.Delete(member).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to remove workspace member", "error", err, "workspace_id", access.WorkspaceID)
+		return nil, errors.ErrInternalServer
+	}
+
+	logger.GlobalLogger(constants.ServiceAuth).LogBusinessOperation(l.ctx, "remove_workspace_member", "workspace", access.WorkspaceID, "success",
+		"user_id", access.UserID,
+		"member_id", member.UserID,
+		"role", member.Role,
+		"leaving", leaving,
+	)
+
+	message := "Member removed successfully"
+	if leaving {
+		message = "You have left the workspace"
+	}
+	return &types.WorkspaceMessageResponse{Message: message}, nil
+}
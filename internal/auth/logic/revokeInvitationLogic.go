@@ -0,0 +1,55 @@
+package logic
+
+import (
+	"context"
+
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RevokeInvitationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRevokeInvitationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RevokeInvitationLogic {
+	return &RevokeInvitationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RevokeInvitation 撤销待接受的邀请，仅所有者可操作
+func (l *RevokeInvitationLogic) RevokeInvitation(req *types.RevokeInvitationRequest) (resp *types.WorkspaceMessageResponse, err error) {
+	access, err := workspace.AuthorizeWorkspace(l.ctx, l.svcCtx.DB, req.WorkspaceID, workspace.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	result := l.svcCtx.DB.Model(&models.WorkspaceInvitation{}).
+		Where("id = ? AND workspace_id = ? AND status = ?", req.InvitationID, access.WorkspaceID, workspace.InvitationPending).
+		Update("status", workspace.InvitationRevoked)
+	if result.Error != nil {
+		utils.LogError(l.ctx, "Failed to revoke workspace invitation", "error", result.Error, "workspace_id", access.WorkspaceID)
+		return nil, errors.ErrInternalServer
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.ErrNotFound
+	}
+
+	logger.GlobalLogger(constants.ServiceAuth).LogBusinessOperation(l.ctx, "revoke_invitation", "workspace", access.WorkspaceID, "success",
+		"user_id", access.UserID,
+		"invitation_id", req.InvitationID,
+	)
+	return &types.WorkspaceMessageResponse{Message: "Invitation revoked successfully"}, nil
+}
@@ -0,0 +1,60 @@
+package logic
+
+import (
+	"context"
+
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateWorkspaceLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateWorkspaceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateWorkspaceLogic {
+	return &UpdateWorkspaceLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// UpdateWorkspace 重命名工作区，仅所有者可操作
+func (l *UpdateWorkspaceLogic) UpdateWorkspace(req *types.UpdateWorkspaceRequest) (resp *types.Workspace, err error) {
+	access, err := workspace.AuthorizeWorkspace(l.ctx, l.svcCtx.DB, req.WorkspaceID, workspace.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := validateWorkspaceName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.svcCtx.DB.Model(&models.Workspace{}).Where("id = ?", access.WorkspaceID).Update("name", name).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to update workspace", "error", err, "workspace_id", access.WorkspaceID)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp, err = loadWorkspace(l.svcCtx.DB, access.WorkspaceID, access.UserID)
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to load updated workspace", "error", err, "workspace_id", access.WorkspaceID)
+		return nil, errors.ErrInternalServer
+	}
+
+	logger.GlobalLogger(constants.ServiceAuth).LogBusinessOperation(l.ctx, "update_workspace", "workspace", access.WorkspaceID, "success",
+		"user_id", access.UserID,
+		"name", name,
+	)
+	return resp, nil
+}
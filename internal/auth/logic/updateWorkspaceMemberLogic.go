@@ -0,0 +1,73 @@
+package logic
+
+import (
+	"context"
+
+	"amazonpilot/internal/auth/svc"
+	"amazonpilot/internal/auth/types"
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateWorkspaceMemberLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateWorkspaceMemberLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateWorkspaceMemberLogic {
+	return &UpdateWorkspaceMemberLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// UpdateWorkspaceMember 修改成员角色，仅所有者可操作，工作区至少保留一个所有者
+func (l *UpdateWorkspaceMemberLogic) UpdateWorkspaceMember(req *types.UpdateWorkspaceMemberRequest) (resp *types.WorkspaceMember, err error) {
+	access, err := workspace.AuthorizeWorkspace(l.ctx, l.svcCtx.DB, req.WorkspaceID, workspace.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	if !workspace.ValidRole(req.Role) {
+		return nil, errors.NewValidationError("Invalid role", []errors.FieldError{
+			{Field: "role", Message: "Role must be one of: owner, editor, viewer"},
+		})
+	}
+
+	member, err := findWorkspaceMember(l.ctx, l.svcCtx.DB, access.WorkspaceID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == req.Role {
+		result := toWorkspaceMember(member)
+		return &result, nil
+	}
+	if req.Role != workspace.RoleOwner {
+		if err := checkOwnerRemains(l.ctx, l.svcCtx.DB, member); err != nil {
+			return nil, err
+		}
+	}
+
+	previousRole := member.Role
+	if err := l.svcCtx.DB.Model(member).Update("role", req.Role).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to update workspace member", "error", err, "workspace_id", access.WorkspaceID)
+		return nil, errors.ErrInternalServer
+	}
+
+	logger.GlobalLogger(constants.ServiceAuth).LogBusinessOperation(l.ctx, "update_workspace_member", "workspace", access.WorkspaceID, "success",
+		"user_id", access.UserID,
+		"member_id", member.UserID,
+		"previous_role", previousRole,
+		"role", req.Role,
+	)
+
+	result := toWorkspaceMember(member)
+	return &result, nil
+}
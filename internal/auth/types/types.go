@@ -3,6 +3,20 @@
 
 package types
 
+type AcceptInvitationRequest struct {
+	InvitationID string `path:"invitation_id"`
+}
+
+type CreateInvitationRequest struct {
+	WorkspaceID string `path:"workspace_id"`
+	Email       string `json:"email"`
+	Role        string `json:"role,default=viewer"`
+}
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name"`
+}
+
 type HealthResponse struct {
 	Service string `json:"service"`
 	Status  string `json:"status"`
@@ -10,6 +24,18 @@ type HealthResponse struct {
 	Uptime  int64  `json:"uptime"`
 }
 
+type ListInvitationsResponse struct {
+	Invitations []WorkspaceInvitation `json:"invitations"`
+}
+
+type ListWorkspaceMembersResponse struct {
+	Members []WorkspaceMember `json:"members"`
+}
+
+type ListWorkspacesResponse struct {
+	Workspaces []Workspace `json:"workspaces"`
+}
+
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
@@ -62,6 +88,27 @@ type RegisterResponse struct {
 	UserID  string `json:"user_id"`
 }
 
+type RemoveWorkspaceMemberRequest struct {
+	WorkspaceID string `path:"workspace_id"`
+	UserID      string `path:"user_id"`
+}
+
+type RevokeInvitationRequest struct {
+	WorkspaceID  string `path:"workspace_id"`
+	InvitationID string `path:"invitation_id"`
+}
+
+type UpdateWorkspaceMemberRequest struct {
+	WorkspaceID string `path:"workspace_id"`
+	UserID      string `path:"user_id"`
+	Role        string `json:"role"`
+}
+
+type UpdateWorkspaceRequest struct {
+	WorkspaceID string `path:"workspace_id"`
+	Name        string `json:"name"`
+}
+
 type User struct {
 	ID          string `json:"id"`
 	Email       string `json:"email"`
@@ -70,3 +117,40 @@ type User struct {
 	IsActive    bool   `json:"is_active"`
 	CreatedAt   string `json:"created_at"`
 }
+
+type Workspace struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	OwnerID     string `json:"owner_id"`
+	IsPersonal  bool   `json:"is_personal"`
+	Role        string `json:"role"` // 当前用户的角色
+	MemberCount int    `json:"member_count"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type WorkspaceInvitation struct {
+	ID            string `json:"id"`
+	WorkspaceID   string `json:"workspace_id"`
+	WorkspaceName string `json:"workspace_name"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	Status        string `json:"status"`
+	ExpiresAt     string `json:"expires_at"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type WorkspaceMember struct {
+	UserID      string `json:"user_id"`
+	Email       string `json:"email"`
+	CompanyName string `json:"company_name,omitempty"`
+	Role        string `json:"role"`
+	JoinedAt    string `json:"joined_at"`
+}
+
+type WorkspaceMessageResponse struct {
+	Message string `json:"message"`
+}
+
+type WorkspaceRequest struct {
+	WorkspaceID string `path:"workspace_id"`
+}
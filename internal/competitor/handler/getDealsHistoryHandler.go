@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"amazonpilot/internal/competitor/logic"
+	"amazonpilot/internal/competitor/svc"
+	"amazonpilot/internal/competitor/types"
+	"amazonpilot/internal/pkg/utils"
+)
+
+func getDealsHistoryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.GetDealsHistoryRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			utils.HandleError(w, err)
+			return
+		}
+
+		l := logic.NewGetDealsHistoryLogic(r.Context(), svcCtx)
+		resp, err := l.GetDealsHistory(&req)
+		if err != nil {
+			utils.HandleError(w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -70,6 +70,11 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 					Path:    "/analysis/:analysis_id/report-status",
 					Handler: getReportStatusHandler(serverCtx),
 				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/analysis/:analysis_id/deals",
+					Handler: getDealsHistoryHandler(serverCtx),
+				},
 			}...,
 		),
 		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
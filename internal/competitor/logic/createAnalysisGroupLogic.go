@@ -10,7 +10,9 @@ import (
 	"amazonpilot/internal/competitor/types"
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
@@ -31,11 +33,17 @@ func NewCreateAnalysisGroupLogic(ctx context.Context, svcCtx *svc.ServiceContext
 }
 
 func (l *CreateAnalysisGroupLogic) CreateAnalysisGroup(req *types.CreateAnalysisRequest) (resp *types.CreateAnalysisResponse, err error) {
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
 	if err != nil {
 		return nil, err
 	}
 
+	// 校验工作区计划的分析组数上限
+	if err := quota.CheckCompetitorGroups(l.ctx, l.svcCtx.DB, access.WorkspaceID); err != nil {
+		return nil, err
+	}
+
 	// 验证主产品是否存在
 	var mainProduct models.Product
 	err = l.svcCtx.DB.Where("id = ?", req.MainProductID).First(&mainProduct).Error
@@ -51,7 +59,8 @@ func (l *CreateAnalysisGroupLogic) CreateAnalysisGroup(req *types.CreateAnalysis
 	// 创建分析组 (固定daily更新)
 	nextAnalysis := time.Now().Add(24 * time.Hour)
 	analysisGroup := models.CompetitorAnalysisGroup{
-		UserID:         userIDStr,
+		UserID:         access.UserID,
+		WorkspaceID:    access.WorkspaceID,
 		Name:           req.Name,
 		MainProductID:  req.MainProductID,
 		IsActive:       true,
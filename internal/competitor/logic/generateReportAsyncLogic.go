@@ -11,8 +11,10 @@ import (
 	"amazonpilot/internal/competitor/types"
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
 	"amazonpilot/internal/pkg/tasks"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
 
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
@@ -35,15 +37,15 @@ func NewGenerateReportAsyncLogic(ctx context.Context, svcCtx *svc.ServiceContext
 }
 
 func (l *GenerateReportAsyncLogic) GenerateReportAsync(req *types.GenerateReportAsyncRequest) (resp *types.GenerateReportAsyncResponse, err error) {
-	// 从JWT context获取用户ID
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
 	if err != nil {
 		return nil, err
 	}
 
 	// 验证分析组是否存在且属于当前用户
 	var analysisGroup models.CompetitorAnalysisGroup
-	err = l.svcCtx.DB.Where("id = ? AND user_id = ?", req.AnalysisID, userIDStr).
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.AnalysisID, access.WorkspaceID).
 		First(&analysisGroup).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, errors.ErrNotFound
@@ -67,6 +69,11 @@ func (l *GenerateReportAsyncLogic) GenerateReportAsync(req *types.GenerateReport
 		}
 	}
 
+	// 校验工作区计划的月度报告数上限
+	if err := quota.CheckReports(l.ctx, l.svcCtx.DB, access.WorkspaceID); err != nil {
+		return nil, err
+	}
+
 	// 生成唯一任务ID
 	taskID := uuid.New().String()
 
@@ -82,12 +89,19 @@ func (l *GenerateReportAsyncLogic) GenerateReportAsync(req *types.GenerateReport
 		return nil, errors.ErrInternalServer
 	}
 
+	// 报告展示货币，未指定时使用服务默认货币
+	currency := req.Currency
+	if currency == "" {
+		currency = l.svcCtx.Config.EnvConfig.FX.DefaultCurrency
+	}
+
 	// 准备异步任务载荷
 	taskPayload := tasks.GenerateReportPayload{
 		AnalysisID:  req.AnalysisID,
-		UserID:      userIDStr,
+		UserID:      access.UserID,
 		TaskID:      taskID,
 		Force:       req.Force,
+		Currency:    currency,
 		RequestedAt: time.Now().Format("2006-01-02T15:04:05Z07:00"),
 	}
 
@@ -6,13 +6,18 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"time"
 
 	"amazonpilot/internal/competitor/svc"
 	"amazonpilot/internal/competitor/types"
 	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/estimation"
+	"amazonpilot/internal/pkg/fx"
 	"amazonpilot/internal/pkg/llm"
 	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
@@ -33,15 +38,15 @@ func NewGenerateReportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Ge
 }
 
 func (l *GenerateReportLogic) GenerateReport(req *types.GenerateReportRequest) (resp *types.GenerateReportResponse, err error) {
-	// 从JWT context获取用户ID
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
 	if err != nil {
 		return nil, err
 	}
 
 	// 验证分析组是否存在且属于当前用户
 	var analysisGroup models.CompetitorAnalysisGroup
-	err = l.svcCtx.DB.Where("id = ? AND user_id = ?", req.AnalysisID, userIDStr).
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.AnalysisID, access.WorkspaceID).
 		Preload("MainProduct").
 		Preload("Competitors.Product").
 		First(&analysisGroup).Error
@@ -69,6 +74,11 @@ func (l *GenerateReportLogic) GenerateReport(req *types.GenerateReportRequest) (
 		}
 	}
 
+	// 校验工作区计划的月度报告数上限
+	if err := quota.CheckReports(l.ctx, l.svcCtx.DB, access.WorkspaceID); err != nil {
+		return nil, err
+	}
+
 	// 创建新的分析报告记录
 	analysisResult := models.CompetitorAnalysisResult{
 		AnalysisGroupID: analysisGroup.ID,
@@ -103,6 +113,14 @@ func (l *GenerateReportLogic) GenerateReport(req *types.GenerateReportRequest) (
 		return nil, errors.ErrInternalServer
 	}
 
+	// 报告中的价格统一换算为展示货币
+	displayCurrency := l.svcCtx.Config.EnvConfig.FX.DefaultCurrency
+	if req.Currency != "" {
+		displayCurrency = req.Currency
+	}
+	displayCurrency = fx.NormalizeCurrency(displayCurrency)
+	mainPrice := l.svcCtx.FX.ConvertOrKeep(mainProductData.Price, mainProductData.Currency, displayCurrency, mainProductData.RecordedAt)
+
 	// 准备分析数据
 	analysisData := llm.CompetitorAnalysisData{
 		MainProduct: llm.ProductData{
@@ -113,8 +131,8 @@ func (l *GenerateReportLogic) GenerateReport(req *types.GenerateReportRequest) (
 				}
 				return ""
 			}(),
-			Price:       mainProductData.Price,
-			Currency:    mainProductData.Currency,
+			Price:       mainPrice.Amount,
+			Currency:    mainPrice.Currency,
 			BSR:         mainProductData.BSR,
 			Rating:      mainProductData.Rating,
 			ReviewCount: mainProductData.ReviewCount,
@@ -122,6 +140,9 @@ func (l *GenerateReportLogic) GenerateReport(req *types.GenerateReportRequest) (
 		Competitors:     make([]llm.ProductData, len(analysisGroup.Competitors)),
 		AnalysisMetrics: []string{"price", "bsr", "rating", "features"},
 	}
+	if err := estimation.FillReportSalesEstimate(l.svcCtx.DB, l.svcCtx.Sales, l.svcCtx.FX, &analysisData.MainProduct, analysisGroup.MainProduct.ID); err != nil {
+		l.Errorf("Failed to estimate main product sales: %v", err)
+	}
 
 	// 获取竞品数据
 	for i, comp := range analysisGroup.Competitors {
@@ -134,6 +155,8 @@ func (l *GenerateReportLogic) GenerateReport(req *types.GenerateReportRequest) (
 			}
 		}
 
+		competitorPrice := l.svcCtx.FX.ConvertOrKeep(competitorData.Price, competitorData.Currency, displayCurrency, competitorData.RecordedAt)
+
 		analysisData.Competitors[i] = llm.ProductData{
 			ASIN: comp.Product.ASIN,
 			Title: func() string {
@@ -142,12 +165,15 @@ func (l *GenerateReportLogic) GenerateReport(req *types.GenerateReportRequest) (
 				}
 				return ""
 			}(),
-			Price:       competitorData.Price,
-			Currency:    competitorData.Currency,
+			Price:       competitorPrice.Amount,
+			Currency:    competitorPrice.Currency,
 			BSR:         competitorData.BSR,
 			Rating:      competitorData.Rating,
 			ReviewCount: competitorData.ReviewCount,
 		}
+		if err := estimation.FillReportSalesEstimate(l.svcCtx.DB, l.svcCtx.Sales, l.svcCtx.FX, &analysisData.Competitors[i], comp.Product.ID); err != nil {
+			l.Errorf("Failed to estimate sales for %s: %v", comp.Product.ASIN, err)
+		}
 	}
 
 	// 同步调用DeepSeek生成报告
@@ -203,40 +229,33 @@ type ProductData struct {
 	BSR         int
 	Rating      float64
 	ReviewCount int
+	RecordedAt  time.Time // 价格记录时间，用于选择当天汇率
 }
 
-// getLatestProductData 获取产品的最新数据（价格、BSR、评分等）
+// getLatestProductData 从最新快照获取产品的最新数据（价格、BSR、评分等）
 func (l *GenerateReportLogic) getLatestProductData(productID string) (*ProductData, error) {
-	// 获取最新价格数据
-	var latestPrice models.PriceHistory
-	err := l.svcCtx.DB.Where("product_id = ?", productID).
-		Order("recorded_at DESC").
-		First(&latestPrice).Error
-	if err != nil && err != gorm.ErrRecordNotFound {
-		return nil, err
-	}
-
-	// 获取最新排名数据
-	var latestRanking models.RankingHistory
-	err = l.svcCtx.DB.Where("product_id = ?", productID).
-		Order("recorded_at DESC").
-		First(&latestRanking).Error
-	if err != nil && err != gorm.ErrRecordNotFound {
+	var snapshot models.LatestSnapshot
+	err := l.svcCtx.DB.Where("product_id = ?", productID).First(&snapshot).Error
+	if err == gorm.ErrRecordNotFound {
+		// 尚未刷新过的产品没有快照
+		return &ProductData{}, nil
+	} else if err != nil {
 		return nil, err
 	}
 
 	data := &ProductData{
-		Price:       latestPrice.Price,
-		Currency:    latestPrice.Currency,
-		ReviewCount: latestRanking.ReviewCount,
+		Price:       snapshot.Price,
+		Currency:    snapshot.Currency,
+		ReviewCount: snapshot.ReviewCount,
+		RecordedAt:  snapshot.RecordedAt,
 	}
 
 	// 安全设置BSR和Rating
-	if latestRanking.BSRRank != nil {
-		data.BSR = *latestRanking.BSRRank
+	if snapshot.BSRRank != nil {
+		data.BSR = *snapshot.BSRRank
 	}
-	if latestRanking.Rating != nil {
-		data.Rating = *latestRanking.Rating
+	if snapshot.Rating != nil {
+		data.Rating = *snapshot.Rating
 	}
 
 	return data, nil
@@ -5,11 +5,16 @@ import (
 	"amazonpilot/internal/competitor/types"
 	"amazonpilot/internal/pkg/constants"
 	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/estimation"
+	"amazonpilot/internal/pkg/fx"
 	"amazonpilot/internal/pkg/logger"
 	"amazonpilot/internal/pkg/models"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
 	"context"
 	"encoding/json"
+	"sort"
+	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
@@ -30,15 +35,15 @@ func NewGetAnalysisResultsLogic(ctx context.Context, svcCtx *svc.ServiceContext)
 }
 
 func (l *GetAnalysisResultsLogic) GetAnalysisResults(req *types.GetAnalysisRequest) (resp *types.GetAnalysisResponse, err error) {
-	// 从JWT context获取用户ID
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
 	if err != nil {
 		return nil, err
 	}
 
 	// 验证分析组是否存在且属于当前用户
 	var analysisGroup models.CompetitorAnalysisGroup
-	err = l.svcCtx.DB.Where("id = ? AND user_id = ?", req.AnalysisID, userIDStr).
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.AnalysisID, access.WorkspaceID).
 		Preload("MainProduct").
 		Preload("Competitors.Product").
 		First(&analysisGroup).Error
@@ -74,6 +79,13 @@ func (l *GetAnalysisResultsLogic) GetAnalysisResults(req *types.GetAnalysisReque
 		LastUpdated: analysisGroup.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
+	// 展示货币：所有价格统一换算后再比较，避免不同币种直接计算min/max/avg
+	displayCurrency := l.svcCtx.Config.EnvConfig.FX.DefaultCurrency
+	if req.Currency != "" {
+		displayCurrency = req.Currency
+	}
+	displayCurrency = fx.NormalizeCurrency(displayCurrency)
+
 	// 构建主产品信息
 	mainProductData, err := l.getLatestProductData(analysisGroup.MainProduct.ID)
 	if err != nil {
@@ -81,6 +93,7 @@ func (l *GetAnalysisResultsLogic) GetAnalysisResults(req *types.GetAnalysisReque
 		// 使用默认值
 		mainProductData = &productData{Price: 0, Currency: "USD", BSR: 0, Rating: 0, ReviewCount: 0}
 	}
+	mainPrice := l.svcCtx.FX.ConvertOrKeep(mainProductData.Price, mainProductData.Currency, displayCurrency, mainProductData.RecordedAt)
 
 	resp.MainProduct = types.CompetitorProduct{
 		ID:   analysisGroup.MainProduct.ID,
@@ -97,11 +110,15 @@ func (l *GetAnalysisResultsLogic) GetAnalysisResults(req *types.GetAnalysisReque
 			}
 			return ""
 		}(),
-		Price:       mainProductData.Price,
 		BSR:         mainProductData.BSR,
 		Rating:      mainProductData.Rating,
 		ReviewCount: mainProductData.ReviewCount,
 	}
+	setCompetitorPrice(&resp.MainProduct, mainPrice)
+	if err := setCompetitorSales(l.svcCtx, &resp.MainProduct, analysisGroup.MainProduct.ID, displayCurrency); err != nil {
+		utils.LogError(l.ctx, "Failed to estimate main product sales", "product_id", analysisGroup.MainProduct.ID, "error", err)
+	}
+	prices := []fx.Money{mainPrice}
 
 	// 构建竞品信息
 	resp.Competitors = make([]types.CompetitorProduct, len(analysisGroup.Competitors))
@@ -112,6 +129,7 @@ func (l *GetAnalysisResultsLogic) GetAnalysisResults(req *types.GetAnalysisReque
 			// 使用默认值
 			competitorData = &productData{Price: 0, Currency: "USD", BSR: 0, Rating: 0, ReviewCount: 0}
 		}
+		competitorPrice := l.svcCtx.FX.ConvertOrKeep(competitorData.Price, competitorData.Currency, displayCurrency, competitorData.RecordedAt)
 
 		resp.Competitors[i] = types.CompetitorProduct{
 			ID:   comp.Product.ID,
@@ -128,13 +146,20 @@ func (l *GetAnalysisResultsLogic) GetAnalysisResults(req *types.GetAnalysisReque
 				}
 				return ""
 			}(),
-			Price:       competitorData.Price,
 			BSR:         competitorData.BSR,
 			Rating:      competitorData.Rating,
 			ReviewCount: competitorData.ReviewCount,
 		}
+		setCompetitorPrice(&resp.Competitors[i], competitorPrice)
+		if err := setCompetitorSales(l.svcCtx, &resp.Competitors[i], comp.Product.ID, displayCurrency); err != nil {
+			utils.LogError(l.ctx, "Failed to estimate competitor sales", "product_id", comp.Product.ID, "error", err)
+		}
+		prices = append(prices, competitorPrice)
 	}
 
+	// 价格区间只统计已换算为展示货币且有价格的产品
+	resp.Analysis.PriceRange = buildPriceRange(prices, displayCurrency)
+
 	// 如果有完成的报告，添加分析和建议
 	if err == nil && latestReport.Status == "completed" {
 		// 解析建议
@@ -166,41 +191,91 @@ type productData struct {
 	BSR         int
 	Rating      float64
 	ReviewCount int
+	RecordedAt  time.Time // 价格记录时间，用于选择当天汇率
 }
 
-// getLatestProductData 获取产品的最新数据（价格、BSR、评分等）
+// getLatestProductData 从最新快照获取产品的最新数据（价格、BSR、评分等）
 func (l *GetAnalysisResultsLogic) getLatestProductData(productID string) (*productData, error) {
-	// 获取最新价格数据
-	var latestPrice models.PriceHistory
-	err := l.svcCtx.DB.Where("product_id = ?", productID).
-		Order("recorded_at DESC").
-		First(&latestPrice).Error
-	if err != nil && err != gorm.ErrRecordNotFound {
-		return nil, err
-	}
-
-	// 获取最新排名数据
-	var latestRanking models.RankingHistory
-	err = l.svcCtx.DB.Where("product_id = ?", productID).
-		Order("recorded_at DESC").
-		First(&latestRanking).Error
-	if err != nil && err != gorm.ErrRecordNotFound {
+	var snapshot models.LatestSnapshot
+	err := l.svcCtx.DB.Where("product_id = ?", productID).First(&snapshot).Error
+	if err == gorm.ErrRecordNotFound {
+		// 尚未刷新过的产品没有快照
+		return &productData{}, nil
+	} else if err != nil {
 		return nil, err
 	}
 
 	data := &productData{
-		Price:       latestPrice.Price,
-		Currency:    latestPrice.Currency,
-		ReviewCount: latestRanking.ReviewCount,
+		Price:       snapshot.Price,
+		Currency:    snapshot.Currency,
+		ReviewCount: snapshot.ReviewCount,
+		RecordedAt:  snapshot.RecordedAt,
 	}
 
 	// 安全设置BSR和Rating
-	if latestRanking.BSRRank != nil {
-		data.BSR = *latestRanking.BSRRank
+	if snapshot.BSRRank != nil {
+		data.BSR = *snapshot.BSRRank
 	}
-	if latestRanking.Rating != nil {
-		data.Rating = *latestRanking.Rating
+	if snapshot.Rating != nil {
+		data.Rating = *snapshot.Rating
 	}
 
 	return data, nil
 }
+
+// setCompetitorPrice 设置换算后的价格，发生换算时保留原始价格
+func setCompetitorPrice(product *types.CompetitorProduct, money fx.Money) {
+	product.Price = money.Amount
+	product.Currency = money.Currency
+	if money.Converted() {
+		product.OriginalPrice = money.OriginalAmount
+		product.OriginalCurrency = money.OriginalCurrency
+	}
+}
+
+// setCompetitorSales 设置近30天的预估日销量和月收入（月收入换算为展示货币）
+func setCompetitorSales(svcCtx *svc.ServiceContext, product *types.CompetitorProduct, productID, displayCurrency string) error {
+	now := time.Now()
+	estimate, err := estimation.EstimateSales(svcCtx.DB, svcCtx.Sales, productID, now.AddDate(0, 0, -30), now)
+	if err != nil || estimate == nil {
+		return err
+	}
+
+	product.EstUnitsPerDay = estimate.AvgUnitsPerDay
+	product.EstMonthlyRevenue = svcCtx.FX.ConvertOrKeep(estimate.MonthlyRevenue, estimate.Currency, displayCurrency, estimate.RecordedAt).Amount
+	return nil
+}
+
+// buildPriceRange 计算展示货币下的价格区间（min/max/avg/median）
+func buildPriceRange(prices []fx.Money, currency string) types.PriceRange {
+	values := make([]float64, 0, len(prices))
+	for _, price := range prices {
+		// 缺少汇率的价格保持原币种，不能参与统计
+		if price.Amount <= 0 || price.Currency != currency {
+			continue
+		}
+		values = append(values, price.Amount)
+	}
+	if len(values) == 0 {
+		return types.PriceRange{Currency: currency}
+	}
+
+	sort.Float64s(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	median := values[len(values)/2]
+	if len(values)%2 == 0 {
+		median = (values[len(values)/2-1] + values[len(values)/2]) / 2
+	}
+
+	return types.PriceRange{
+		Min:      values[0],
+		Max:      values[len(values)-1],
+		Average:  sum / float64(len(values)),
+		Median:   median,
+		Currency: currency,
+	}
+}
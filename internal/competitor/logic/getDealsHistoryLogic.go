@@ -0,0 +1,115 @@
+package logic
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"amazonpilot/internal/competitor/svc"
+	"amazonpilot/internal/competitor/types"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/tasks"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetDealsHistoryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetDealsHistoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetDealsHistoryLogic {
+	return &GetDealsHistoryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetDealsHistoryLogic) GetDealsHistory(req *types.GetDealsHistoryRequest) (resp *types.GetDealsHistoryResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证分析组是否存在且属于当前用户
+	var analysisGroup models.CompetitorAnalysisGroup
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.AnalysisID, access.WorkspaceID).
+		Preload("MainProduct").
+		Preload("Competitors.Product").
+		First(&analysisGroup).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Database error when fetching analysis group", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	period, startTime := utils.PeriodStartTime(req.Period)
+	// 只返回计划保留期内的历史数据
+	if startTime, err = quota.HistoryStart(l.ctx, l.svcCtx.DB, access.WorkspaceID, startTime); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	resp = &types.GetDealsHistoryResponse{
+		AnalysisID: analysisGroup.ID,
+		Period:     period,
+		Products:   make([]types.ProductDeals, 0, len(analysisGroup.Competitors)+1),
+	}
+
+	products := []models.Product{analysisGroup.MainProduct}
+	for _, competitor := range analysisGroup.Competitors {
+		products = append(products, competitor.Product)
+	}
+
+	for i, product := range products {
+		var history []models.PriceHistory
+		if err := l.svcCtx.DB.Where("product_id = ? AND recorded_at >= ?", product.ID, startTime).
+			Order("recorded_at ASC").
+			Find(&history).Error; err != nil {
+			utils.LogError(l.ctx, "Failed to get price history for deals", "product_id", product.ID, "error", err)
+			return nil, errors.ErrInternalServer
+		}
+
+		deals := types.ProductDeals{
+			ProductID:     product.ID,
+			ASIN:          product.ASIN,
+			IsMainProduct: i == 0,
+			Promotions:    []types.Promotion{},
+		}
+		if product.Title != nil {
+			deals.Title = *product.Title
+		}
+
+		for _, p := range tasks.BuildPromotionPeriods(history, now) {
+			promotion := types.Promotion{
+				Type:          p.Type,
+				StartedAt:     p.StartedAt.Format("2006-01-02T15:04:05Z07:00"),
+				Ongoing:       p.Ongoing(),
+				DurationHours: p.DurationHours,
+				MaxDiscount:   p.MaxDiscount,
+				LowestPrice:   p.LowestPrice,
+				CouponText:    p.CouponText,
+			}
+			if p.EndedAt != nil {
+				promotion.EndedAt = p.EndedAt.Format("2006-01-02T15:04:05Z07:00")
+			}
+			deals.Promotions = append(deals.Promotions, promotion)
+			deals.PromotedHours += p.DurationHours
+		}
+		deals.PromotionCount = len(deals.Promotions)
+		deals.PromotedHours = math.Round(deals.PromotedHours*10) / 10
+
+		resp.Products = append(resp.Products, deals)
+	}
+
+	return resp, nil
+}
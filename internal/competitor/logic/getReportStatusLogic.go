@@ -8,6 +8,7 @@ import (
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/models"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
@@ -28,15 +29,15 @@ func NewGetReportStatusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *G
 }
 
 func (l *GetReportStatusLogic) GetReportStatus(req *types.GetReportStatusRequest) (resp *types.GetReportStatusResponse, err error) {
-	// 从JWT context获取用户ID
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
 	if err != nil {
 		return nil, err
 	}
 
 	// 验证分析组是否存在且属于当前用户
 	var analysisGroup models.CompetitorAnalysisGroup
-	err = l.svcCtx.DB.Where("id = ? AND user_id = ?", req.AnalysisID, userIDStr).
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.AnalysisID, access.WorkspaceID).
 		First(&analysisGroup).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, errors.ErrNotFound
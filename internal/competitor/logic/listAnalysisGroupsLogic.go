@@ -11,6 +11,8 @@ import (
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/models"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	pkgtypes "amazonpilot/pkg/types"
 
 	"github.com/zeromicro/go-zero/core/logx"
 )
@@ -30,23 +32,35 @@ func NewListAnalysisGroupsLogic(ctx context.Context, svcCtx *svc.ServiceContext)
 }
 
 func (l *ListAnalysisGroupsLogic) ListAnalysisGroups(req *types.ListAnalysisRequest) (resp *types.ListAnalysisResponse, err error) {
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
 	if err != nil {
 		return nil, err
 	}
 
-	// 分页参数
+	cursor, err := utils.ParseCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分页参数，游标模式不使用 offset
 	offset := (req.Page - 1) * req.Limit
+	if cursor != nil {
+		offset = 0
+	}
 
 	// 构建查询
-	query := l.svcCtx.DB.Where("user_id = ?", userIDStr)
+	query := l.svcCtx.DB.Where("workspace_id = ?", access.WorkspaceID)
 	if req.Status != "" {
 		query = query.Where("status = ?", req.Status)
 	}
 
-	// 查询总数
+	// 查询总数，游标模式不统计
 	var total int64
-	if err = query.Model(&models.CompetitorAnalysisGroup{}).Count(&total).Error; err != nil {
+	if cursor != nil {
+		cond, args := cursor.Condition("created_at", "id", true)
+		query = query.Where(cond, args...)
+	} else if err = query.Model(&models.CompetitorAnalysisGroup{}).Count(&total).Error; err != nil {
 		utils.LogError(l.ctx, "Database error when counting analysis groups", "error", err)
 		return nil, errors.ErrInternalServer
 	}
@@ -55,14 +69,18 @@ func (l *ListAnalysisGroupsLogic) ListAnalysisGroups(req *types.ListAnalysisRequ
 	var groups []models.CompetitorAnalysisGroup
 	err = query.Preload("MainProduct").
 		Preload("Competitors").
-		Order("created_at DESC").
+		Order(pkgtypes.KeysetOrder("created_at", "id", true)).
 		Offset(offset).
-		Limit(req.Limit).
+		Limit(req.Limit + 1).
 		Find(&groups).Error
 	if err != nil {
 		utils.LogError(l.ctx, "Database error when fetching analysis groups", "error", err)
 		return nil, errors.ErrInternalServer
 	}
+	fetched := len(groups)
+	if fetched > req.Limit {
+		groups = groups[:req.Limit]
+	}
 
 	// 转换为响应格式
 	analysisGroups := make([]types.AnalysisGroup, len(groups))
@@ -90,21 +108,30 @@ func (l *ListAnalysisGroupsLogic) ListAnalysisGroups(req *types.ListAnalysisRequ
 	}
 
 	// 计算分页信息
-	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	page := pkgtypes.NewCursorResponse(fetched, req.Limit, func() (time.Time, string) {
+		last := groups[len(groups)-1]
+		return last.CreatedAt, last.ID
+	})
+	pagination := types.Pagination{
+		Limit:      req.Limit,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}
+	if cursor == nil {
+		pagination.Page = req.Page
+		pagination.Total = int(total)
+		pagination.TotalPages = int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	}
 
 	resp = &types.ListAnalysisResponse{
-		Groups: analysisGroups,
-		Pagination: types.Pagination{
-			Page:       req.Page,
-			Limit:      req.Limit,
-			Total:      int(total),
-			TotalPages: totalPages,
-		},
+		Groups:     analysisGroups,
+		Pagination: pagination,
 	}
 
 	logger.GlobalLogger(constants.ServiceCompetitor).LogBusinessOperation(l.ctx, "list_analysis_groups", "competitor_group", "", "success",
 		"total_count", total,
-		"page", req.Page)
+		"page", req.Page,
+		"cursor", cursor != nil)
 
 	return resp, nil
 }
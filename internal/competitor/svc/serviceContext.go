@@ -1,10 +1,14 @@
 package svc
 
 import (
+	"context"
+
 	"amazonpilot/internal/competitor/config"
 	"amazonpilot/internal/competitor/middleware"
 	"amazonpilot/internal/pkg/auth"
 	"amazonpilot/internal/pkg/database"
+	"amazonpilot/internal/pkg/estimation"
+	"amazonpilot/internal/pkg/fx"
 
 	"github.com/hibiken/asynq"
 	"github.com/redis/go-redis/v9"
@@ -18,6 +22,8 @@ type ServiceContext struct {
 	RedisClient          *redis.Client
 	AsynqClient          *asynq.Client
 	JWTAuth              *auth.JWTAuth
+	FX                   *fx.Converter
+	Sales                *estimation.Model
 	RateLimitMiddleware  rest.Middleware
 }
 
@@ -53,6 +59,19 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		DB:   envCfg.Redis.DB,
 	})
 
+	// 加载汇率数据
+	fxConverter, err := fx.LoadConverter(envCfg.FX.RatesFile)
+	if err != nil {
+		panic("Failed to load fx rates: " + err.Error())
+	}
+	fx.StartProviderSync(context.Background(), fxConverter, envCfg.FX.ProviderURL)
+
+	// 加载 BSR -> 销量估算曲线
+	salesModel, err := estimation.LoadModel(envCfg.Estimation.CurvesFile)
+	if err != nil {
+		panic("Failed to load sales curves: " + err.Error())
+	}
+
 	// 初始化中间件
 	rateLimitMiddleware := middleware.NewRateLimitMiddleware()
 
@@ -62,6 +81,8 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		RedisClient:         redisClient,
 		AsynqClient:         asynqClient,
 		JWTAuth:             jwtAuth,
+		FX:                  fxConverter,
+		Sales:               salesModel,
 		RateLimitMiddleware: rateLimitMiddleware.Handle,
 	}
 }
\ No newline at end of file
@@ -37,15 +37,20 @@ type CompetitorAnalysis struct {
 }
 
 type CompetitorProduct struct {
-	ID          string  `json:"id"`
-	ASIN        string  `json:"asin"`
-	Title       string  `json:"title,omitempty"`
-	Brand       string  `json:"brand,omitempty"`
-	Price       float64 `json:"price"`
-	BSR         int     `json:"bsr,omitempty"`
-	Rating      float64 `json:"rating,omitempty"`
-	ReviewCount int     `json:"review_count"`
-	IsWinner    bool    `json:"is_winner,omitempty"`
+	ID                string  `json:"id"`
+	ASIN              string  `json:"asin"`
+	Title             string  `json:"title,omitempty"`
+	Brand             string  `json:"brand,omitempty"`
+	Price             float64 `json:"price"`
+	Currency          string  `json:"currency"`
+	OriginalPrice     float64 `json:"original_price,omitempty"`
+	OriginalCurrency  string  `json:"original_currency,omitempty"`
+	BSR               int     `json:"bsr,omitempty"`
+	Rating            float64 `json:"rating,omitempty"`
+	ReviewCount       int     `json:"review_count"`
+	EstUnitsPerDay    float64 `json:"est_units_per_day,omitempty"`   // 近30天预估日销量
+	EstMonthlyRevenue float64 `json:"est_monthly_revenue,omitempty"` // 按展示货币
+	IsWinner          bool    `json:"is_winner,omitempty"`
 }
 
 type CreateAnalysisRequest struct {
@@ -66,7 +71,8 @@ type CreateAnalysisResponse struct {
 
 type GenerateReportAsyncRequest struct {
 	AnalysisID string `path:"analysis_id"`
-	Force      bool   `json:"force,optional"` // 强制重新生成
+	Force      bool   `json:"force,optional"`    // 强制重新生成
+	Currency   string `json:"currency,optional"` // 报告展示货币
 }
 
 type GenerateReportAsyncResponse struct {
@@ -78,7 +84,8 @@ type GenerateReportAsyncResponse struct {
 
 type GenerateReportRequest struct {
 	AnalysisID string `path:"analysis_id"`
-	Force      bool   `json:"force,optional"` // 强制重新生成
+	Force      bool   `json:"force,optional"`    // 强制重新生成
+	Currency   string `json:"currency,optional"` // 报告展示货币
 }
 
 type GenerateReportResponse struct {
@@ -90,6 +97,7 @@ type GenerateReportResponse struct {
 
 type GetAnalysisRequest struct {
 	AnalysisID string `path:"analysis_id"`
+	Currency   string `form:"currency,optional"` // 展示货币，默认USD
 }
 
 type GetAnalysisResponse struct {
@@ -104,6 +112,17 @@ type GetAnalysisResponse struct {
 	LastUpdated     string              `json:"last_updated"`
 }
 
+type GetDealsHistoryRequest struct {
+	AnalysisID string `path:"analysis_id"`
+	Period     string `form:"period,optional"` // 7d, 30d, 90d
+}
+
+type GetDealsHistoryResponse struct {
+	AnalysisID string         `json:"analysis_id"`
+	Period     string         `json:"period"`
+	Products   []ProductDeals `json:"products"`
+}
+
 type GetReportStatusRequest struct {
 	AnalysisID string `path:"analysis_id"`
 	TaskID     string `path:"task_id,optional"`
@@ -130,6 +149,7 @@ type HealthResponse struct {
 type ListAnalysisRequest struct {
 	Page   int    `form:"page,default=1"`
 	Limit  int    `form:"limit,default=20"`
+	Cursor string `form:"cursor,optional"` // 上一页返回的 next_cursor，提供时按游标分页 (不统计 total)
 	Status string `form:"status,optional"`
 }
 
@@ -139,10 +159,12 @@ type ListAnalysisResponse struct {
 }
 
 type Pagination struct {
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	Total      int `json:"total"`
-	TotalPages int `json:"total_pages"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor,omitempty"` // 游标模式下的下一页游标，为空表示没有更多数据
+	HasMore    bool   `json:"has_more,omitempty"`
 }
 
 type PingResponse struct {
@@ -152,10 +174,32 @@ type PingResponse struct {
 }
 
 type PriceRange struct {
-	Min     float64 `json:"min"`
-	Max     float64 `json:"max"`
-	Average float64 `json:"average"`
-	Median  float64 `json:"median"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Average  float64 `json:"average"`
+	Median   float64 `json:"median"`
+	Currency string  `json:"currency,omitempty"`
+}
+
+type ProductDeals struct {
+	ProductID      string      `json:"product_id"`
+	ASIN           string      `json:"asin"`
+	Title          string      `json:"title"`
+	IsMainProduct  bool        `json:"is_main_product"`
+	PromotionCount int         `json:"promotion_count"`
+	PromotedHours  float64     `json:"promoted_hours"`
+	Promotions     []Promotion `json:"promotions"`
+}
+
+type Promotion struct {
+	Type          string  `json:"type"` // lightning_deal, prime_day, deal_of_the_day, limited_time_deal, deal, coupon, discount
+	StartedAt     string  `json:"started_at"`
+	EndedAt       string  `json:"ended_at,omitempty"`
+	Ongoing       bool    `json:"ongoing"`
+	DurationHours float64 `json:"duration_hours"`
+	MaxDiscount   float64 `json:"max_discount"`
+	LowestPrice   float64 `json:"lowest_price"`
+	CouponText    string  `json:"coupon_text,omitempty"`
 }
 
 type RatingAnalysis struct {
@@ -10,7 +10,9 @@ import (
 	"amazonpilot/internal/optimization/types"
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
@@ -31,7 +33,8 @@ func NewCreateOptimizationTaskLogic(ctx context.Context, svcCtx *svc.ServiceCont
 }
 
 func (l *CreateOptimizationTaskLogic) CreateOptimizationTask(req *types.CreateOptimizationRequest) (resp *types.CreateOptimizationResponse, err error) {
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
 	if err != nil {
 		return nil, err
 	}
@@ -48,9 +51,15 @@ func (l *CreateOptimizationTaskLogic) CreateOptimizationTask(req *types.CreateOp
 		return nil, errors.ErrInternalServer
 	}
 
+	// 优化分析计入工作区计划的月度报告数
+	if err := quota.CheckReports(l.ctx, l.svcCtx.DB, access.WorkspaceID); err != nil {
+		return nil, err
+	}
+
 	// 创建优化分析记录
 	analysis := models.OptimizationAnalysis{
-		UserID:       userIDStr,
+		UserID:       access.UserID,
+		WorkspaceID:  access.WorkspaceID,
 		ProductID:    req.ProductID,
 		AnalysisType: req.OptimizationType,
 		Status:       "pending",
@@ -8,6 +8,7 @@ import (
 	"amazonpilot/internal/pkg/logger"
 	"amazonpilot/internal/pkg/models"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
 	"context"
 
 	"github.com/zeromicro/go-zero/core/logx"
@@ -28,28 +29,29 @@ func NewGetOptimizationStatsLogic(ctx context.Context, svcCtx *svc.ServiceContex
 }
 
 func (l *GetOptimizationStatsLogic) GetOptimizationStats() (resp *types.GetStatsResponse, err error) {
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
 	if err != nil {
 		return nil, err
 	}
 
 	// 统计总任务数
 	var totalTasks int64
-	if err = l.svcCtx.DB.Model(&models.OptimizationAnalysis{}).Where("user_id = ?", userIDStr).Count(&totalTasks).Error; err != nil {
+	if err = l.svcCtx.DB.Model(&models.OptimizationAnalysis{}).Where("workspace_id = ?", access.WorkspaceID).Count(&totalTasks).Error; err != nil {
 		utils.LogError(l.ctx, "Database error when counting total tasks", "error", err)
 		return nil, errors.ErrInternalServer
 	}
 
 	// 统计待处理任务数
 	var pendingTasks int64
-	if err = l.svcCtx.DB.Model(&models.OptimizationAnalysis{}).Where("user_id = ? AND status = ?", userIDStr, "pending").Count(&pendingTasks).Error; err != nil {
+	if err = l.svcCtx.DB.Model(&models.OptimizationAnalysis{}).Where("workspace_id = ? AND status = ?", access.WorkspaceID, "pending").Count(&pendingTasks).Error; err != nil {
 		utils.LogError(l.ctx, "Database error when counting pending tasks", "error", err)
 		return nil, errors.ErrInternalServer
 	}
 
 	// 统计已完成任务数
 	var completedTasks int64
-	if err = l.svcCtx.DB.Model(&models.OptimizationAnalysis{}).Where("user_id = ? AND status = ?", userIDStr, "completed").Count(&completedTasks).Error; err != nil {
+	if err = l.svcCtx.DB.Model(&models.OptimizationAnalysis{}).Where("workspace_id = ? AND status = ?", access.WorkspaceID, "completed").Count(&completedTasks).Error; err != nil {
 		utils.LogError(l.ctx, "Database error when counting completed tasks", "error", err)
 		return nil, errors.ErrInternalServer
 	}
@@ -58,7 +60,7 @@ func (l *GetOptimizationStatsLogic) GetOptimizationStats() (resp *types.GetStats
 	var avgImpactScore float64
 	row := l.svcCtx.DB.Table("optimization_suggestions").
 		Joins("JOIN optimization_analyses ON optimization_suggestions.analysis_id = optimization_analyses.id").
-		Where("optimization_analyses.user_id = ?", userIDStr).
+		Where("optimization_analyses.workspace_id = ?", access.WorkspaceID).
 		Select("AVG(optimization_suggestions.impact_score) as avg_score").
 		Row()
 
@@ -11,6 +11,8 @@ import (
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/models"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	pkgtypes "amazonpilot/pkg/types"
 
 	"github.com/zeromicro/go-zero/core/logx"
 )
@@ -30,16 +32,25 @@ func NewListOptimizationTasksLogic(ctx context.Context, svcCtx *svc.ServiceConte
 }
 
 func (l *ListOptimizationTasksLogic) ListOptimizationTasks(req *types.ListOptimizationRequest) (resp *types.ListOptimizationResponse, err error) {
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
 	if err != nil {
 		return nil, err
 	}
 
-	// 分页参数
+	cursor, err := utils.ParseCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分页参数，游标模式不使用 offset
 	offset := (req.Page - 1) * req.Limit
+	if cursor != nil {
+		offset = 0
+	}
 
 	// 构建查询
-	query := l.svcCtx.DB.Where("user_id = ?", userIDStr)
+	query := l.svcCtx.DB.Where("workspace_id = ?", access.WorkspaceID)
 	if req.Status != "" {
 		query = query.Where("status = ?", req.Status)
 	}
@@ -47,9 +58,12 @@ func (l *ListOptimizationTasksLogic) ListOptimizationTasks(req *types.ListOptimi
 		query = query.Where("suggestions.priority = ?", req.Priority)
 	}
 
-	// 查询总数
+	// 查询总数，游标模式不统计
 	var total int64
-	if err = query.Model(&models.OptimizationAnalysis{}).Count(&total).Error; err != nil {
+	if cursor != nil {
+		cond, args := cursor.Condition("started_at", "id", true)
+		query = query.Where(cond, args...)
+	} else if err = query.Model(&models.OptimizationAnalysis{}).Count(&total).Error; err != nil {
 		utils.LogError(l.ctx, "Database error when counting optimization tasks", "error", err)
 		return nil, errors.ErrInternalServer
 	}
@@ -58,14 +72,18 @@ func (l *ListOptimizationTasksLogic) ListOptimizationTasks(req *types.ListOptimi
 	var analyses []models.OptimizationAnalysis
 	err = query.Preload("Product").
 		Preload("Suggestions").
-		Order("started_at DESC").
+		Order(pkgtypes.KeysetOrder("started_at", "id", true)).
 		Offset(offset).
-		Limit(req.Limit).
+		Limit(req.Limit + 1).
 		Find(&analyses).Error
 	if err != nil {
 		utils.LogError(l.ctx, "Database error when fetching optimization tasks", "error", err)
 		return nil, errors.ErrInternalServer
 	}
+	fetched := len(analyses)
+	if fetched > req.Limit {
+		analyses = analyses[:req.Limit]
+	}
 
 	// 转换为响应格式
 	optimizationTasks := make([]types.OptimizationTask, len(analyses))
@@ -107,21 +125,30 @@ func (l *ListOptimizationTasksLogic) ListOptimizationTasks(req *types.ListOptimi
 	}
 
 	// 计算分页信息
-	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	page := pkgtypes.NewCursorResponse(fetched, req.Limit, func() (time.Time, string) {
+		last := analyses[len(analyses)-1]
+		return last.StartedAt, last.ID
+	})
+	pagination := types.Pagination{
+		Limit:      req.Limit,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}
+	if cursor == nil {
+		pagination.Page = req.Page
+		pagination.Total = int(total)
+		pagination.TotalPages = int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	}
 
 	resp = &types.ListOptimizationResponse{
-		Tasks: optimizationTasks,
-		Pagination: types.Pagination{
-			Page:       req.Page,
-			Limit:      req.Limit,
-			Total:      int(total),
-			TotalPages: totalPages,
-		},
+		Tasks:      optimizationTasks,
+		Pagination: pagination,
 	}
 
 	logger.GlobalLogger(constants.ServiceOptimization).LogBusinessOperation(l.ctx, "list_optimization_tasks", "optimization_task", "", "success",
 		"total_count", total,
-		"page", req.Page)
+		"page", req.Page,
+		"cursor", cursor != nil)
 
 	return resp, nil
 }
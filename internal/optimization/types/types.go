@@ -55,6 +55,7 @@ type HealthResponse struct {
 type ListOptimizationRequest struct {
 	Page     int    `form:"page,default=1"`
 	Limit    int    `form:"limit,default=20"`
+	Cursor   string `form:"cursor,optional"` // 上一页返回的 next_cursor，提供时按游标分页 (不统计 total)
 	Status   string `form:"status,optional"`
 	Priority string `form:"priority,optional"`
 }
@@ -79,10 +80,12 @@ type OptimizationTask struct {
 }
 
 type Pagination struct {
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	Total      int `json:"total"`
-	TotalPages int `json:"total_pages"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor,omitempty"` // 游标模式下的下一页游标，为空表示没有更多数据
+	HasMore    bool   `json:"has_more,omitempty"`
 }
 
 type PingResponse struct {
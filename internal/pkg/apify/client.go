@@ -16,9 +16,10 @@ import (
 
 // Client Apify API客户端
 type Client struct {
-	apiToken   string
-	baseURL    string
-	httpClient *http.Client
+	apiToken    string
+	baseURL     string
+	offersActor string // 抓取全部卖家报价的actor，为空时不抓取
+	httpClient  *http.Client
 }
 
 // ProductData Amazon产品数据结构
@@ -36,6 +37,8 @@ type ProductData struct {
 	ReviewCount  int       `json:"countReview,omitempty"`
 	BSR          int       `json:"salesRank,omitempty"`
 	BSRCategory  string    `json:"salesRankCategory,omitempty"`
+	CategoryRanks []CategoryRank `json:"bestsellerRanks,omitempty"` // 根类目及各小类目排名
+	BSRText      string    `json:"bestSellersRank,omitempty"`      // 页面原始排名文本，例如 "#1,234 in Home & Kitchen #5 in Kitchen Utensil Sets"
 	Images       []string  `json:"imageUrlList,omitempty"`
 	Description  string    `json:"productDescription,omitempty"`
 	Features     []string  `json:"features,omitempty"`  // 实际 API 返回 features
@@ -44,12 +47,64 @@ type ProductData struct {
 	Prime        bool      `json:"prime,omitempty"`
 	Seller       string    `json:"soldBy,omitempty"`
 	FulfilledBy  string    `json:"fulfilledBy,omitempty"`
+	SellerID     string    `json:"sellerId,omitempty"`
+	ShippingPrice float64  `json:"shippingPrice,omitempty"`
 	BuyBoxPrice  *float64  `json:"buyBoxPrice,omitempty"`  // 标准化后的Buy Box价格
+	Offers       []Offer   `json:"offers,omitempty"`       // 全部卖家报价 (需要 offers actor)
+	Deal         bool      `json:"deal,omitempty"`
+	PriceSaving  string    `json:"priceSaving,omitempty"` // 例如 "-53%"
+	DealBadge    string    `json:"dealBadge,omitempty"`   // 例如 "Lightning Deal", "Prime Day Deal"
+	Coupon       string    `json:"coupon,omitempty"`      // 例如 "Save 10% with coupon"
+	ParentASIN   string      `json:"parentAsin,omitempty"` // 变体父ASIN (部分actor版本返回)
+	Variations   []Variation `json:"variations,omitempty"` // 变体维度 (颜色、尺寸等)
 	ScrapedAt    time.Time `json:"scrapedAt"`
 	URL          string    `json:"url,omitempty"`
 	StatusCode   int       `json:"statusCode,omitempty"`
 }
 
+// Variation 变体维度，例如 color_name 下的所有颜色
+type Variation struct {
+	VariationName string           `json:"variationName"`
+	Values        []VariationValue `json:"values"`
+}
+
+// VariationValue 变体维度的取值，每个取值对应一个子ASIN
+type VariationValue struct {
+	Value     string  `json:"value"`
+	DpURL     string  `json:"dpUrl,omitempty"`
+	Selected  bool    `json:"selected"`
+	Available bool    `json:"available"`
+	Price     float64 `json:"price,omitempty"`
+	ImageURL  string  `json:"imageUrl,omitempty"`
+	ASIN      string  `json:"asin"`
+}
+
+// CategoryRank 单个类目的BSR排名
+type CategoryRank struct {
+	Rank     int    `json:"rank"`
+	Category string `json:"category"`
+	URL      string `json:"url,omitempty"`
+}
+
+// Offer 单个卖家报价
+type Offer struct {
+	SellerName    string  `json:"sellerName"`
+	SellerID      string  `json:"sellerId,omitempty"`
+	Price         float64 `json:"price"`
+	ShippingPrice float64 `json:"shippingPrice,omitempty"`
+	Currency      string  `json:"currency,omitempty"`
+	Condition     string  `json:"condition,omitempty"` // new, used - like new, ...
+	FBA           bool    `json:"fulfilledByAmazon"`
+	Prime         bool    `json:"prime"`
+	BuyBoxWinner  bool    `json:"buyBoxWinner"`
+}
+
+// OffersResult offers actor 按ASIN返回的报价列表
+type OffersResult struct {
+	ASIN   string  `json:"asin"`
+	Offers []Offer `json:"offers"`
+}
+
 // RunInput Apify Actor运行输入 (简化为仅必需字段)
 type RunInput struct {
 	URLs []string `json:"urls"`
@@ -75,6 +130,16 @@ func NewClient(apiToken string) *Client {
 	}
 }
 
+// SetOffersActor 设置抓取全部卖家报价的actor (例如 "username~amazon-offers-scraper")
+func (c *Client) SetOffersActor(actor string) {
+	c.offersActor = actor
+}
+
+// OffersEnabled 是否配置了 offers actor
+func (c *Client) OffersEnabled() bool {
+	return c.offersActor != ""
+}
+
 // RunAmazonProductActor 运行Amazon产品数据抓取Actor
 func (c *Client) RunAmazonProductActor(ctx context.Context, asins []string) (*RunResponse, error) {
 	// 使用经过验证的Amazon Product Details Actor (使用actor ID而不是name)
@@ -306,6 +371,8 @@ type ApifyResponseWithFeatures struct {
 	ReviewCount  int      `json:"countReview,omitempty"`  // 实际 API 返回 countReview
 	BSR          int      `json:"salesRank,omitempty"`
 	BSRCategory  string   `json:"salesRankCategory,omitempty"`
+	CategoryRanks []CategoryRank `json:"bestsellerRanks,omitempty"`
+	BSRText      string   `json:"bestSellersRank,omitempty"`
 	Images       []string `json:"imageUrlList,omitempty"` // 实际 API 返回 imageUrlList
 	Description  string   `json:"productDescription,omitempty"`
 	Features     []string `json:"features,omitempty"`     // 实际 API 返回 features
@@ -313,7 +380,16 @@ type ApifyResponseWithFeatures struct {
 	Prime        bool     `json:"prime,omitempty"`
 	Seller       string   `json:"soldBy,omitempty"`
 	FulfilledBy  string   `json:"fulfilledBy,omitempty"`
+	SellerID     string   `json:"sellerId,omitempty"`
+	ShippingPrice float64 `json:"shippingPrice,omitempty"`
 	BuyBoxUsed   *float64 `json:"buyBoxUsed,omitempty"`   // Apify返回的Buy Box价格
+	Offers       []Offer  `json:"offers,omitempty"`
+	Deal         bool     `json:"deal,omitempty"`
+	PriceSaving  string   `json:"priceSaving,omitempty"`
+	DealBadge    string   `json:"dealBadge,omitempty"`
+	Coupon       string   `json:"coupon,omitempty"`
+	ParentASIN   string      `json:"parentAsin,omitempty"`
+	Variations   []Variation `json:"variations,omitempty"`
 	URL          string   `json:"url,omitempty"`
 	StatusCode   int      `json:"statusCode,omitempty"`
 }
@@ -378,6 +454,8 @@ func NormalizeApifyResponse(rawJSON []byte) (*ProductData, error) {
 		ReviewCount:  response.ReviewCount,
 		BSR:          response.BSR,
 		BSRCategory:  response.BSRCategory,
+		CategoryRanks: response.CategoryRanks,
+		BSRText:      response.BSRText,
 		Images:       response.Images, // 从 imageUrlList 映射到 Images
 		Description:  response.Description,
 		BulletPoints: bulletPoints,   // 从 features 映射到 BulletPoints
@@ -385,7 +463,16 @@ func NormalizeApifyResponse(rawJSON []byte) (*ProductData, error) {
 		Prime:        response.Prime,
 		Seller:       response.Seller,
 		FulfilledBy:  response.FulfilledBy,
+		SellerID:     response.SellerID,
+		ShippingPrice: response.ShippingPrice,
 		BuyBoxPrice:  response.BuyBoxUsed,  // 映射buyBoxUsed到BuyBoxPrice
+		Offers:       response.Offers,
+		Deal:         response.Deal,
+		PriceSaving:  response.PriceSaving,
+		DealBadge:    response.DealBadge,
+		Coupon:       response.Coupon,
+		ParentASIN:   response.ParentASIN,
+		Variations:   response.Variations,
 		ScrapedAt:    now,
 		URL:          response.URL,
 		StatusCode:   response.StatusCode,
@@ -481,3 +568,58 @@ func (c *Client) FetchProductData(ctx context.Context, asins []string, timeout t
 
 	return products, nil
 }
+
+// FetchOffers 同步获取ASIN的全部卖家报价 (使用run-sync-get-dataset-items API)
+func (c *Client) FetchOffers(ctx context.Context, asin string, timeout time.Duration) ([]Offer, error) {
+	if !c.OffersEnabled() {
+		return nil, fmt.Errorf("offers actor is not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	inputBytes, err := json.Marshal(RunInput{
+		URLs: []string{fmt.Sprintf("https://www.amazon.com/dp/%s", asin)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal input: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/acts/%s/run-sync-get-dataset-items?token=%s", c.baseURL, c.offersActor, c.apiToken)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(inputBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var results []OffersResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode offers: %w", err)
+	}
+
+	offers := []Offer{}
+	for _, result := range results {
+		if result.ASIN != "" && result.ASIN != asin {
+			continue
+		}
+		offers = append(offers, result.Offers...)
+	}
+
+	slog.Info("Offers fetch completed",
+		"asin", asin,
+		"offers_count", len(offers),
+	)
+
+	return offers, nil
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"encoding/json"
+	"time"
+
+	"amazonpilot/internal/pkg/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 产品数据缓存配置 (需求: 产品数据缓存 24-48 小时)
+const (
+	ProductDataVersion = 2
+	ProductDataMinTTL  = 24 * time.Hour
+	ProductDataMaxTTL  = 48 * time.Hour
+)
+
+// ProductData 按产品缓存的产品内容和最新数据，不包含用户相关的追踪信息
+// 字段变化时需要递增 ProductDataVersion
+type ProductData struct {
+	ProductID            string    `json:"product_id"`
+	ASIN                 string    `json:"asin"`
+	ParentASIN           string    `json:"parent_asin,omitempty"`
+	Title                string    `json:"title,omitempty"`
+	Brand                string    `json:"brand,omitempty"`
+	Category             string    `json:"category,omitempty"`
+	Description          string    `json:"description,omitempty"`
+	Images               []string  `json:"images,omitempty"`
+	BulletPoints         []string  `json:"bullet_points,omitempty"`
+	LastUpdatedAt        time.Time `json:"last_updated_at"`
+	HasSnapshot          bool      `json:"has_snapshot"`
+	CurrentPrice         float64   `json:"current_price"`
+	Currency             string    `json:"currency"`
+	BuyBoxPrice          float64   `json:"buy_box_price,omitempty"`
+	BSR                  int       `json:"bsr,omitempty"`
+	BSRCategory          string    `json:"bsr_category,omitempty"`
+	Rating               float64   `json:"rating,omitempty"`
+	ReviewCount          int       `json:"review_count"`
+	Availability         string    `json:"availability,omitempty"`
+	PriceChange24h       float64   `json:"price_change_24h,omitempty"`
+	BSRChange24h         int       `json:"bsr_change_24h,omitempty"`
+	ReviewCountChange24h int       `json:"review_count_change_24h,omitempty"`
+}
+
+// NewProductStore 创建产品数据缓存
+func NewProductStore(client *redis.Client) *Store[ProductData] {
+	return NewStore[ProductData](client, "product_data", ProductDataPrefix, ProductDataVersion, ProductDataMinTTL, ProductDataMaxTTL)
+}
+
+// NewProductData 由产品及其最新快照 (需预加载 LatestSnapshot) 生成缓存数据
+func NewProductData(product *models.Product) ProductData {
+	data := ProductData{
+		ProductID:     product.ID,
+		ASIN:          product.ASIN,
+		ParentASIN:    stringValue(product.ParentASIN),
+		Title:         stringValue(product.Title),
+		Brand:         stringValue(product.Brand),
+		Category:      stringValue(product.Category),
+		Description:   stringValue(product.Description),
+		LastUpdatedAt: product.LastUpdatedAt,
+	}
+	if product.Images != nil {
+		json.Unmarshal(product.Images, &data.Images)
+	}
+	if product.BulletPoints != nil {
+		json.Unmarshal(product.BulletPoints, &data.BulletPoints)
+	}
+
+	// 尚未刷新过的产品没有快照
+	snapshot := product.LatestSnapshot
+	if snapshot == nil {
+		return data
+	}
+	data.HasSnapshot = true
+	data.CurrentPrice = snapshot.Price
+	data.Currency = snapshot.Currency
+	data.BuyBoxPrice = snapshot.Price // 没有BuyBox价格时使用当前价格
+	if snapshot.BuyBoxPrice != nil {
+		data.BuyBoxPrice = *snapshot.BuyBoxPrice
+	}
+	if snapshot.BSRRank != nil {
+		data.BSR = *snapshot.BSRRank
+	}
+	data.BSRCategory = stringValue(snapshot.BSRCategory)
+	if snapshot.Rating != nil {
+		data.Rating = *snapshot.Rating
+	}
+	data.ReviewCount = snapshot.ReviewCount
+	data.Availability = snapshot.AvailabilityState
+	if snapshot.PriceChange24h != nil {
+		data.PriceChange24h = *snapshot.PriceChange24h
+	}
+	if snapshot.BSRChange24h != nil {
+		data.BSRChange24h = *snapshot.BSRChange24h
+	}
+	if snapshot.ReviewCountChange24h != nil {
+		data.ReviewCountChange24h = *snapshot.ReviewCountChange24h
+	}
+	return data
+}
+
+func stringValue(s *string) string {
+	if s != nil {
+		return *s
+	}
+	return ""
+}
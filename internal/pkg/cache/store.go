@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"amazonpilot/internal/pkg/metrics"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/syncx"
+)
+
+// Store 带类型的 Redis 缓存，值以 JSON 保存
+// 键中包含版本号，缓存数据结构变化时递增版本即可让旧数据失效；
+// 过期时间在 [minTTL, maxTTL] 之间随机，避免大量键同时过期；
+// 同一进程内对相同键的并发回源会合并为一次加载
+type Store[T any] struct {
+	client  *redis.Client
+	name    string
+	prefix  string
+	version int
+	minTTL  time.Duration
+	maxTTL  time.Duration
+	flight  syncx.SingleFlight
+}
+
+// NewStore 创建缓存，name 用作指标标签
+func NewStore[T any](client *redis.Client, name, prefix string, version int, minTTL, maxTTL time.Duration) *Store[T] {
+	if maxTTL < minTTL {
+		maxTTL = minTTL
+	}
+	return &Store[T]{
+		client:  client,
+		name:    name,
+		prefix:  prefix,
+		version: version,
+		minTTL:  minTTL,
+		maxTTL:  maxTTL,
+		flight:  syncx.NewSingleFlight(),
+	}
+}
+
+// Key 返回带版本号的缓存键
+func (s *Store[T]) Key(id string) string {
+	return fmt.Sprintf("%sv%d:%s", s.prefix, s.version, id)
+}
+
+// TTL 返回本次写入使用的过期时间
+func (s *Store[T]) TTL() time.Duration {
+	if s.maxTTL == s.minTTL {
+		return s.minTTL
+	}
+	return s.minTTL + time.Duration(rand.Int63n(int64(s.maxTTL-s.minTTL)))
+}
+
+// Get 读取缓存，未命中时 ok 为 false
+func (s *Store[T]) Get(ctx context.Context, id string) (value T, ok bool, err error) {
+	data, err := s.client.Get(ctx, s.Key(id)).Bytes()
+	if err == redis.Nil {
+		metrics.RecordCacheLookup(s.name, "miss", 1)
+		return value, false, nil
+	} else if err != nil {
+		metrics.RecordCacheLookup(s.name, "error", 1)
+		return value, false, err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		// 无法解析的数据按未命中处理，由下一次写入覆盖
+		metrics.RecordCacheLookup(s.name, "miss", 1)
+		return value, false, nil
+	}
+	metrics.RecordCacheLookup(s.name, "hit", 1)
+	return value, true, nil
+}
+
+// GetMany 批量读取缓存，只返回命中的数据
+func (s *Store[T]) GetMany(ctx context.Context, ids []string) (map[string]T, error) {
+	values := make(map[string]T, len(ids))
+	if len(ids) == 0 {
+		return values, nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = s.Key(id)
+	}
+	results, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		metrics.RecordCacheLookup(s.name, "error", len(ids))
+		return values, err
+	}
+	for i, result := range results {
+		data, ok := result.(string)
+		if !ok {
+			continue
+		}
+		var value T
+		if err := json.Unmarshal([]byte(data), &value); err == nil {
+			values[ids[i]] = value
+		}
+	}
+	metrics.RecordCacheLookup(s.name, "hit", len(values))
+	metrics.RecordCacheLookup(s.name, "miss", len(ids)-len(values))
+	return values, nil
+}
+
+// Set 写入缓存 (数据更新后的写穿)
+func (s *Store[T]) Set(ctx context.Context, id string, value T) error {
+	return s.SetMany(ctx, map[string]T{id: value})
+}
+
+// SetMany 批量写入缓存 (数据更新后的写穿)
+func (s *Store[T]) SetMany(ctx context.Context, values map[string]T) error {
+	return s.write(ctx, values, "write_through", false)
+}
+
+// Delete 删除缓存
+func (s *Store[T]) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = s.Key(id)
+	}
+	return s.client.Del(ctx, keys...).Err()
+}
+
+// GetOrLoad 读取缓存，未命中时调用 load 回源并回填缓存
+// Redis 不可用时直接回源，不影响请求
+func (s *Store[T]) GetOrLoad(ctx context.Context, id string, load func(ctx context.Context) (T, error)) (T, error) {
+	if value, ok, err := s.Get(ctx, id); ok && err == nil {
+		return value, nil
+	}
+	loaded, err := s.load(ctx, s.Key(id), func() (map[string]T, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]T{id: value}, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return loaded[id], nil
+}
+
+// GetOrLoadMany 批量读取缓存，未命中的ID一次性交给 load 回源并回填缓存
+// load 未返回的ID (例如数据不存在) 不会出现在结果中
+func (s *Store[T]) GetOrLoadMany(ctx context.Context, ids []string, load func(ctx context.Context, ids []string) (map[string]T, error)) (map[string]T, error) {
+	values, _ := s.GetMany(ctx, ids)
+	missing := make([]string, 0, len(ids)-len(values))
+	for _, id := range ids {
+		if _, ok := values[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	// 相同的未命中集合 (例如同一页列表的并发请求) 合并为一次加载
+	sorted := append([]string(nil), missing...)
+	sort.Strings(sorted)
+	loaded, err := s.load(ctx, s.prefix+"batch:"+strings.Join(sorted, ","), func() (map[string]T, error) {
+		return load(ctx, missing)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for id, value := range loaded {
+		values[id] = value
+	}
+	return values, nil
+}
+
+// load 合并相同 flightKey 的并发回源，只有实际执行加载的请求负责回填缓存
+func (s *Store[T]) load(ctx context.Context, flightKey string, fn func() (map[string]T, error)) (map[string]T, error) {
+	result, fresh, err := s.flight.DoEx(flightKey, func() (any, error) {
+		start := time.Now()
+		values, err := fn()
+		metrics.CacheLoadDuration.WithLabelValues(s.name).Observe(float64(time.Since(start).Milliseconds()))
+		if err != nil {
+			return nil, err
+		}
+		// 回填只写入不存在的键，回填失败不影响本次请求
+		_ = s.write(ctx, values, "load", true)
+		return values, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		metrics.CacheCoalescedTotal.WithLabelValues(s.name).Inc()
+	}
+	return result.(map[string]T), nil
+}
+
+// write 使用 pipeline 批量写入缓存
+// onlyIfAbsent 时使用 SET NX：回源期间 worker 写穿的新数据不会被回源时读到的旧数据覆盖
+func (s *Store[T]) write(ctx context.Context, values map[string]T, source string, onlyIfAbsent bool) error {
+	if len(values) == 0 {
+		return nil
+	}
+	pipe := s.client.Pipeline()
+	nxCmds := make([]*redis.BoolCmd, 0, len(values))
+	for id, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if onlyIfAbsent {
+			nxCmds = append(nxCmds, pipe.SetNX(ctx, s.Key(id), data, s.TTL()))
+		} else {
+			pipe.Set(ctx, s.Key(id), data, s.TTL())
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	written := len(values)
+	if onlyIfAbsent {
+		written = 0
+		for _, cmd := range nxCmds {
+			if cmd.Val() {
+				written++
+			}
+		}
+	}
+	metrics.RecordCacheWrite(s.name, source, written)
+	return nil
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"amazonpilot/internal/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+func TestStoreKeyAndTTL(t *testing.T) {
+	store := NewProductStore(nil)
+
+	// 键包含版本号，与旧的未版本化键不冲突
+	assert.Equal(t, "amazon_pilot:product_data:v2:p1", store.Key("p1"))
+	assert.NotEqual(t, ProductDataPrefix+"p1", store.Key("p1"))
+
+	for i := 0; i < 100; i++ {
+		ttl := store.TTL()
+		assert.GreaterOrEqual(t, ttl, ProductDataMinTTL)
+		assert.Less(t, ttl, ProductDataMaxTTL)
+	}
+
+	fixed := NewStore[string](nil, "test", "amazon_pilot:test:", 1, time.Hour, 0)
+	assert.Equal(t, time.Hour, fixed.TTL())
+}
+
+func TestNewProductData(t *testing.T) {
+	title := "Echo Dot"
+	updatedAt := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	product := models.Product{
+		ID:            "p1",
+		ASIN:          "B08N5WRWNW",
+		Title:         &title,
+		Images:        datatypes.JSON(`["a.jpg","b.jpg"]`),
+		LastUpdatedAt: updatedAt,
+	}
+
+	// 尚未刷新过的产品没有快照
+	data := NewProductData(&product)
+	assert.Equal(t, "p1", data.ProductID)
+	assert.Equal(t, "Echo Dot", data.Title)
+	assert.Equal(t, []string{"a.jpg", "b.jpg"}, data.Images)
+	assert.Equal(t, updatedAt, data.LastUpdatedAt)
+	assert.False(t, data.HasSnapshot)
+	assert.Zero(t, data.CurrentPrice)
+
+	bsr, change := 120, 5.5
+	product.LatestSnapshot = &models.LatestSnapshot{
+		ProductID:         "p1",
+		Price:             22,
+		Currency:          "USD",
+		BSRRank:           &bsr,
+		ReviewCount:       130,
+		AvailabilityState: "in_stock",
+		PriceChange24h:    &change,
+	}
+	data = NewProductData(&product)
+	assert.True(t, data.HasSnapshot)
+	assert.Equal(t, 22.0, data.CurrentPrice)
+	assert.Equal(t, 22.0, data.BuyBoxPrice)
+	assert.Equal(t, 120, data.BSR)
+	assert.Equal(t, 130, data.ReviewCount)
+	assert.Equal(t, "in_stock", data.Availability)
+	assert.Equal(t, 5.5, data.PriceChange24h)
+	assert.Zero(t, data.BSRChange24h)
+}
@@ -35,6 +35,15 @@ type EnvConfig struct {
 
 	// Dashboard配置
 	Dashboard DashboardConfig
+
+	// 汇率配置
+	FX FXConfig
+
+	// 销量估算配置
+	Estimation EstimationConfig
+
+	// 历史数据导出配置
+	Export ExportConfig
 }
 
 // DatabaseConfig 数据库配置
@@ -69,6 +78,7 @@ type APIKeysConfig struct {
 // WorkerConfig Worker配置
 type WorkerConfig struct {
 	Concurrency int
+	OffersActor string // 抓取全部卖家报价的 Apify actor，为空时只记录 Buy Box 卖家
 }
 
 // SchedulerConfig 调度器配置
@@ -81,6 +91,24 @@ type DashboardConfig struct {
 	Port string
 }
 
+// FXConfig 汇率配置
+type FXConfig struct {
+	RatesFile       string // 汇率历史文件路径 (JSON)
+	ProviderURL     string // Frankfurter 兼容的汇率API，配置后每天同步最新汇率
+	DefaultCurrency string // 默认展示货币
+}
+
+// EstimationConfig 销量估算配置
+type EstimationConfig struct {
+	CurvesFile string // 按类目的 BSR -> 销量曲线文件路径 (JSON)，为空时使用内置曲线
+}
+
+// ExportConfig 历史数据导出配置
+type ExportConfig struct {
+	Dir       string        // 后台导出文件目录，product 服务与 worker 需共享
+	Retention time.Duration // 导出文件保留时间
+}
+
 // LoadEnvConfig 加载环境变量配置
 func LoadEnvConfig(serviceName constants.ServiceName) (*EnvConfig, error) {
 	cfg := &EnvConfig{
@@ -115,6 +143,7 @@ func LoadEnvConfig(serviceName constants.ServiceName) (*EnvConfig, error) {
 
 	// Worker配置
 	cfg.Worker.Concurrency = getEnvAsInt("WORKER_CONCURRENCY", 10)
+	cfg.Worker.OffersActor = os.Getenv("APIFY_OFFERS_ACTOR")
 
 	// 调度器配置
 	cfg.Scheduler.ProductUpdateInterval = getEnvWithDefault("SCHEDULER_PRODUCT_UPDATE_INTERVAL", "1h")
@@ -122,6 +151,18 @@ func LoadEnvConfig(serviceName constants.ServiceName) (*EnvConfig, error) {
 	// Dashboard配置
 	cfg.Dashboard.Port = getEnvWithDefault("DASHBOARD_PORT", "5555")
 
+	// 汇率配置
+	cfg.FX.RatesFile = os.Getenv("FX_RATES_FILE")
+	cfg.FX.ProviderURL = os.Getenv("FX_PROVIDER_URL")
+	cfg.FX.DefaultCurrency = getEnvWithDefault("FX_DEFAULT_CURRENCY", "USD")
+
+	// 销量估算配置
+	cfg.Estimation.CurvesFile = os.Getenv("SALES_CURVES_FILE")
+
+	// 历史数据导出配置
+	cfg.Export.Dir = getEnvWithDefault("EXPORT_DIR", "/tmp/amazonpilot/exports")
+	cfg.Export.Retention = time.Duration(getEnvAsInt("EXPORT_RETENTION_HOURS", 72)) * time.Hour
+
 	// 记录配置加载成功
 	slog.Info("Environment configuration loaded",
 		"service", serviceName.String(),
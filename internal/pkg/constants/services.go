@@ -30,6 +30,9 @@ const (
 	
 	// ServiceDashboard 监控面板服务
 	ServiceDashboard ServiceName = "dashboard"
+	
+	// ServiceSeed 测试数据生成工具
+	ServiceSeed ServiceName = "seed"
 )
 
 // String 返回服务名称字符串
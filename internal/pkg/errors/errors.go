@@ -40,6 +40,7 @@ const (
 	CodeConflict          = "CONFLICT"
 	CodeUnprocessableEntity = "UNPROCESSABLE_ENTITY"
 	CodeRateLimitExceeded = "RATE_LIMIT_EXCEEDED"
+	CodeQuotaExceeded     = "QUOTA_EXCEEDED"
 	CodeInternalError     = "INTERNAL_ERROR"
 	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
 )
@@ -96,10 +97,19 @@ func NewUnauthorizedError(message string) *APIError {
 	return NewAPIError(http.StatusUnauthorized, CodeUnauthorized, message)
 }
 
+func NewForbiddenError(message string) *APIError {
+	return NewAPIError(http.StatusForbidden, CodeForbidden, message)
+}
+
 func NewConflictError(message string) *APIError {
 	return NewAPIError(http.StatusConflict, CodeConflict, message)
 }
 
+// NewQuotaExceededError 创建计划额度超限错误
+func NewQuotaExceededError(message string) *APIError {
+	return NewAPIError(http.StatusPaymentRequired, CodeQuotaExceeded, message)
+}
+
 // generateRequestID 生成请求ID
 func generateRequestID() string {
 	return "req-" + uuid.New().String()
@@ -15,6 +15,7 @@ func TestErrorCodes(t *testing.T) {
 	assert.Equal(t, "CONFLICT", CodeConflict)
 	assert.Equal(t, "UNPROCESSABLE_ENTITY", CodeUnprocessableEntity)
 	assert.Equal(t, "RATE_LIMIT_EXCEEDED", CodeRateLimitExceeded)
+	assert.Equal(t, "QUOTA_EXCEEDED", CodeQuotaExceeded)
 	assert.Equal(t, "INTERNAL_ERROR", CodeInternalError)
 	assert.Equal(t, "SERVICE_UNAVAILABLE", CodeServiceUnavailable)
 }
@@ -112,4 +113,13 @@ func TestRateLimitError(t *testing.T) {
 	assert.Equal(t, "Too many requests", err.ErrorDetail.Message)
 	assert.NotNil(t, err.ErrorDetail.RetryAfter)
 	assert.Equal(t, 60, *err.ErrorDetail.RetryAfter)
-}
\ No newline at end of file
+}
+
+func TestQuotaExceededError(t *testing.T) {
+	err := NewQuotaExceededError("Tracked product limit reached")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, CodeQuotaExceeded, err.ErrorDetail.Code)
+	assert.Equal(t, "Tracked product limit reached", err.ErrorDetail.Message)
+	assert.Contains(t, err.ErrorDetail.RequestID, "req-")
+}
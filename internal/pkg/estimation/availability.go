@@ -0,0 +1,94 @@
+package estimation
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 标准化的库存状态
+const (
+	AvailabilityInStock     = "in_stock"
+	AvailabilityLowStock    = "low_stock"
+	AvailabilityOutOfStock  = "out_of_stock"
+	AvailabilityBackOrder   = "back_order"
+	AvailabilityUnavailable = "unavailable"
+	AvailabilityUnknown     = "unknown"
+)
+
+// maxAvailabilityTextLength availability_history.availability_text 的长度上限 (字符数)
+const maxAvailabilityTextLength = 255
+
+// lowStockPattern 匹配 "Only 3 left in stock - order soon."
+var lowStockPattern = regexp.MustCompile(`(?i)only\s+(\d+)\s+left\s+in\s+stock`)
+
+// backOrderKeywords 可下单但需等待补货/发货的描述
+var backOrderKeywords = []string{
+	"back-order", "backorder", "back order", "pre-order", "preorder",
+	"usually ships within", "available to ship in", "will be released on",
+	"place your order and we'll email you",
+}
+
+// ParseAvailability 将 warehouseAvailability 文本解析为标准化库存状态，低库存时返回剩余数量
+func ParseAvailability(text string) (string, *int) {
+	normalized := strings.ToLower(strings.TrimSpace(text))
+	if normalized == "" {
+		return AvailabilityUnknown, nil
+	}
+
+	if matches := lowStockPattern.FindStringSubmatch(normalized); len(matches) == 2 {
+		if count, err := strconv.Atoi(matches[1]); err == nil {
+			return AvailabilityLowStock, &count
+		}
+	}
+
+	for _, keyword := range backOrderKeywords {
+		if strings.Contains(normalized, keyword) {
+			return AvailabilityBackOrder, nil
+		}
+	}
+
+	switch {
+	case strings.Contains(normalized, "out of stock"):
+		return AvailabilityOutOfStock, nil
+	case strings.Contains(normalized, "unavailable"):
+		return AvailabilityUnavailable, nil
+	case strings.Contains(normalized, "in stock"):
+		return AvailabilityInStock, nil
+	}
+
+	return AvailabilityUnknown, nil
+}
+
+// IsAvailable 该状态下买家是否可以立即购买
+func IsAvailable(state string) bool {
+	return state == AvailabilityInStock || state == AvailabilityLowStock
+}
+
+// AvailabilityTransition 根据前后状态返回需要记录的异常类型 (out_of_stock / back_in_stock)，无需记录时返回空
+// 任一状态未知时不判断，避免抓取失败导致误报
+func AvailabilityTransition(previous, current string) string {
+	if previous == "" || previous == AvailabilityUnknown || current == AvailabilityUnknown {
+		return ""
+	}
+
+	wasAvailable := IsAvailable(previous)
+	nowAvailable := IsAvailable(current)
+	switch {
+	case wasAvailable && !nowAvailable:
+		return "out_of_stock"
+	case !wasAvailable && nowAvailable:
+		return "back_in_stock"
+	}
+	return ""
+}
+
+// TruncateAvailabilityText 将原始库存描述截断到列长度上限，按字符截断避免切断多字节字符
+func TruncateAvailabilityText(text string) string {
+	if utf8.RuneCountInString(text) <= maxAvailabilityTextLength {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:maxAvailabilityTextLength])
+}
@@ -0,0 +1,55 @@
+package estimation
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAvailability(t *testing.T) {
+	cases := []struct {
+		text     string
+		state    string
+		quantity int
+	}{
+		{"In Stock", AvailabilityInStock, 0},
+		{"Only 3 left in stock - order soon.", AvailabilityLowStock, 3},
+		{"Temporarily out of stock.", AvailabilityOutOfStock, 0},
+		{"Temporarily out of stock. We are working hard to be back in stock. Place your order and we'll email you when we have an estimated delivery date.", AvailabilityBackOrder, 0},
+		{"Usually ships within 2 to 3 weeks.", AvailabilityBackOrder, 0},
+		{"Currently unavailable.", AvailabilityUnavailable, 0},
+		{"", AvailabilityUnknown, 0},
+	}
+
+	for _, c := range cases {
+		state, quantity := ParseAvailability(c.text)
+		assert.Equal(t, c.state, state, c.text)
+		if c.quantity > 0 {
+			assert.Equal(t, c.quantity, *quantity)
+		} else {
+			assert.Nil(t, quantity)
+		}
+	}
+
+	assert.Equal(t, "out_of_stock", AvailabilityTransition(AvailabilityLowStock, AvailabilityBackOrder))
+	assert.Equal(t, "back_in_stock", AvailabilityTransition(AvailabilityUnavailable, AvailabilityInStock))
+	assert.Equal(t, "", AvailabilityTransition(AvailabilityInStock, AvailabilityLowStock))
+	assert.Equal(t, "", AvailabilityTransition(AvailabilityInStock, AvailabilityUnknown))
+	assert.Equal(t, "", AvailabilityTransition("", AvailabilityOutOfStock))
+}
+
+func TestTruncateAvailabilityText(t *testing.T) {
+	assert.Equal(t, "In Stock", TruncateAvailabilityText("In Stock"))
+
+	// 多字节字符按字符截断，不会产生非法 UTF-8
+	text := strings.Repeat("现货", 200)
+	truncated := TruncateAvailabilityText(text)
+	assert.True(t, utf8.ValidString(truncated))
+	assert.Equal(t, 255, utf8.RuneCountInString(truncated))
+	assert.True(t, strings.HasPrefix(text, truncated))
+
+	exact := strings.Repeat("é", 255)
+	assert.Equal(t, exact, TruncateAvailabilityText(exact))
+}
@@ -0,0 +1,104 @@
+package estimation
+
+import (
+	"time"
+
+	"amazonpilot/internal/pkg/fx"
+	"amazonpilot/internal/pkg/llm"
+	"amazonpilot/internal/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+// SalesEstimate 一段时间内的销量和收入估算，收入使用产品价格的原始币种
+type SalesEstimate struct {
+	Summary
+	Currency          string
+	LatestUnitsPerDay float64
+	RecordedAt        time.Time // 最新估算点的时间，用于选择汇率
+}
+
+// BuildSalesPoints 将排名历史转换为带价格的估算点（两者均需按时间升序）
+// 已保存估算值的记录直接使用，历史数据按当前模型补算；价格取不晚于排名时间的最近价格
+func BuildSalesPoints(rankings []models.RankingHistory, prices []models.PriceHistory, model *Model) []Point {
+	points := make([]Point, 0, len(rankings))
+	priceIdx := -1
+	for _, ranking := range rankings {
+		if ranking.BSRRank == nil || *ranking.BSRRank <= 0 {
+			continue
+		}
+
+		for priceIdx+1 < len(prices) && !prices[priceIdx+1].RecordedAt.After(ranking.RecordedAt) {
+			priceIdx++
+		}
+		price := 0.0
+		if priceIdx >= 0 {
+			price = prices[priceIdx].Price
+		} else if len(prices) > 0 {
+			price = prices[0].Price
+		}
+
+		units := 0.0
+		if ranking.EstUnitsPerDay != nil {
+			units = *ranking.EstUnitsPerDay
+		} else if model != nil {
+			units = model.UnitsPerDay(ranking.Category, *ranking.BSRRank)
+		}
+
+		points = append(points, Point{
+			At:          ranking.RecordedAt,
+			UnitsPerDay: units,
+			Price:       price,
+		})
+	}
+	return points
+}
+
+// EstimateSales 汇总产品在 [since, until] 内的销量估算，没有BSR数据时返回 nil
+func EstimateSales(db *gorm.DB, model *Model, productID string, since, until time.Time) (*SalesEstimate, error) {
+	var rankings []models.RankingHistory
+	if err := db.Where("product_id = ? AND recorded_at >= ? AND recorded_at <= ? AND bsr_rank IS NOT NULL", productID, since, until).
+		Order("recorded_at ASC").
+		Find(&rankings).Error; err != nil {
+		return nil, err
+	}
+	if len(rankings) == 0 {
+		return nil, nil
+	}
+
+	var prices []models.PriceHistory
+	if err := db.Select("price, currency, recorded_at").
+		Where("product_id = ? AND recorded_at >= ? AND recorded_at <= ?", productID, since, until).
+		Order("recorded_at ASC").
+		Find(&prices).Error; err != nil {
+		return nil, err
+	}
+
+	points := BuildSalesPoints(rankings, prices, model)
+	if len(points) == 0 {
+		return nil, nil
+	}
+
+	estimate := &SalesEstimate{
+		Summary:           Summarize(points, until),
+		LatestUnitsPerDay: points[len(points)-1].UnitsPerDay,
+		RecordedAt:        points[len(points)-1].At,
+	}
+	if len(prices) > 0 {
+		estimate.Currency = prices[len(prices)-1].Currency
+	}
+	return estimate, nil
+}
+
+// FillReportSalesEstimate 为报告数据填充近30天的预估日销量和月收入（月收入换算为 data.Currency）
+func FillReportSalesEstimate(db *gorm.DB, model *Model, converter *fx.Converter, data *llm.ProductData, productID string) error {
+	now := time.Now()
+	estimate, err := EstimateSales(db, model, productID, now.AddDate(0, 0, -30), now)
+	if err != nil || estimate == nil {
+		return err
+	}
+
+	data.EstUnitsPerDay = estimate.AvgUnitsPerDay
+	data.EstMonthlyRevenue = converter.ConvertOrKeep(estimate.MonthlyRevenue, estimate.Currency, data.Currency, estimate.RecordedAt).Amount
+	return nil
+}
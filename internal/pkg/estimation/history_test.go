@@ -0,0 +1,35 @@
+package estimation
+
+import (
+	"testing"
+	"time"
+
+	"amazonpilot/internal/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSalesPoints(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	rank := func(v int) *int { return &v }
+	stored := 12.5
+	rankings := []models.RankingHistory{
+		{Category: "Electronics", BSRRank: rank(1000), RecordedAt: start},
+		{Category: "Electronics", BSRRank: rank(900), EstUnitsPerDay: &stored, RecordedAt: start.Add(24 * time.Hour)},
+		{Category: "Electronics", RecordedAt: start.Add(48 * time.Hour)},
+	}
+	prices := []models.PriceHistory{
+		{Price: 19.99, RecordedAt: start},
+		{Price: 24.99, RecordedAt: start.Add(12 * time.Hour)},
+	}
+
+	model := DefaultModel()
+	points := BuildSalesPoints(rankings, prices, model)
+	assert.Len(t, points, 2)
+
+	// 历史数据按模型补算，已保存的估算值直接使用
+	assert.Equal(t, model.UnitsPerDay("Electronics", 1000), points[0].UnitsPerDay)
+	assert.Equal(t, 19.99, points[0].Price)
+	assert.Equal(t, 12.5, points[1].UnitsPerDay)
+	assert.Equal(t, 24.99, points[1].Price)
+}
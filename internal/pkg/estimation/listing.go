@@ -0,0 +1,184 @@
+package estimation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"amazonpilot/internal/pkg/apify"
+	"amazonpilot/internal/pkg/models"
+)
+
+// Listing 内容字段
+const (
+	ListingFieldTitle        = "title"
+	ListingFieldDescription  = "description"
+	ListingFieldBulletPoints = "bullet_points"
+	ListingFieldImages       = "images"
+)
+
+// ListingContent 用于变更检测的Listing内容
+type ListingContent struct {
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	BulletPoints []string `json:"bullet_points"`
+	Images       []string `json:"images"`
+}
+
+// ListingFieldDiff 单个字段的变更
+// 文本字段填充 Old/New，列表字段额外给出新增和删除的条目
+type ListingFieldDiff struct {
+	Field   string      `json:"field"`
+	Old     interface{} `json:"old"`
+	New     interface{} `json:"new"`
+	Added   []string    `json:"added,omitempty"`
+	Removed []string    `json:"removed,omitempty"`
+}
+
+// ListingContentFromApify 从 Apify 数据提取Listing内容
+func ListingContentFromApify(data *apify.ProductData) ListingContent {
+	return ListingContent{
+		Title:        strings.TrimSpace(data.Title),
+		Description:  strings.TrimSpace(data.Description),
+		BulletPoints: data.BulletPoints,
+		Images:       data.Images,
+	}
+}
+
+// ListingContentFromSnapshot 从快照还原Listing内容
+func ListingContentFromSnapshot(snapshot *models.ListingSnapshot) ListingContent {
+	content := ListingContent{}
+	if snapshot.Title != nil {
+		content.Title = *snapshot.Title
+	}
+	if snapshot.Description != nil {
+		content.Description = *snapshot.Description
+	}
+	if snapshot.BulletPoints != nil {
+		json.Unmarshal(snapshot.BulletPoints, &content.BulletPoints)
+	}
+	if snapshot.Images != nil {
+		json.Unmarshal(snapshot.Images, &content.Images)
+	}
+	return content
+}
+
+// ListingContentFromProduct 从当前产品记录提取Listing内容（用于尚无快照时的基线）
+func ListingContentFromProduct(product *models.Product) ListingContent {
+	return ListingContentFromSnapshot(&models.ListingSnapshot{
+		Title:        product.Title,
+		Description:  product.Description,
+		BulletPoints: product.BulletPoints,
+		Images:       product.Images,
+	})
+}
+
+// IsEmpty 是否没有任何内容（例如刚添加追踪、尚未抓取的产品）
+func (c ListingContent) IsEmpty() bool {
+	return c.Title == "" && c.Description == "" && len(c.BulletPoints) == 0 && len(c.Images) == 0
+}
+
+// FillMissing 用旧版本补齐本次抓取缺失的字段，避免快照因抓取不完整而丢失内容
+func (c ListingContent) FillMissing(from ListingContent) ListingContent {
+	if c.Title == "" {
+		c.Title = from.Title
+	}
+	if c.Description == "" {
+		c.Description = from.Description
+	}
+	if len(c.BulletPoints) == 0 {
+		c.BulletPoints = from.BulletPoints
+	}
+	if len(c.Images) == 0 {
+		c.Images = from.Images
+	}
+	return c
+}
+
+// Hash 内容摘要，用于快速判断是否变化
+func (c ListingContent) Hash() string {
+	raw, _ := json.Marshal(c)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// ToSnapshot 构建快照记录
+func (c ListingContent) ToSnapshot(productID string) models.ListingSnapshot {
+	snapshot := models.ListingSnapshot{
+		ProductID:   productID,
+		ContentHash: c.Hash(),
+	}
+	if c.Title != "" {
+		snapshot.Title = &c.Title
+	}
+	if c.Description != "" {
+		snapshot.Description = &c.Description
+	}
+	if len(c.BulletPoints) > 0 {
+		snapshot.BulletPoints, _ = json.Marshal(c.BulletPoints)
+	}
+	if len(c.Images) > 0 {
+		snapshot.Images, _ = json.Marshal(c.Images)
+	}
+	return snapshot
+}
+
+// DiffListingContent 比较两个版本的Listing内容，返回字段级差异
+// 新数据缺失某字段（抓取不完整）时不视为删除，避免误报
+func DiffListingContent(old, new ListingContent) []ListingFieldDiff {
+	diffs := []ListingFieldDiff{}
+
+	if new.Title != "" && old.Title != new.Title {
+		diffs = append(diffs, ListingFieldDiff{Field: ListingFieldTitle, Old: old.Title, New: new.Title})
+	}
+	if new.Description != "" && old.Description != new.Description {
+		diffs = append(diffs, ListingFieldDiff{Field: ListingFieldDescription, Old: old.Description, New: new.Description})
+	}
+	if len(new.BulletPoints) > 0 && !equalStrings(old.BulletPoints, new.BulletPoints) {
+		diffs = append(diffs, diffStringList(ListingFieldBulletPoints, old.BulletPoints, new.BulletPoints))
+	}
+	if len(new.Images) > 0 && !equalStrings(old.Images, new.Images) {
+		diffs = append(diffs, diffStringList(ListingFieldImages, old.Images, new.Images))
+	}
+
+	return diffs
+}
+
+// diffStringList 计算列表字段的新增/删除条目（顺序调整时两者均为空）
+func diffStringList(field string, old, new []string) ListingFieldDiff {
+	oldSet := make(map[string]bool, len(old))
+	for _, v := range old {
+		oldSet[v] = true
+	}
+	newSet := make(map[string]bool, len(new))
+	for _, v := range new {
+		newSet[v] = true
+	}
+
+	diff := ListingFieldDiff{Field: field, Old: old, New: new}
+	for _, v := range new {
+		if !oldSet[v] {
+			diff.Added = append(diff.Added, v)
+		}
+	}
+	for _, v := range old {
+		if !newSet[v] {
+			diff.Removed = append(diff.Removed, v)
+		}
+	}
+	return diff
+}
+
+// equalStrings 判断两个字符串列表是否完全一致（包括顺序）
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
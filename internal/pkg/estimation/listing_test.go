@@ -0,0 +1,42 @@
+package estimation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffListingContent(t *testing.T) {
+	old := ListingContent{
+		Title:        "Echo Dot (4th Gen)",
+		Description:  "Smart speaker with Alexa",
+		BulletPoints: []string{"Voice control your music", "Ready to help"},
+		Images:       []string{"https://example.com/1.jpg"},
+	}
+
+	// 内容未变化
+	assert.Empty(t, DiffListingContent(old, old))
+
+	// 新抓取缺失描述和图片时不视为删除
+	partial := ListingContent{Title: old.Title, BulletPoints: old.BulletPoints}
+	assert.Empty(t, DiffListingContent(old, partial))
+	assert.Equal(t, old.Hash(), partial.FillMissing(old).Hash())
+
+	updated := old
+	updated.Title = "Echo Dot (5th Gen)"
+	updated.BulletPoints = []string{"Voice control your music", "Improved audio"}
+
+	diffs := DiffListingContent(old, updated)
+	assert.Len(t, diffs, 2)
+	assert.Equal(t, ListingFieldTitle, diffs[0].Field)
+	assert.Equal(t, "Echo Dot (4th Gen)", diffs[0].Old)
+	assert.Equal(t, "Echo Dot (5th Gen)", diffs[0].New)
+	assert.Equal(t, ListingFieldBulletPoints, diffs[1].Field)
+	assert.Equal(t, []string{"Improved audio"}, diffs[1].Added)
+	assert.Equal(t, []string{"Ready to help"}, diffs[1].Removed)
+
+	// 快照往返后内容一致
+	snapshot := updated.ToSnapshot("product-1")
+	assert.Equal(t, updated.Hash(), snapshot.ContentHash)
+	assert.Equal(t, updated, ListingContentFromSnapshot(&snapshot))
+}
@@ -0,0 +1,192 @@
+package estimation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// Curve BSR -> 日销量的幂律曲线: units/day = A * rank^(-B)
+type Curve struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+}
+
+// Valid 曲线参数是否可用
+func (c Curve) Valid() bool {
+	return c.A > 0 && c.B > 0
+}
+
+// UnitsPerDay 按曲线估算日销量
+func (c Curve) UnitsPerDay(rank int) float64 {
+	if rank <= 0 || !c.Valid() {
+		return 0
+	}
+	return round2(c.A * math.Pow(float64(rank), -c.B))
+}
+
+// CurveFile 销量曲线文件格式（类目名不区分大小写）
+//
+//	{"default": {"a": 3000, "b": 0.7}, "categories": {"Home & Kitchen": {"a": 5500, "b": 0.72}}}
+type CurveFile struct {
+	Default    Curve            `json:"default"`
+	Categories map[string]Curve `json:"categories"`
+}
+
+// DefaultCurve 未知类目使用的通用曲线（BSR 1 约 3000 件/天，BSR 100000 约 1 件/天）
+var DefaultCurve = Curve{A: 3000, B: 0.7}
+
+// DefaultCategoryCurves 内置的主要类目曲线，可被曲线文件覆盖
+var DefaultCategoryCurves = map[string]Curve{
+	"Home & Kitchen":           {A: 5500, B: 0.72},
+	"Kitchen & Dining":         {A: 3200, B: 0.72},
+	"Electronics":              {A: 2800, B: 0.74},
+	"Toys & Games":             {A: 3500, B: 0.73},
+	"Beauty & Personal Care":   {A: 4500, B: 0.72},
+	"Health & Household":       {A: 4500, B: 0.72},
+	"Sports & Outdoors":        {A: 2500, B: 0.72},
+	"Pet Supplies":             {A: 2600, B: 0.71},
+	"Tools & Home Improvement": {A: 2400, B: 0.72},
+	"Books":                    {A: 2000, B: 0.76},
+}
+
+// Model 按类目的销量估算模型
+type Model struct {
+	defaultCurve Curve
+	categories   map[string]Curve // 小写类目名 -> 曲线
+}
+
+// NewModel 创建估算模型，file 中的曲线覆盖内置曲线
+func NewModel(file CurveFile) *Model {
+	m := &Model{
+		defaultCurve: DefaultCurve,
+		categories:   make(map[string]Curve, len(DefaultCategoryCurves)+len(file.Categories)),
+	}
+	for category, curve := range DefaultCategoryCurves {
+		m.categories[normalizeCategory(category)] = curve
+	}
+	if file.Default.Valid() {
+		m.defaultCurve = file.Default
+	}
+	for category, curve := range file.Categories {
+		if curve.Valid() {
+			m.categories[normalizeCategory(category)] = curve
+		}
+	}
+	return m
+}
+
+// DefaultModel 只使用内置曲线的模型
+func DefaultModel() *Model {
+	return NewModel(CurveFile{})
+}
+
+// LoadFile 从JSON文件加载销量曲线
+func LoadFile(path string) (*Model, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sales curve file: %w", err)
+	}
+
+	var file CurveFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode sales curve file: %w", err)
+	}
+
+	for category, curve := range file.Categories {
+		if !curve.Valid() {
+			return nil, fmt.Errorf("invalid sales curve for category %q: a and b must be positive", category)
+		}
+	}
+
+	return NewModel(file), nil
+}
+
+// LoadModel 根据曲线文件创建模型，path 为空时使用内置曲线
+func LoadModel(path string) (*Model, error) {
+	if path == "" {
+		return DefaultModel(), nil
+	}
+	return LoadFile(path)
+}
+
+// Curve 获取类目对应的曲线，未配置的类目使用默认曲线
+func (m *Model) Curve(category string) Curve {
+	if curve, ok := m.categories[normalizeCategory(category)]; ok {
+		return curve
+	}
+	return m.defaultCurve
+}
+
+// UnitsPerDay 估算指定类目和BSR下的日销量
+func (m *Model) UnitsPerDay(category string, rank int) float64 {
+	return m.Curve(category).UnitsPerDay(rank)
+}
+
+// Point 一个带销量估算的历史点
+type Point struct {
+	At          time.Time
+	UnitsPerDay float64
+	Price       float64
+}
+
+// Summary 一段时间内的销量估算汇总
+type Summary struct {
+	Days           float64 `json:"days"`
+	TotalUnits     float64 `json:"total_units"`
+	AvgUnitsPerDay float64 `json:"avg_units_per_day"`
+	Revenue        float64 `json:"revenue"`
+	MonthlyRevenue float64 `json:"monthly_revenue"` // 按30天折算
+}
+
+// Summarize 按时间加权汇总销量估算：每个点的估算值持续到下一个点（最后一个点持续到 end）
+// points 需按时间升序排列
+func Summarize(points []Point, end time.Time) Summary {
+	if len(points) == 0 {
+		return Summary{}
+	}
+
+	var summary Summary
+	for i, point := range points {
+		until := end
+		if i+1 < len(points) {
+			until = points[i+1].At
+		}
+		days := until.Sub(point.At).Hours() / 24
+		if days <= 0 {
+			continue
+		}
+		summary.Days += days
+		summary.TotalUnits += point.UnitsPerDay * days
+		summary.Revenue += point.UnitsPerDay * point.Price * days
+	}
+
+	if summary.Days <= 0 {
+		// 只有同一时刻的数据点时，直接使用最新的估算值
+		last := points[len(points)-1]
+		return Summary{
+			AvgUnitsPerDay: round2(last.UnitsPerDay),
+			MonthlyRevenue: round2(last.UnitsPerDay * last.Price * 30),
+		}
+	}
+
+	summary.AvgUnitsPerDay = round2(summary.TotalUnits / summary.Days)
+	summary.MonthlyRevenue = round2(summary.Revenue / summary.Days * 30)
+	summary.Days = round2(summary.Days)
+	summary.TotalUnits = round2(summary.TotalUnits)
+	summary.Revenue = round2(summary.Revenue)
+	return summary
+}
+
+// normalizeCategory 类目名标准化（去空白，小写）
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+// round2 保留两位小数
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
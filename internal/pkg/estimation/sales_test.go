@@ -0,0 +1,60 @@
+package estimation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_UnitsPerDay(t *testing.T) {
+	model := DefaultModel()
+
+	// 类目名不区分大小写，未知类目使用默认曲线
+	assert.Equal(t, model.UnitsPerDay("Home & Kitchen", 1000), model.UnitsPerDay(" home & kitchen ", 1000))
+	assert.Equal(t, DefaultCurve, model.Curve("Unknown Category"))
+	assert.InDelta(t, 1.0, model.UnitsPerDay("Unknown Category", 100000), 0.1)
+
+	// BSR 越低销量越高
+	assert.Greater(t, model.UnitsPerDay("Electronics", 100), model.UnitsPerDay("Electronics", 1000))
+	assert.Equal(t, 0.0, model.UnitsPerDay("Electronics", 0))
+}
+
+func TestLoadFile_OverridesCurves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "curves.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"default": {"a": 1000, "b": 0.5}, "categories": {"Electronics": {"a": 10000, "b": 1}}}`), 0644))
+
+	model, err := LoadModel(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, model.UnitsPerDay("electronics", 100))
+	assert.Equal(t, 100.0, model.UnitsPerDay("Garden", 100))
+	assert.Equal(t, DefaultCategoryCurves["Books"], model.Curve("Books"))
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"categories": {"Electronics": {"a": 0, "b": 1}}}`), 0644))
+	_, err = LoadFile(path)
+	assert.Error(t, err)
+}
+
+func TestSummarize_TimeWeighted(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	points := []Point{
+		{At: start, UnitsPerDay: 10, Price: 20},
+		{At: start.Add(48 * time.Hour), UnitsPerDay: 40, Price: 10},
+	}
+
+	summary := Summarize(points, start.Add(72*time.Hour))
+	assert.Equal(t, 3.0, summary.Days)
+	assert.Equal(t, 60.0, summary.TotalUnits)
+	assert.Equal(t, 20.0, summary.AvgUnitsPerDay)
+	assert.Equal(t, 800.0, summary.Revenue)
+	assert.Equal(t, 8000.0, summary.MonthlyRevenue)
+
+	// 单个点时直接使用当前估算
+	summary = Summarize(points[1:2], points[1].At)
+	assert.Equal(t, 40.0, summary.AvgUnitsPerDay)
+	assert.Equal(t, 12000.0, summary.MonthlyRevenue)
+
+	assert.Equal(t, Summary{}, Summarize(nil, start))
+}
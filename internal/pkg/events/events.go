@@ -0,0 +1,149 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// 领域事件类型
+const (
+	TypeRefreshStarted   = "refresh.started"
+	TypeRefreshCompleted = "refresh.completed"
+	TypeRefreshFailed    = "refresh.failed"
+	TypeAnomalyDetected  = "anomaly.detected"
+	TypeReportCompleted  = "report.completed"
+)
+
+// UserChannelPrefix 用户事件频道前缀，每个用户一个频道
+const UserChannelPrefix = "amazon_pilot:events:user:"
+
+// Event 推送给用户的领域事件
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	UserID     string          `json:"user_id"`
+	ProductID  string          `json:"product_id,omitempty"`
+	Data       json.RawMessage `json:"data,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// New 创建事件，data 序列化为 JSON
+func New(eventType, userID, productID string, data interface{}) (Event, error) {
+	event := Event{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		UserID:     userID,
+		ProductID:  productID,
+		OccurredAt: time.Now(),
+	}
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return event, err
+		}
+		event.Data = raw
+	}
+	return event, nil
+}
+
+// UserChannel 返回用户的事件频道
+func UserChannel(userID string) string {
+	return UserChannelPrefix + userID
+}
+
+// Bus 基于 Redis pub/sub 的事件总线
+// 事件不持久化，订阅者断线期间的事件会丢失，客户端重连后应重新拉取状态
+type Bus struct {
+	client *redis.Client
+}
+
+// NewBus 创建事件总线
+func NewBus(client *redis.Client) *Bus {
+	return &Bus{client: client}
+}
+
+// Publish 将事件发布到各自用户的频道
+func (b *Bus) Publish(ctx context.Context, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	pipe := b.client.Pipeline()
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		pipe.Publish(ctx, UserChannel(event.UserID), data)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Subscribe 订阅用户的事件频道，调用方结束后需要 Close
+func (b *Bus) Subscribe(ctx context.Context, userID string) (*Subscription, error) {
+	pubsub := b.client.Subscribe(ctx, UserChannel(userID))
+	// 等待订阅确认，Redis 不可用时直接返回错误
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	sub := &Subscription{pubsub: pubsub, events: make(chan Event), done: make(chan struct{})}
+	go sub.run()
+	return sub, nil
+}
+
+// Subscription 用户事件订阅
+type Subscription struct {
+	pubsub *redis.PubSub
+	events chan Event
+	done   chan struct{}
+}
+
+// Events 返回事件通道，订阅关闭后通道关闭
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close 取消订阅，只能调用一次
+func (s *Subscription) Close() error {
+	close(s.done)
+	return s.pubsub.Close()
+}
+
+func (s *Subscription) run() {
+	defer close(s.events)
+	for msg := range s.pubsub.Channel() {
+		var event Event
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			continue
+		}
+		select {
+		case s.events <- event:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// WriteSSE 按 Server-Sent Events 格式写出事件
+func WriteSSE(w io.Writer, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// WriteHeartbeat 写出 SSE 注释行，保持连接不被中间代理断开
+func WriteHeartbeat(w io.Writer) error {
+	_, err := io.WriteString(w, ": ping\n\n")
+	return err
+}
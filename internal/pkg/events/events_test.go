@@ -0,0 +1,53 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserChannel(t *testing.T) {
+	assert.Equal(t, "amazon_pilot:events:user:u1", UserChannel("u1"))
+}
+
+func TestNewEvent(t *testing.T) {
+	event, err := New(TypeRefreshCompleted, "u1", "p1", map[string]interface{}{"price": 19.99})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, event.ID)
+	assert.Equal(t, "refresh.completed", event.Type)
+	assert.Equal(t, "u1", event.UserID)
+	assert.Equal(t, "p1", event.ProductID)
+	assert.JSONEq(t, `{"price":19.99}`, string(event.Data))
+	assert.False(t, event.OccurredAt.IsZero())
+
+	// 没有附加数据时不输出 data 字段
+	event, err = New(TypeRefreshStarted, "u1", "p1", nil)
+	assert.NoError(t, err)
+	raw, _ := json.Marshal(event)
+	assert.NotContains(t, string(raw), `"data"`)
+}
+
+func TestWriteSSE(t *testing.T) {
+	event, _ := New(TypeAnomalyDetected, "u1", "p1", map[string]string{"event_type": "price_change"})
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteSSE(&buf, event))
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "id: "+event.ID+"\nevent: anomaly.detected\ndata: {"))
+	assert.True(t, strings.HasSuffix(out, "}\n\n"))
+	// data 必须是单行 JSON，否则客户端会拆成多行
+	assert.Equal(t, 3, strings.Count(strings.TrimSuffix(out, "\n\n"), "\n")+1)
+
+	var decoded Event
+	data := strings.TrimSuffix(strings.SplitN(out, "data: ", 2)[1], "\n\n")
+	assert.NoError(t, json.Unmarshal([]byte(data), &decoded))
+	assert.Equal(t, event.ID, decoded.ID)
+
+	buf.Reset()
+	assert.NoError(t, WriteHeartbeat(&buf))
+	assert.Equal(t, ": ping\n\n", buf.String())
+}
@@ -0,0 +1,166 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"amazonpilot/internal/pkg/fx"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readZipFile(t *testing.T, data []byte, name string) string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	for _, f := range zr.File {
+		if f.Name == name {
+			rc, err := f.Open()
+			assert.NoError(t, err)
+			defer rc.Close()
+			content, err := io.ReadAll(rc)
+			assert.NoError(t, err)
+			return string(content)
+		}
+	}
+	t.Fatalf("zip entry %s not found", name)
+	return ""
+}
+
+func testRecords() []Record {
+	price := 19.99
+	rank := 1234.0
+	at := time.Date(2025, 1, 2, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	return []Record{
+		{TrackedID: "t1", ASIN: "B08N5WRWNW", Metric: MetricPrice, RecordedAt: at, Value: &price, Currency: "USD", Tags: []string{"Kitchen", "Q4"}},
+		{TrackedID: "t1", ASIN: "B08N5WRWNW", Metric: MetricBSR, RecordedAt: at, Value: &rank, Category: "Home & Kitchen"},
+	}
+}
+
+func writeAll(t *testing.T, w Writer, records []Record) {
+	metric := ""
+	for _, r := range records {
+		if r.Metric != metric {
+			metric = r.Metric
+			assert.NoError(t, w.BeginMetric(metric))
+		}
+		assert.NoError(t, w.Write(r))
+	}
+	assert.NoError(t, w.Close())
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf)
+	assert.NoError(t, err)
+	writeAll(t, w, testRecords())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "tracked_id,asin,metric,recorded_at,value,currency,category,seller,tags", lines[0])
+	assert.Equal(t, "t1,B08N5WRWNW,price,2025-01-02T00:00:00Z,19.99,USD,,,Kitchen;Q4", lines[1])
+	assert.Equal(t, "t1,B08N5WRWNW,bsr,2025-01-02T00:00:00Z,1234,,Home & Kitchen,,", lines[2])
+
+	// 没有数据时只输出表头
+	buf.Reset()
+	w, _ = NewWriter(FormatCSV, &buf)
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "tracked_id,asin,metric,recorded_at,value,currency,category,seller,tags\n", buf.String())
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatNDJSON, &buf)
+	assert.NoError(t, err)
+	writeAll(t, w, testRecords())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "price", record["metric"])
+	assert.Equal(t, 19.99, record["value"])
+	assert.Equal(t, "2025-01-02T00:00:00Z", record["recorded_at"])
+	assert.NotContains(t, lines[0], "category")
+	assert.Equal(t, []interface{}{"Kitchen", "Q4"}, record["tags"])
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatXLSX, &buf)
+	assert.NoError(t, err)
+	writeAll(t, w, testRecords())
+
+	workbook := readZipFile(t, buf.Bytes(), "xl/workbook.xml")
+	assert.Contains(t, workbook, `<sheet name="price" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, workbook, `<sheet name="bsr" sheetId="2" r:id="rId2"/>`)
+
+	sheet := readZipFile(t, buf.Bytes(), "xl/worksheets/sheet2.xml")
+	assert.Contains(t, sheet, `<t xml:space="preserve">Home &amp; Kitchen</t>`)
+	assert.Contains(t, sheet, `<c><v>1234</v></c>`)
+	assert.NotContains(t, sheet, "metric")
+
+	contentTypes := readZipFile(t, buf.Bytes(), "[Content_Types].xml")
+	assert.Contains(t, contentTypes, "/xl/worksheets/sheet2.xml")
+}
+
+func TestXLSXSheetName(t *testing.T) {
+	x := NewXLSXWriter(io.Discard)
+	assert.NoError(t, x.AddSheet("a/b:c"))
+	assert.NoError(t, x.AddSheet("A_B_C"))
+	assert.NoError(t, x.AddSheet(strings.Repeat("x", 40)))
+	assert.NoError(t, x.Close())
+
+	assert.Equal(t, []string{"a_b_c", "A_B_C (2)", strings.Repeat("x", 31)}, x.sheets)
+}
+
+func TestParseMetrics(t *testing.T) {
+	metrics, err := ParseMetrics("")
+	assert.NoError(t, err)
+	assert.Equal(t, Metrics, metrics)
+
+	metrics, err = ParseMetrics("buybox, PRICE,price")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{MetricPrice, MetricBuyBox}, metrics)
+
+	_, err = ParseMetrics("price,views")
+	assert.Error(t, err)
+
+	format, err := ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+	_, err = ParseFormat("pdf")
+	assert.Error(t, err)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "history_20250101_20250131.xlsx", FileName(FormatXLSX, from, from.AddDate(0, 0, 31)))
+}
+
+func TestOptionsConvert(t *testing.T) {
+	store := fx.NewStore()
+	store.SetDailyRates(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), map[string]float64{"EUR": 0.9})
+	store.SetDailyRates(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), map[string]float64{"EUR": 0.8})
+	opts := Options{Currency: "EUR", Converter: fx.NewConverter(store)}
+
+	// 价格按记录当天的汇率换算，非价格指标不变
+	records := testRecords()
+	for i := range records {
+		opts.convert(&records[i])
+	}
+	assert.Equal(t, 15.99, *records[0].Value)
+	assert.Equal(t, "EUR", records[0].Currency)
+	assert.Equal(t, 1234.0, *records[1].Value)
+	assert.Equal(t, "", records[1].Currency)
+
+	// 缺少汇率时保留原始币种
+	price := 100.0
+	record := Record{Metric: MetricBuyBox, RecordedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Value: &price, Currency: "GBP"}
+	opts.convert(&record)
+	assert.Equal(t, 100.0, *record.Value)
+	assert.Equal(t, "GBP", record.Currency)
+}
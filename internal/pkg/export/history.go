@@ -0,0 +1,390 @@
+package export
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"amazonpilot/internal/pkg/fx"
+	"amazonpilot/internal/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+// 可导出的历史指标
+const (
+	MetricPrice       = "price"
+	MetricBSR         = "bsr"
+	MetricRating      = "rating"
+	MetricReviewCount = "review_count"
+	MetricBuyBox      = "buybox"
+)
+
+// Metrics 全部可导出指标（默认导出顺序）
+var Metrics = []string{MetricPrice, MetricBSR, MetricRating, MetricReviewCount, MetricBuyBox}
+
+// 导出文件格式
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// Columns 导出文件的列（XLSX 每个指标一个工作表，不含 metric 列）
+var Columns = []string{"tracked_id", "asin", "metric", "recorded_at", "value", "currency", "category", "seller", "tags"}
+
+// Product 待导出的追踪产品
+type Product struct {
+	TrackedID string
+	ProductID string
+	ASIN      string
+	Tags      []string `gorm:"-"` // 标签名称
+}
+
+// Record 一条导出的历史记录
+type Record struct {
+	TrackedID  string    `json:"tracked_id"`
+	ASIN       string    `json:"asin"`
+	Metric     string    `json:"metric"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Value      *float64  `json:"value"`
+	Currency   string    `json:"currency,omitempty"` // price, buybox
+	Category   string    `json:"category,omitempty"` // bsr
+	Seller     string    `json:"seller,omitempty"`   // buybox 获得者
+	Tags       []string  `json:"tags,omitempty"`     // 追踪产品的标签名称
+}
+
+// Options 导出参数，时间范围为 [From, To)
+type Options struct {
+	Products []Product
+	Metrics  []string
+	From     time.Time
+	To       time.Time
+	// Currency 价格类指标 (price, buybox) 按记录时间的汇率换算的货币，为空时保留原始币种
+	Currency  string
+	Converter *fx.Converter
+}
+
+// convert 将价格类记录换算为 Currency，缺少汇率时保留原始币种
+func (o Options) convert(record *Record) {
+	if o.Currency == "" || o.Converter == nil || record.Value == nil {
+		return
+	}
+	if record.Metric != MetricPrice && record.Metric != MetricBuyBox {
+		return
+	}
+	money := o.Converter.ConvertOrKeep(*record.Value, record.Currency, o.Currency, record.RecordedAt)
+	record.Value = &money.Amount
+	record.Currency = money.Currency
+}
+
+// Writer 按指标顺序逐条写出记录
+type Writer interface {
+	// BeginMetric 开始写出一个指标的记录
+	BeginMetric(metric string) error
+	Write(record Record) error
+	// Close 刷新缓冲并结束文件（不关闭底层 io.Writer）
+	Close() error
+}
+
+// NewWriter 按格式创建写出器
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	case FormatXLSX:
+		return &xlsxWriter{w: NewXLSXWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// ParseFormat 校验导出格式，为空时使用 csv
+func ParseFormat(format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case "":
+		return FormatCSV, nil
+	case FormatCSV, FormatNDJSON, FormatXLSX:
+		return format, nil
+	default:
+		return "", fmt.Errorf("format must be one of csv, ndjson, xlsx")
+	}
+}
+
+// ParseMetrics 解析逗号分隔的指标列表，为空时返回全部指标，结果按默认顺序去重
+func ParseMetrics(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return append([]string(nil), Metrics...), nil
+	}
+
+	requested := map[string]bool{}
+	for _, metric := range strings.Split(value, ",") {
+		metric = strings.ToLower(strings.TrimSpace(metric))
+		if metric == "" {
+			continue
+		}
+		if !isMetric(metric) {
+			return nil, fmt.Errorf("unsupported metric: %s", metric)
+		}
+		requested[metric] = true
+	}
+
+	metrics := []string{}
+	for _, metric := range Metrics {
+		if requested[metric] {
+			metrics = append(metrics, metric)
+		}
+	}
+	return metrics, nil
+}
+
+// ContentType 导出格式对应的 Content-Type
+func ContentType(format string) string {
+	switch format {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// FileName 导出文件名，如 history_20250101_20250131.csv
+func FileName(format string, from, to time.Time) string {
+	return fmt.Sprintf("history_%s_%s.%s", from.Format("20060102"), to.AddDate(0, 0, -1).Format("20060102"), format)
+}
+
+// LoadProducts 查询工作区的追踪产品及其标签，trackedIDs 为空时返回全部追踪中的产品
+// tagIDs 不为空时只返回带有其中任一标签的产品；不属于该工作区的 ID 会被忽略，调用方可比较数量判断
+func LoadProducts(db *gorm.DB, workspaceID string, trackedIDs, tagIDs []string) ([]Product, error) {
+	query := db.Table("tracked_products").
+		Select("tracked_products.id AS tracked_id, tracked_products.product_id, products.asin").
+		Joins("JOIN products ON products.id = tracked_products.product_id").
+		Where("tracked_products.workspace_id = ?", workspaceID)
+	if len(trackedIDs) > 0 {
+		query = query.Where("tracked_products.id IN ?", trackedIDs)
+	} else {
+		query = query.Where("tracked_products.is_active = ?", true)
+	}
+	if len(tagIDs) > 0 {
+		query = query.Where("tracked_products.id IN (SELECT tracked_product_id FROM tracked_product_tags WHERE tag_id IN ?)", tagIDs)
+	}
+
+	var products []Product
+	if err := query.Order("products.asin ASC").Scan(&products).Error; err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return products, nil
+	}
+
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.TrackedID
+	}
+	var links []struct {
+		TrackedProductID string
+		Name             string
+	}
+	if err := db.Table("tracked_product_tags").
+		Select("tracked_product_tags.tracked_product_id, tags.name").
+		Joins("JOIN tags ON tags.id = tracked_product_tags.tag_id").
+		Where("tracked_product_tags.tracked_product_id IN ?", ids).
+		Order("tags.name ASC").
+		Scan(&links).Error; err != nil {
+		return nil, err
+	}
+	tags := map[string][]string{}
+	for _, link := range links {
+		tags[link.TrackedProductID] = append(tags[link.TrackedProductID], link.Name)
+	}
+	for i := range products {
+		products[i].Tags = tags[products[i].TrackedID]
+	}
+	return products, nil
+}
+
+// Export 按指标、产品逐个流式查询并写出，返回写出的记录数
+// 每次只持有一行数据库结果，适合大范围导出
+func Export(db *gorm.DB, w Writer, opts Options) (int, error) {
+	count := 0
+	for _, metric := range opts.Metrics {
+		if err := w.BeginMetric(metric); err != nil {
+			return count, err
+		}
+		for _, product := range opts.Products {
+			n, err := exportMetric(db, w, metric, product, opts)
+			count += n
+			if err != nil {
+				return count, fmt.Errorf("failed to export %s history for %s: %w", metric, product.ASIN, err)
+			}
+		}
+	}
+	return count, w.Close()
+}
+
+// exportMetric 导出单个产品的单个指标
+func exportMetric(db *gorm.DB, w Writer, metric string, product Product, opts Options) (int, error) {
+	var query *gorm.DB
+	switch metric {
+	case MetricPrice:
+		query = db.Model(&models.PriceHistory{}).Select("recorded_at, price, currency, '' AS category, '' AS seller")
+	case MetricBSR:
+		query = db.Model(&models.RankingHistory{}).Select("recorded_at, bsr_rank, '' AS currency, COALESCE(bsr_category, category) AS category, '' AS seller").
+			Where("bsr_rank IS NOT NULL")
+	case MetricRating:
+		query = db.Model(&models.ReviewHistory{}).Select("recorded_at, average_rating, '' AS currency, '' AS category, '' AS seller").
+			Where("average_rating IS NOT NULL")
+	case MetricReviewCount:
+		query = db.Model(&models.ReviewHistory{}).Select("recorded_at, review_count, '' AS currency, '' AS category, '' AS seller")
+	case MetricBuyBox:
+		query = db.Model(&models.BuyBoxHistory{}).Select("recorded_at, winner_price, currency, '' AS category, COALESCE(winner_seller, '') AS seller")
+	default:
+		return 0, fmt.Errorf("unsupported metric: %s", metric)
+	}
+
+	rows, err := query.Where("product_id = ? AND recorded_at >= ? AND recorded_at < ?", product.ProductID, opts.From, opts.To).
+		Order("recorded_at ASC").
+		Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var (
+			recordedAt time.Time
+			value      sql.NullFloat64
+			currency   string
+			category   string
+			seller     string
+		)
+		if err := rows.Scan(&recordedAt, &value, &currency, &category, &seller); err != nil {
+			return count, err
+		}
+
+		record := Record{
+			TrackedID:  product.TrackedID,
+			ASIN:       product.ASIN,
+			Metric:     metric,
+			RecordedAt: recordedAt,
+			Currency:   currency,
+			Category:   category,
+			Seller:     seller,
+			Tags:       product.Tags,
+		}
+		if value.Valid {
+			v := value.Float64
+			record.Value = &v
+		}
+		opts.convert(&record)
+		if err := w.Write(record); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+func isMetric(metric string) bool {
+	for _, m := range Metrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// formatValue 数值格式化，nil 为空字符串
+func formatValue(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+// csvWriter 所有指标写在同一个表中
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvWriter) BeginMetric(string) error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.w.Write(Columns)
+}
+
+func (c *csvWriter) Write(r Record) error {
+	return c.w.Write([]string{
+		r.TrackedID,
+		r.ASIN,
+		r.Metric,
+		r.RecordedAt.UTC().Format(time.RFC3339),
+		formatValue(r.Value),
+		r.Currency,
+		r.Category,
+		r.Seller,
+		strings.Join(r.Tags, ";"),
+	})
+}
+
+func (c *csvWriter) Close() error {
+	if !c.headerWritten {
+		c.BeginMetric("")
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ndjsonWriter 每行一个JSON对象
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonWriter) BeginMetric(string) error { return nil }
+
+func (n *ndjsonWriter) Write(r Record) error {
+	r.RecordedAt = r.RecordedAt.UTC()
+	return n.enc.Encode(r)
+}
+
+func (n *ndjsonWriter) Close() error { return nil }
+
+// xlsxWriter 每个指标一个工作表
+type xlsxWriter struct {
+	w *XLSXWriter
+}
+
+func (x *xlsxWriter) BeginMetric(metric string) error {
+	if err := x.w.AddSheet(metric); err != nil {
+		return err
+	}
+	header := make([]interface{}, 0, len(Columns)-1)
+	for _, column := range Columns {
+		if column != "metric" {
+			header = append(header, column)
+		}
+	}
+	return x.w.WriteRow(header...)
+}
+
+func (x *xlsxWriter) Write(r Record) error {
+	return x.w.WriteRow(r.TrackedID, r.ASIN, r.RecordedAt, r.Value, r.Currency, r.Category, r.Seller, strings.Join(r.Tags, ";"))
+}
+
+func (x *xlsxWriter) Close() error {
+	return x.w.Close()
+}
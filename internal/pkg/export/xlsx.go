@@ -0,0 +1,221 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// XLSXWriter 流式写出只包含数据的 XLSX 工作簿
+// 工作表按顺序逐个写入 zip，不需要把整个工作簿保存在内存中
+type XLSXWriter struct {
+	zw     *zip.Writer
+	sheet  *bufio.Writer
+	sheets []string
+	closed bool
+}
+
+// NewXLSXWriter 创建 XLSX 写出器
+func NewXLSXWriter(w io.Writer) *XLSXWriter {
+	return &XLSXWriter{zw: zip.NewWriter(w)}
+}
+
+// AddSheet 结束当前工作表并开始新的工作表，名称超过31个字符或包含非法字符时自动处理
+func (x *XLSXWriter) AddSheet(name string) error {
+	if err := x.endSheet(); err != nil {
+		return err
+	}
+
+	x.sheets = append(x.sheets, x.sheetName(name))
+	f, err := x.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(x.sheets)))
+	if err != nil {
+		return err
+	}
+	x.sheet = bufio.NewWriter(f)
+	_, err = x.sheet.WriteString(xml.Header +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return err
+}
+
+// WriteRow 向当前工作表写入一行，支持 string、数值、bool、time.Time 和 *float64（nil 为空单元格）
+func (x *XLSXWriter) WriteRow(values ...interface{}) error {
+	if x.sheet == nil {
+		return fmt.Errorf("xlsx: WriteRow called before AddSheet")
+	}
+
+	x.sheet.WriteString("<row>")
+	for _, value := range values {
+		if err := x.writeCell(value); err != nil {
+			return err
+		}
+	}
+	_, err := x.sheet.WriteString("</row>")
+	return err
+}
+
+// Close 写出工作簿结构并关闭 zip，没有工作表时创建一个空工作表
+func (x *XLSXWriter) Close() error {
+	if x.closed {
+		return nil
+	}
+	x.closed = true
+
+	if len(x.sheets) == 0 {
+		if err := x.AddSheet("Sheet1"); err != nil {
+			return err
+		}
+	}
+	if err := x.endSheet(); err != nil {
+		return err
+	}
+
+	var workbook, workbookRels, contentTypes strings.Builder
+	workbook.WriteString(xml.Header +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	workbookRels.WriteString(xml.Header +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	contentTypes.WriteString(xml.Header +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+
+	for i, name := range x.sheets {
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(name), i+1, i+1)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	workbook.WriteString(`</sheets></workbook>`)
+	fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`, len(x.sheets)+1)
+	contentTypes.WriteString(`</Types>`)
+
+	parts := []struct{ name, content string }{
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", workbookRels.String()},
+		{"xl/styles.xml", xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<fonts count="1"><font/></fonts><fills count="1"><fill/></fills><borders count="1"><border/></borders>` +
+			`<cellStyleXfs count="1"><xf/></cellStyleXfs><cellXfs count="1"><xf/></cellXfs></styleSheet>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"[Content_Types].xml", contentTypes.String()},
+	}
+	for _, part := range parts {
+		f, err := x.zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	return x.zw.Close()
+}
+
+// endSheet 结束当前工作表
+func (x *XLSXWriter) endSheet() error {
+	if x.sheet == nil {
+		return nil
+	}
+	x.sheet.WriteString(`</sheetData></worksheet>`)
+	err := x.sheet.Flush()
+	x.sheet = nil
+	return err
+}
+
+// writeCell 写出单个单元格，字符串使用内联字符串避免共享字符串表
+func (x *XLSXWriter) writeCell(value interface{}) error {
+	var err error
+	switch v := value.(type) {
+	case nil:
+		_, err = x.sheet.WriteString("<c/>")
+	case *float64:
+		if v == nil {
+			_, err = x.sheet.WriteString("<c/>")
+		} else {
+			err = x.writeNumber(strconv.FormatFloat(*v, 'f', -1, 64))
+		}
+	case float64:
+		err = x.writeNumber(strconv.FormatFloat(v, 'f', -1, 64))
+	case int:
+		err = x.writeNumber(strconv.Itoa(v))
+	case int64:
+		err = x.writeNumber(strconv.FormatInt(v, 10))
+	case bool:
+		b := "0"
+		if v {
+			b = "1"
+		}
+		_, err = x.sheet.WriteString(`<c t="b"><v>` + b + `</v></c>`)
+	case time.Time:
+		err = x.writeString(v.UTC().Format(time.RFC3339))
+	case string:
+		err = x.writeString(v)
+	default:
+		err = x.writeString(fmt.Sprint(v))
+	}
+	return err
+}
+
+func (x *XLSXWriter) writeNumber(v string) error {
+	_, err := x.sheet.WriteString("<c><v>" + v + "</v></c>")
+	return err
+}
+
+func (x *XLSXWriter) writeString(v string) error {
+	if v == "" {
+		_, err := x.sheet.WriteString("<c/>")
+		return err
+	}
+	_, err := x.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">` + escapeXML(v) + `</t></is></c>`)
+	return err
+}
+
+// sheetName 生成合法且不重复的工作表名称
+func (x *XLSXWriter) sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", len(x.sheets)+1)
+	}
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+
+	base := name
+	for i := 2; x.hasSheet(name); i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		runes := []rune(base)
+		if len(runes)+len(suffix) > 31 {
+			runes = runes[:31-len(suffix)]
+		}
+		name = string(runes) + suffix
+	}
+	return name
+}
+
+func (x *XLSXWriter) hasSheet(name string) bool {
+	for _, existing := range x.sheets {
+		if strings.EqualFold(existing, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeXML 转义XML文本，非法字符替换为 U+FFFD
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,250 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BaseCurrency 汇率表的基准货币，所有汇率均表示为 1 USD 可兑换的目标货币数量
+const BaseCurrency = "USD"
+
+// dateLayout 汇率按天存储的日期格式
+const dateLayout = "2006-01-02"
+
+// RateProvider 可插拔的汇率数据源（例如第三方汇率API）
+type RateProvider interface {
+	// FetchDailyRates 获取某一天相对 BaseCurrency 的汇率
+	FetchDailyRates(ctx context.Context, day time.Time) (map[string]float64, error)
+}
+
+// RateFile 汇率文件格式
+//
+//	{"base": "USD", "rates": {"2025-09-01": {"EUR": 0.92, "JPY": 147.1}}}
+type RateFile struct {
+	Base  string                        `json:"base"`
+	Rates map[string]map[string]float64 `json:"rates"`
+}
+
+// Store 按天保存的汇率历史
+type Store struct {
+	mu    sync.RWMutex
+	days  []string                      // 已排序的日期列表，用于查找不晚于指定日期的最近汇率
+	rates map[string]map[string]float64 // date -> currency -> rate (相对 BaseCurrency)
+}
+
+// NewStore 创建空的汇率存储
+func NewStore() *Store {
+	return &Store{
+		rates: make(map[string]map[string]float64),
+	}
+}
+
+// LoadFile 从JSON文件加载汇率历史
+func LoadFile(path string) (*Store, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fx rate file: %w", err)
+	}
+
+	var file RateFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode fx rate file: %w", err)
+	}
+
+	if file.Base != "" && !strings.EqualFold(file.Base, BaseCurrency) {
+		return nil, fmt.Errorf("unsupported fx base currency %q, expected %s", file.Base, BaseCurrency)
+	}
+
+	store := NewStore()
+	for day, rates := range file.Rates {
+		date, err := time.Parse(dateLayout, day)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fx rate date %q: %w", day, err)
+		}
+		store.SetDailyRates(date, rates)
+	}
+
+	return store, nil
+}
+
+// SetDailyRates 设置某一天的汇率（会与当天已有汇率合并）
+func (s *Store) SetDailyRates(day time.Time, rates map[string]float64) {
+	key := day.UTC().Format(dateLayout)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dayRates, ok := s.rates[key]
+	if !ok {
+		dayRates = make(map[string]float64, len(rates))
+		s.rates[key] = dayRates
+		s.days = append(s.days, key)
+		sort.Strings(s.days)
+	}
+
+	for currency, rate := range rates {
+		if rate <= 0 {
+			continue
+		}
+		dayRates[strings.ToUpper(currency)] = rate
+	}
+}
+
+// Sync 从数据源拉取某一天的汇率并写入存储
+func (s *Store) Sync(ctx context.Context, provider RateProvider, day time.Time) error {
+	rates, err := provider.FetchDailyRates(ctx, day)
+	if err != nil {
+		return fmt.Errorf("failed to fetch fx rates: %w", err)
+	}
+	s.SetDailyRates(day, rates)
+	return nil
+}
+
+// Rate 获取指定日期 from -> to 的汇率，使用不晚于该日期的最近一天汇率
+func (s *Store) Rate(from, to string, at time.Time) (float64, error) {
+	from = NormalizeCurrency(from)
+	to = NormalizeCurrency(to)
+	if from == to {
+		return 1, nil
+	}
+
+	fromRate, err := s.baseRate(from, at)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := s.baseRate(to, at)
+	if err != nil {
+		return 0, err
+	}
+
+	return toRate / fromRate, nil
+}
+
+// baseRate 获取 BaseCurrency -> currency 的汇率
+func (s *Store) baseRate(currency string, at time.Time) (float64, error) {
+	if currency == BaseCurrency {
+		return 1, nil
+	}
+
+	key := at.UTC().Format(dateLayout)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// 找到第一个晚于 key 的位置，然后向前查找包含该币种的最近日期
+	idx := sort.Search(len(s.days), func(i int) bool { return s.days[i] > key })
+	for i := idx - 1; i >= 0; i-- {
+		if rate, ok := s.rates[s.days[i]][currency]; ok {
+			return rate, nil
+		}
+	}
+
+	// 请求日期早于所有历史记录时，退回到最早的可用汇率
+	for i := idx; i < len(s.days); i++ {
+		if rate, ok := s.rates[s.days[i]][currency]; ok {
+			return rate, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no fx rate available for %s", currency)
+}
+
+// Money 换算后的金额，保留原始值
+type Money struct {
+	Amount           float64 `json:"amount"`
+	Currency         string  `json:"currency"`
+	OriginalAmount   float64 `json:"original_amount"`
+	OriginalCurrency string  `json:"original_currency"`
+	Rate             float64 `json:"rate"`
+}
+
+// Converted 是否发生了币种转换
+func (m Money) Converted() bool {
+	return m.Currency != m.OriginalCurrency
+}
+
+// Converter 货币转换辅助
+type Converter struct {
+	store *Store
+}
+
+// NewConverter 创建转换器，store 为 nil 时只支持同币种"转换"
+func NewConverter(store *Store) *Converter {
+	if store == nil {
+		store = NewStore()
+	}
+	return &Converter{store: store}
+}
+
+// LoadConverter 根据汇率文件创建转换器，path 为空时返回仅支持同币种的转换器
+func LoadConverter(path string) (*Converter, error) {
+	if path == "" {
+		return NewConverter(nil), nil
+	}
+	store, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewConverter(store), nil
+}
+
+// Store 返回底层汇率存储
+func (c *Converter) Store() *Store {
+	return c.store
+}
+
+// Convert 将金额从 from 币种换算为 to 币种（按 at 当天的汇率）
+func (c *Converter) Convert(amount float64, from, to string, at time.Time) (Money, error) {
+	from = NormalizeCurrency(from)
+	to = NormalizeCurrency(to)
+
+	rate, err := c.store.Rate(from, to, at)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{
+		Amount:           round2(amount * rate),
+		Currency:         to,
+		OriginalAmount:   amount,
+		OriginalCurrency: from,
+		Rate:             rate,
+	}, nil
+}
+
+// ConvertOrKeep 换算金额，缺少汇率时保留原始币种
+func (c *Converter) ConvertOrKeep(amount float64, from, to string, at time.Time) Money {
+	money, err := c.Convert(amount, from, to, at)
+	if err != nil {
+		from = NormalizeCurrency(from)
+		return Money{
+			Amount:           amount,
+			Currency:         from,
+			OriginalAmount:   amount,
+			OriginalCurrency: from,
+			Rate:             1,
+		}
+	}
+	return money
+}
+
+// NormalizeCurrency 标准化货币代码（大写，空值视为USD）
+func NormalizeCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return BaseCurrency
+	}
+	return currency
+}
+
+// round2 保留两位小数
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
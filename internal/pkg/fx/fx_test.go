@@ -0,0 +1,115 @@
+package fx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type staticProvider map[string]float64
+
+func (p staticProvider) FetchDailyRates(ctx context.Context, day time.Time) (map[string]float64, error) {
+	return p, nil
+}
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestStoreRate_UsesLatestRateOnOrBeforeDate(t *testing.T) {
+	store := NewStore()
+	store.SetDailyRates(day("2025-09-01"), map[string]float64{"EUR": 0.90})
+	store.SetDailyRates(day("2025-09-05"), map[string]float64{"EUR": 0.95})
+
+	rate, err := store.Rate("USD", "EUR", day("2025-09-03"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.90, rate)
+
+	rate, err = store.Rate("USD", "EUR", day("2025-09-10"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.95, rate)
+
+	// 早于所有记录时使用最早的汇率
+	rate, err = store.Rate("usd", "eur", day("2025-01-01"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.90, rate)
+}
+
+func TestStoreRate_CrossCurrency(t *testing.T) {
+	store := NewStore()
+	store.SetDailyRates(day("2025-09-01"), map[string]float64{"EUR": 0.8, "GBP": 0.5})
+
+	rate, err := store.Rate("EUR", "GBP", day("2025-09-01"))
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.625, rate, 1e-9)
+
+	_, err = store.Rate("EUR", "JPY", day("2025-09-01"))
+	assert.Error(t, err)
+}
+
+func TestConverter_PreservesOriginalValue(t *testing.T) {
+	store := NewStore()
+	assert.NoError(t, store.Sync(context.Background(), staticProvider{"EUR": 0.9}, day("2025-09-01")))
+	converter := NewConverter(store)
+
+	money, err := converter.Convert(100, "USD", "EUR", day("2025-09-02"))
+	assert.NoError(t, err)
+	assert.Equal(t, 90.0, money.Amount)
+	assert.Equal(t, "EUR", money.Currency)
+	assert.Equal(t, 100.0, money.OriginalAmount)
+	assert.Equal(t, "USD", money.OriginalCurrency)
+	assert.True(t, money.Converted())
+
+	// 缺少汇率时保留原始币种
+	kept := converter.ConvertOrKeep(100, "JPY", "EUR", day("2025-09-02"))
+	assert.Equal(t, 100.0, kept.Amount)
+	assert.Equal(t, "JPY", kept.Currency)
+	assert.False(t, kept.Converted())
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	content := `{"base": "USD", "rates": {"2025-09-01": {"EUR": 0.92, "JPY": 147.1}}}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	store, err := LoadFile(path)
+	assert.NoError(t, err)
+
+	rate, err := store.Rate("USD", "JPY", day("2025-09-01"))
+	assert.NoError(t, err)
+	assert.Equal(t, 147.1, rate)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"base": "EUR", "rates": {}}`), 0o644))
+	_, err = LoadFile(path)
+	assert.Error(t, err)
+}
+
+func TestHTTPProvider_FetchDailyRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2025-09-01", r.URL.Path)
+		assert.Equal(t, "USD", r.URL.Query().Get("from"))
+		w.Write([]byte(`{"base":"USD","date":"2025-09-01","rates":{"EUR":0.9}}`))
+	}))
+	defer server.Close()
+
+	store := NewStore()
+	assert.NoError(t, store.Sync(context.Background(), NewHTTPProvider(server.URL+"/"), day("2025-09-01")))
+	rate, err := store.Rate("USD", "EUR", day("2025-09-01"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.9, rate)
+
+	// 非 200 响应返回错误
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	_, err = NewHTTPProvider(failing.URL).FetchDailyRates(context.Background(), day("2025-09-01"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPProvider 通过 Frankfurter 兼容的汇率API获取每日汇率
+//
+//	GET {baseURL}/2025-09-01?from=USD -> {"base": "USD", "date": "2025-09-01", "rates": {"EUR": 0.92}}
+type HTTPProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPProvider 创建HTTP汇率数据源
+func NewHTTPProvider(baseURL string) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// FetchDailyRates 获取某一天相对 BaseCurrency 的汇率，当天未发布时API返回最近一个工作日的汇率
+func (p *HTTPProvider) FetchDailyRates(ctx context.Context, day time.Time) (map[string]float64, error) {
+	url := fmt.Sprintf("%s/%s?from=%s", p.baseURL, day.UTC().Format(dateLayout), BaseCurrency)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fx provider returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Base  string             `json:"base"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode fx provider response: %w", err)
+	}
+	if body.Base != "" && !strings.EqualFold(body.Base, BaseCurrency) {
+		return nil, fmt.Errorf("unexpected fx base currency %q", body.Base)
+	}
+	if len(body.Rates) == 0 {
+		return nil, fmt.Errorf("fx provider returned no rates")
+	}
+	return body.Rates, nil
+}
+
+// StartDailySync 在后台立即同步当天汇率，之后每隔 interval 再同步一次，直到 ctx 取消
+// 每个进程各自持有内存中的汇率，需要换算货币的服务都应启动同步
+func (s *Store) StartDailySync(ctx context.Context, provider RateProvider, interval time.Duration) {
+	sync := func() {
+		if err := s.Sync(ctx, provider, time.Now().UTC()); err != nil {
+			slog.Warn("Failed to sync fx rates", "error", err)
+		}
+	}
+
+	go func() {
+		sync()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sync()
+			}
+		}
+	}()
+}
+
+// SyncInterval 汇率同步间隔
+const SyncInterval = 24 * time.Hour
+
+// StartProviderSync 配置了汇率API时为转换器启动每日同步，否则只使用汇率文件中的数据
+func StartProviderSync(ctx context.Context, converter *Converter, providerURL string) {
+	if providerURL == "" {
+		return
+	}
+	converter.Store().StartDailySync(ctx, NewHTTPProvider(providerURL), SyncInterval)
+}
@@ -61,12 +61,15 @@ func (c *DeepSeekClient) buildCompetitorAnalysisPrompt(data CompetitorAnalysisDa
 - 价格: %.2f %s
 - BSR排名: %d
 - 评分: %.1f (%d条评论)
-`, data.MainProduct.ASIN, data.MainProduct.Title, data.MainProduct.Price, data.MainProduct.Currency, data.MainProduct.BSR, data.MainProduct.Rating, data.MainProduct.ReviewCount)
+- 预估日销量: %.1f 件, 预估月收入: %.2f %s
+`, data.MainProduct.ASIN, data.MainProduct.Title, data.MainProduct.Price, data.MainProduct.Currency, data.MainProduct.BSR, data.MainProduct.Rating, data.MainProduct.ReviewCount,
+		data.MainProduct.EstUnitsPerDay, data.MainProduct.EstMonthlyRevenue, data.MainProduct.Currency)
 
 	prompt += "\n竞品产品：\n"
 	for i, competitor := range data.Competitors {
-		prompt += fmt.Sprintf(`%d. ASIN: %s, 价格: %.2f %s, BSR: %d, 评分: %.1f (%d条评论)
-`, i+1, competitor.ASIN, competitor.Price, competitor.Currency, competitor.BSR, competitor.Rating, competitor.ReviewCount)
+		prompt += fmt.Sprintf(`%d. ASIN: %s, 价格: %.2f %s, BSR: %d, 评分: %.1f (%d条评论), 预估日销量: %.1f 件, 预估月收入: %.2f %s
+`, i+1, competitor.ASIN, competitor.Price, competitor.Currency, competitor.BSR, competitor.Rating, competitor.ReviewCount,
+			competitor.EstUnitsPerDay, competitor.EstMonthlyRevenue, competitor.Currency)
 	}
 
 	prompt += `
@@ -34,6 +34,10 @@ type ProductData struct {
 	Rating      float64  `json:"rating"`
 	ReviewCount int      `json:"review_count"`
 	BulletPoints []string `json:"bullet_points,omitempty"`
+
+	// 按 BSR 曲线估算的近30天销量，月收入与 Currency 一致
+	EstUnitsPerDay    float64 `json:"est_units_per_day,omitempty"`
+	EstMonthlyRevenue float64 `json:"est_monthly_revenue,omitempty"`
 }
 
 // CompetitorReport 竞争定位报告
@@ -133,12 +137,15 @@ func (c *OpenAIClient) buildCompetitorAnalysisPrompt(data CompetitorAnalysisData
 - 价格: %.2f %s
 - BSR排名: %d
 - 评分: %.1f (%d条评论)
-`, data.MainProduct.ASIN, data.MainProduct.Title, data.MainProduct.Price, data.MainProduct.Currency, data.MainProduct.BSR, data.MainProduct.Rating, data.MainProduct.ReviewCount)
+- 预估日销量: %.1f 件, 预估月收入: %.2f %s
+`, data.MainProduct.ASIN, data.MainProduct.Title, data.MainProduct.Price, data.MainProduct.Currency, data.MainProduct.BSR, data.MainProduct.Rating, data.MainProduct.ReviewCount,
+		data.MainProduct.EstUnitsPerDay, data.MainProduct.EstMonthlyRevenue, data.MainProduct.Currency)
 
 	prompt += "\n竞品产品：\n"
 	for i, competitor := range data.Competitors {
-		prompt += fmt.Sprintf(`%d. ASIN: %s, 价格: %.2f %s, BSR: %d, 评分: %.1f (%d条评论)
-`, i+1, competitor.ASIN, competitor.Price, competitor.Currency, competitor.BSR, competitor.Rating, competitor.ReviewCount)
+		prompt += fmt.Sprintf(`%d. ASIN: %s, 价格: %.2f %s, BSR: %d, 评分: %.1f (%d条评论), 预估日销量: %.1f 件, 预估月收入: %.2f %s
+`, i+1, competitor.ASIN, competitor.Price, competitor.Currency, competitor.BSR, competitor.Rating, competitor.ReviewCount,
+			competitor.EstUnitsPerDay, competitor.EstMonthlyRevenue, competitor.Currency)
 	}
 
 	prompt += `
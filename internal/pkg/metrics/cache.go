@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Cache metrics
+var (
+	// 缓存查询次数，result 为 hit / miss / error
+	CacheRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "amazon_pilot_cache_requests_total",
+			Help: "Total number of cache lookups",
+		},
+		[]string{"cache", "result"},
+	)
+
+	// 缓存写入次数，source 为 load (读取时回填) / write_through (数据更新后写入)
+	CacheWritesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "amazon_pilot_cache_writes_total",
+			Help: "Total number of cache writes",
+		},
+		[]string{"cache", "source"},
+	)
+
+	// 缓存未命中时回源加载耗时 (合并后的请求只计一次)
+	CacheLoadDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "amazon_pilot_cache_load_duration_milliseconds",
+			Help:    "Cache miss load duration in milliseconds",
+			Buckets: []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500},
+		},
+		[]string{"cache"},
+	)
+
+	// 合并到进行中加载的请求数
+	CacheCoalescedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "amazon_pilot_cache_coalesced_total",
+			Help: "Total number of cache loads served by an in-flight load",
+		},
+		[]string{"cache"},
+	)
+)
+
+// RecordCacheLookup 记录缓存查询结果
+func RecordCacheLookup(cacheName, result string, count int) {
+	if count > 0 {
+		CacheRequestsTotal.WithLabelValues(cacheName, result).Add(float64(count))
+	}
+}
+
+// RecordCacheWrite 记录缓存写入
+func RecordCacheWrite(cacheName, source string, count int) {
+	if count > 0 {
+		CacheWritesTotal.WithLabelValues(cacheName, source).Add(float64(count))
+	}
+}
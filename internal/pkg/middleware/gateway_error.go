@@ -40,6 +40,8 @@ func getErrorCode(httpCode int) string {
 		return errors.CodeConflict
 	case http.StatusTooManyRequests:
 		return errors.CodeRateLimitExceeded
+	case http.StatusPaymentRequired:
+		return errors.CodeQuotaExceeded
 	case http.StatusInternalServerError:
 		return errors.CodeInternalError
 	case http.StatusServiceUnavailable:
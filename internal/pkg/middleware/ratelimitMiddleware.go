@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/quota"
 
 	"github.com/zeromicro/go-zero/rest/httpx"
 )
@@ -157,14 +158,7 @@ func getUserPlan(ctx context.Context) string {
 
 // getRateLimitForPlan 获取计划对应的限流数量
 func getRateLimitForPlan(plan string) int {
-	switch plan {
-	case "premium":
-		return 500
-	case "enterprise":
-		return 2000
-	default:
-		return 100
-	}
+	return quota.ForPlan(plan).RateLimitPerMinute
 }
 
 // getClientIP 获取客户端IP
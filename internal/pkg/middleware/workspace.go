@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// WorkspaceHeader 指定请求操作的工作区，缺省时使用用户的个人工作区
+const WorkspaceHeader = "X-Workspace-ID"
+
+// WorkspaceMiddleware 工作区中间件，只负责把请求头中的工作区ID放入context，成员和角色由业务逻辑校验
+type WorkspaceMiddleware struct{}
+
+// NewWorkspaceMiddleware 创建工作区中间件
+func NewWorkspaceMiddleware() *WorkspaceMiddleware {
+	return &WorkspaceMiddleware{}
+}
+
+// Handle 处理工作区请求头
+func (m *WorkspaceMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if workspaceID := strings.TrimSpace(r.Header.Get(WorkspaceHeader)); workspaceID != "" {
+			r = r.WithContext(context.WithValue(r.Context(), "workspace_id", workspaceID))
+		}
+		next(w, r)
+	}
+}
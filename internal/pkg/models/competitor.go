@@ -10,6 +10,7 @@ import (
 type CompetitorAnalysisGroup struct {
 	ID              string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
 	UserID          string         `gorm:"not null;type:uuid" json:"user_id"`
+	WorkspaceID     string         `gorm:"not null;type:uuid" json:"workspace_id"`
 	Name            string         `gorm:"not null;size:255" json:"name"`
 	Description     *string        `gorm:"type:text" json:"description,omitempty"`
 	MainProductID   string         `gorm:"not null;type:uuid" json:"main_product_id"`
@@ -10,6 +10,7 @@ import (
 type OptimizationAnalysis struct {
 	ID           string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
 	UserID       string         `gorm:"not null;type:uuid" json:"user_id"`
+	WorkspaceID  string         `gorm:"not null;type:uuid" json:"workspace_id"`
 	ProductID    string         `gorm:"not null;type:uuid" json:"product_id"`
 	AnalysisType string         `gorm:"default:comprehensive;size:50" json:"analysis_type"`
 	FocusAreas   datatypes.JSON `gorm:"type:jsonb;default:'[\"title\", \"pricing\", \"description\", \"images\", \"keywords\"]'" json:"focus_areas"`
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/datatypes"
@@ -26,6 +27,10 @@ type Product struct {
 	UPC          *string `gorm:"size:20" json:"upc,omitempty"`
 	EAN          *string `gorm:"size:20" json:"ean,omitempty"`
 
+	// 变体信息 (父ASIN下的颜色、尺寸等子款共享评论和评分)
+	ParentASIN          *string        `gorm:"size:10;index" json:"parent_asin,omitempty"`
+	VariationAttributes datatypes.JSON `gorm:"type:jsonb" json:"variation_attributes,omitempty"`
+
 	// 时间戳
 	FirstSeenAt   time.Time `gorm:"default:now()" json:"first_seen_at"`
 	LastUpdatedAt time.Time `gorm:"default:now()" json:"last_updated_at"`
@@ -35,6 +40,7 @@ type Product struct {
 	TrackedBy      []TrackedProduct `gorm:"foreignKey:ProductID" json:"tracked_by,omitempty"`
 	PriceHistory   []PriceHistory   `gorm:"foreignKey:ProductID" json:"price_history,omitempty"`
 	RankingHistory []RankingHistory `gorm:"foreignKey:ProductID" json:"ranking_history,omitempty"`
+	LatestSnapshot *LatestSnapshot  `gorm:"foreignKey:ProductID" json:"latest_snapshot,omitempty"`
 }
 
 // TableName 表名
@@ -44,22 +50,27 @@ func (Product) TableName() string {
 
 // TrackedProduct 用户追踪的产品
 type TrackedProduct struct {
-	ID                   string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
-	UserID               string     `gorm:"not null;type:uuid" json:"user_id"`
-	ProductID            string     `gorm:"not null;type:uuid" json:"product_id"`
-	Alias                *string    `gorm:"size:255" json:"alias,omitempty"`
-	IsActive             bool       `gorm:"default:true" json:"is_active"`
-	TrackingFrequency    string     `gorm:"default:daily;size:20" json:"tracking_frequency"`
-	PriceChangeThreshold float64    `gorm:"default:10.0;type:decimal(5,2)" json:"price_change_threshold"`
-	BSRChangeThreshold   float64    `gorm:"default:30.0;type:decimal(5,2)" json:"bsr_change_threshold"`
-	CreatedAt            time.Time  `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt            time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
-	LastCheckedAt        *time.Time `json:"last_checked_at,omitempty"`
-	NextCheckAt          *time.Time `json:"next_check_at,omitempty"`
+	ID                   string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID               string         `gorm:"not null;type:uuid" json:"user_id"`
+	WorkspaceID          string         `gorm:"not null;type:uuid" json:"workspace_id"`
+	ProductID            string         `gorm:"not null;type:uuid" json:"product_id"`
+	Alias                *string        `gorm:"size:255" json:"alias,omitempty"`
+	IsActive             bool           `gorm:"default:true" json:"is_active"`
+	TrackingFrequency    string         `gorm:"default:daily;size:20" json:"tracking_frequency"`
+	PriceChangeThreshold float64        `gorm:"default:10.0;type:decimal(5,2)" json:"price_change_threshold"`
+	BSRChangeThreshold   float64        `gorm:"default:30.0;type:decimal(5,2)" json:"bsr_change_threshold"`
+	TrackVariations      bool           `gorm:"default:false" json:"track_variations"`          // 自动追踪同一父ASIN下的子变体
+	AuthorizedSellers    datatypes.JSON `gorm:"type:jsonb" json:"authorized_sellers,omitempty"` // 授权卖家白名单 []SellerIdentity
+	MAPPrice             *float64       `gorm:"type:decimal(10,2)" json:"map_price,omitempty"`  // 最低广告价，为空时不监控
+	CreatedAt            time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt            time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	LastCheckedAt        *time.Time     `json:"last_checked_at,omitempty"`
+	NextCheckAt          *time.Time     `json:"next_check_at,omitempty"`
 
 	// 关联
 	User    User    `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	Product Product `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	Tags    []Tag   `gorm:"many2many:tracked_product_tags" json:"tags,omitempty"`
 }
 
 // TableName 表名
@@ -67,6 +78,120 @@ func (TrackedProduct) TableName() string {
 	return "tracked_products"
 }
 
+// Tag 用户自定义的追踪产品标签
+type Tag struct {
+	ID          string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID      string    `gorm:"not null;type:uuid" json:"user_id"`
+	WorkspaceID string    `gorm:"not null;type:uuid" json:"workspace_id"`
+	Name        string    `gorm:"not null;size:50" json:"name"`
+	Color       string    `gorm:"size:20" json:"color,omitempty"` // 例如 #3B82F6
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// TrackedProductTag 追踪产品与标签的多对多关联
+type TrackedProductTag struct {
+	TrackedProductID string    `gorm:"primaryKey;type:uuid" json:"tracked_product_id"`
+	TagID            string    `gorm:"primaryKey;type:uuid" json:"tag_id"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 表名
+func (TrackedProductTag) TableName() string {
+	return "tracked_product_tags"
+}
+
+// ImportJob 批量导入追踪产品的异步任务
+type ImportJob struct {
+	ID            string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID        string         `gorm:"not null;type:uuid" json:"user_id"`
+	WorkspaceID   string         `gorm:"not null;type:uuid" json:"workspace_id"`
+	Status        string         `gorm:"not null;default:queued;size:20" json:"status"` // queued, processing, completed, failed
+	Format        string         `gorm:"size:10" json:"format"`                         // csv, json
+	TotalRows     int            `gorm:"default:0" json:"total_rows"`
+	ProcessedRows int            `gorm:"default:0" json:"processed_rows"`
+	CreatedRows   int            `gorm:"default:0" json:"created_rows"`
+	ExistingRows  int            `gorm:"default:0" json:"existing_rows"` // 已在追踪的行
+	FailedRows    int            `gorm:"default:0" json:"failed_rows"`
+	Rows          datatypes.JSON `gorm:"type:jsonb" json:"-"`                 // 解析后的待导入行 []ImportRow
+	Results       datatypes.JSON `gorm:"type:jsonb" json:"results,omitempty"` // 逐行结果 []ImportRowResult
+	ErrorMessage  *string        `gorm:"type:text" json:"error_message,omitempty"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	StartedAt     *time.Time     `json:"started_at,omitempty"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty"`
+}
+
+// TableName 表名
+func (ImportJob) TableName() string {
+	return "tracking_import_jobs"
+}
+
+// ExportJob 历史数据导出的后台任务，生成的文件保存在导出目录下
+type ExportJob struct {
+	ID           string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID       string         `gorm:"not null;type:uuid" json:"user_id"`
+	WorkspaceID  string         `gorm:"not null;type:uuid" json:"workspace_id"`
+	Status       string         `gorm:"not null;default:queued;size:20" json:"status"` // queued, processing, completed, failed, expired
+	Format       string         `gorm:"not null;size:10" json:"format"`                // csv, ndjson, xlsx
+	Params       datatypes.JSON `gorm:"type:jsonb;not null" json:"params"`             // 导出参数 (tracked_ids, metrics, from, to)
+	FileName     *string        `gorm:"size:255" json:"file_name,omitempty"`
+	FileSize     int64          `gorm:"default:0" json:"file_size"`
+	RowCount     int            `gorm:"default:0" json:"row_count"`
+	ErrorMessage *string        `gorm:"type:text" json:"error_message,omitempty"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	StartedAt    *time.Time     `json:"started_at,omitempty"`
+	CompletedAt  *time.Time     `json:"completed_at,omitempty"`
+	ExpiresAt    *time.Time     `json:"expires_at,omitempty"`
+}
+
+// TableName 表名
+func (ExportJob) TableName() string {
+	return "history_export_jobs"
+}
+
+// SellerIdentity 卖家身份，优先按卖家ID匹配，没有ID时按名称匹配（忽略大小写）
+type SellerIdentity struct {
+	SellerName string `json:"seller_name"`
+	SellerID   string `json:"seller_id,omitempty"`
+}
+
+// Matches 判断报价中的卖家是否为该身份
+func (s SellerIdentity) Matches(name, id string) bool {
+	if s.SellerID != "" && id != "" {
+		return strings.EqualFold(s.SellerID, id)
+	}
+	return s.SellerName != "" && strings.EqualFold(strings.TrimSpace(s.SellerName), strings.TrimSpace(name))
+}
+
+// SellerAccount 用户声明的自有卖家账号 (品牌方用于跟卖检测)
+type SellerAccount struct {
+	ID          string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID      string    `gorm:"not null;type:uuid;index" json:"user_id"`
+	WorkspaceID string    `gorm:"not null;type:uuid;index" json:"workspace_id"`
+	SellerName  string    `gorm:"not null;size:255" json:"seller_name"`
+	SellerID    *string   `gorm:"size:50" json:"seller_id,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 表名
+func (SellerAccount) TableName() string {
+	return "user_seller_accounts"
+}
+
+// Identity 转换为卖家身份
+func (a SellerAccount) Identity() SellerIdentity {
+	identity := SellerIdentity{SellerName: a.SellerName}
+	if a.SellerID != nil {
+		identity.SellerID = *a.SellerID
+	}
+	return identity
+}
+
 // PriceHistory 价格历史记录
 type PriceHistory struct {
 	ID                 string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
@@ -76,6 +201,9 @@ type PriceHistory struct {
 	BuyBoxPrice        *float64  `gorm:"type:decimal(10,2)" json:"buy_box_price,omitempty"`
 	IsOnSale           bool      `gorm:"default:false" json:"is_on_sale"`
 	DiscountPercentage *float64  `gorm:"type:decimal(5,2)" json:"discount_percentage,omitempty"`
+	ListPrice          *float64  `gorm:"type:decimal(10,2)" json:"list_price,omitempty"`
+	DealType           *string   `gorm:"size:50" json:"deal_type,omitempty"` // lightning_deal, prime_day, limited_time_deal, deal_of_the_day, deal
+	CouponText         *string   `gorm:"size:255" json:"coupon_text,omitempty"`
 	RecordedAt         time.Time `gorm:"default:now()" json:"recorded_at"`
 	DataSource         string    `gorm:"default:apify;size:50" json:"data_source"`
 
@@ -100,6 +228,9 @@ type RankingHistory struct {
 	RecordedAt  time.Time `gorm:"default:now()" json:"recorded_at"`
 	DataSource  string    `gorm:"default:apify;size:50" json:"data_source"`
 
+	// 按类目销量曲线估算的日销量
+	EstUnitsPerDay *float64 `gorm:"type:decimal(12,2)" json:"est_units_per_day,omitempty"`
+
 	// 关联
 	Product Product `gorm:"foreignKey:ProductID" json:"product,omitempty"`
 }
@@ -109,6 +240,26 @@ func (RankingHistory) TableName() string {
 	return "product_ranking_history"
 }
 
+// CategoryRank 排名快照中的单个类目排名（根类目和各小类目）
+// 与同一次刷新的 RankingHistory 通过 RankingHistoryID 关联
+type CategoryRank struct {
+	ID               string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	RankingHistoryID string    `gorm:"not null;type:uuid" json:"ranking_history_id"`
+	ProductID        string    `gorm:"not null;type:uuid" json:"product_id"`
+	Category         string    `gorm:"not null;size:255" json:"category"`
+	Rank             int       `gorm:"not null" json:"rank"`
+	IsRoot           bool      `gorm:"default:false" json:"is_root"`
+	RecordedAt       time.Time `gorm:"default:now()" json:"recorded_at"`
+
+	// 关联
+	Product Product `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+}
+
+// TableName 表名
+func (CategoryRank) TableName() string {
+	return "product_category_ranks"
+}
+
 // ReviewHistory 评论历史记录
 type ReviewHistory struct {
 	ID             string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
@@ -137,6 +288,7 @@ type BuyBoxHistory struct {
 	ID               string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
 	ProductID        string    `gorm:"not null;type:uuid" json:"product_id"`
 	WinnerSeller     *string   `gorm:"size:255" json:"winner_seller,omitempty"`
+	WinnerSellerID   *string   `gorm:"size:50" json:"winner_seller_id,omitempty"`
 	WinnerPrice      *float64  `gorm:"type:decimal(10,2)" json:"winner_price,omitempty"`
 	Currency         string    `gorm:"not null;default:USD;size:3" json:"currency"`
 	IsPrime          bool      `gorm:"default:false" json:"is_prime"`
@@ -155,11 +307,113 @@ func (BuyBoxHistory) TableName() string {
 	return "product_buybox_history"
 }
 
+// AvailabilityHistory 库存状态历史记录
+type AvailabilityHistory struct {
+	ID               string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	ProductID        string    `gorm:"not null;type:uuid" json:"product_id"`
+	State            string    `gorm:"not null;size:20" json:"state"` // in_stock, low_stock, out_of_stock, back_order, unavailable, unknown
+	StockQuantity    *int      `json:"stock_quantity,omitempty"`      // 仅 low_stock 时有值
+	AvailabilityText *string   `gorm:"size:255" json:"availability_text,omitempty"`
+	RecordedAt       time.Time `gorm:"default:now()" json:"recorded_at"`
+	DataSource       string    `gorm:"default:apify;size:50" json:"data_source"`
+
+	// 关联
+	Product Product `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+}
+
+// TableName 表名
+func (AvailabilityHistory) TableName() string {
+	return "product_availability_history"
+}
+
+// OfferHistory 卖家报价历史记录
+// 同一次刷新抓取到的所有报价共享相同的 RecordedAt，构成一个报价快照
+type OfferHistory struct {
+	ID             string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	ProductID      string    `gorm:"not null;type:uuid" json:"product_id"`
+	SellerName     string    `gorm:"not null;size:255" json:"seller_name"`
+	SellerID       *string   `gorm:"size:50" json:"seller_id,omitempty"`
+	Price          float64   `gorm:"not null;type:decimal(10,2)" json:"price"`
+	ShippingPrice  float64   `gorm:"default:0;type:decimal(10,2)" json:"shipping_price"`
+	Currency       string    `gorm:"not null;default:USD;size:3" json:"currency"`
+	Condition      string    `gorm:"default:new;size:50" json:"condition"`
+	IsFBA          bool      `gorm:"default:false" json:"is_fba"`
+	IsPrime        bool      `gorm:"default:false" json:"is_prime"`
+	IsBuyBoxWinner bool      `gorm:"default:false" json:"is_buy_box_winner"`
+	RecordedAt     time.Time `gorm:"default:now()" json:"recorded_at"`
+	DataSource     string    `gorm:"default:apify;size:50" json:"data_source"`
+
+	// 关联
+	Product Product `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+}
+
+// TableName 表名
+func (OfferHistory) TableName() string {
+	return "product_offer_history"
+}
+
+// LandedPrice 含运费的到手价
+func (o *OfferHistory) LandedPrice() float64 {
+	return o.Price + o.ShippingPrice
+}
+
+// LatestSnapshot 产品最新数据快照，每个产品一行
+// 由 worker 在刷新事务中与历史记录一起更新，列表、详情和报告直接读取，避免逐个查询分区历史表
+type LatestSnapshot struct {
+	ProductID         string   `gorm:"primaryKey;type:uuid" json:"product_id"`
+	Price             float64  `gorm:"not null;type:decimal(10,2)" json:"price"`
+	Currency          string   `gorm:"not null;default:USD;size:3" json:"currency"`
+	BuyBoxPrice       *float64 `gorm:"type:decimal(10,2)" json:"buy_box_price,omitempty"`
+	BSRRank           *int     `json:"bsr_rank,omitempty"`
+	BSRCategory       *string  `gorm:"size:255" json:"bsr_category,omitempty"`
+	Rating            *float64 `gorm:"type:decimal(3,2)" json:"rating,omitempty"`
+	ReviewCount       int      `gorm:"default:0" json:"review_count"`
+	AvailabilityState string   `gorm:"not null;default:unknown;size:20" json:"availability_state"`
+	AvailabilityText  *string  `gorm:"size:255" json:"availability_text,omitempty"`
+
+	// 相对24小时前最后一条记录的变化，24小时前没有数据时为空
+	PriceChange24h       *float64 `gorm:"type:decimal(10,2)" json:"price_change_24h,omitempty"` // 百分比
+	BSRChange24h         *int     `json:"bsr_change_24h,omitempty"`                             // 正数表示排名下降
+	RatingChange24h      *float64 `gorm:"type:decimal(4,2)" json:"rating_change_24h,omitempty"`
+	ReviewCountChange24h *int     `json:"review_count_change_24h,omitempty"`
+
+	RecordedAt time.Time `gorm:"not null" json:"recorded_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (LatestSnapshot) TableName() string {
+	return "product_latest_snapshot"
+}
+
+// ListingSnapshot 产品Listing内容快照
+// 仅在标题、描述、五点描述或图片发生变化时写入新版本
+type ListingSnapshot struct {
+	ID           string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	ProductID    string         `gorm:"not null;type:uuid;index" json:"product_id"`
+	Title        *string        `gorm:"type:text" json:"title,omitempty"`
+	Description  *string        `gorm:"type:text" json:"description,omitempty"`
+	BulletPoints datatypes.JSON `gorm:"type:jsonb" json:"bullet_points,omitempty"`
+	Images       datatypes.JSON `gorm:"type:jsonb" json:"images,omitempty"`
+	ContentHash  string         `gorm:"not null;size:64" json:"content_hash"`
+	RecordedAt   time.Time      `gorm:"default:now()" json:"recorded_at"`
+	DataSource   string         `gorm:"default:apify;size:50" json:"data_source"`
+
+	// 关联
+	Product Product `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+}
+
+// TableName 表名
+func (ListingSnapshot) TableName() string {
+	return "product_content_snapshots"
+}
+
 // AnomalyEvent 异常事件表 (分区表)
 // 用于记录产品数据异常变化（价格变动>10%、BSR变动>30%等）
 type AnomalyEvent struct {
 	ID               string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
 	ProductID        string         `gorm:"not null;type:uuid" json:"product_id"`
+	WorkspaceID      *string        `gorm:"type:uuid" json:"workspace_id,omitempty"` // 为空时对所有追踪该产品的工作区可见
 	ASIN             string         `gorm:"not null;size:20" json:"asin"`
 	EventType        string         `gorm:"not null;size:50" json:"event_type"`
 	OldValue         *float64       `gorm:"type:decimal(15,2)" json:"old_value,omitempty"`
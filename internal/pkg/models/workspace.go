@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Workspace 工作区，追踪产品、标签、竞品分析组和优化分析等资源归属于工作区，成员按角色共享
+type Workspace struct {
+	ID         string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	Name       string    `gorm:"not null;size:100" json:"name"`
+	OwnerID    string    `gorm:"not null;type:uuid;index" json:"owner_id"` // 创建者
+	IsPersonal bool      `gorm:"default:false" json:"is_personal"`         // 注册时自动创建的个人工作区，不可删除
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (Workspace) TableName() string {
+	return "workspaces"
+}
+
+// WorkspaceMember 工作区成员
+type WorkspaceMember struct {
+	ID          string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	WorkspaceID string    `gorm:"not null;type:uuid;uniqueIndex:idx_workspace_members_workspace_user" json:"workspace_id"`
+	UserID      string    `gorm:"not null;type:uuid;uniqueIndex:idx_workspace_members_workspace_user;index" json:"user_id"`
+	Role        string    `gorm:"not null;default:viewer;size:20" json:"role"` // owner, editor, viewer
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// 关联
+	User      User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Workspace Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
+}
+
+// TableName 表名
+func (WorkspaceMember) TableName() string {
+	return "workspace_members"
+}
+
+// WorkspaceInvitation 工作区邀请，被邀请人以相同邮箱登录后接受
+type WorkspaceInvitation struct {
+	ID          string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	WorkspaceID string     `gorm:"not null;type:uuid;index" json:"workspace_id"`
+	Email       string     `gorm:"not null;size:255;index" json:"email"`
+	Role        string     `gorm:"not null;default:viewer;size:20" json:"role"`
+	Status      string     `gorm:"not null;default:pending;size:20" json:"status"` // pending, accepted, revoked
+	InvitedBy   string     `gorm:"not null;type:uuid" json:"invited_by"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// 关联
+	Workspace Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
+}
+
+// TableName 表名
+func (WorkspaceInvitation) TableName() string {
+	return "workspace_invitations"
+}
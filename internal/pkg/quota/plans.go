@@ -0,0 +1,100 @@
+package quota
+
+import "time"
+
+// 订阅计划
+const (
+	PlanBasic      = "basic"
+	PlanPremium    = "premium"
+	PlanEnterprise = "enterprise"
+)
+
+// Unlimited 数量类权益为 0 时表示不限
+const Unlimited = 0
+
+// Entitlements 订阅计划的权益
+type Entitlements struct {
+	Plan                 string
+	MaxTrackedProducts   int    // 工作区追踪产品数上限
+	MinRefreshFrequency  string // 允许的最高追踪频率 (hourly > daily > weekly)
+	MaxCompetitorGroups  int    // 工作区竞品分析组数上限
+	ReportsPerMonth      int    // 每自然月 (UTC) 生成的 LLM 报告数，包括竞品报告和优化分析
+	HistoryRetentionDays int    // 可查询和导出的历史数据天数
+	RateLimitPerMinute   int    // 每分钟请求数
+}
+
+var plans = map[string]Entitlements{
+	PlanBasic: {
+		Plan:                 PlanBasic,
+		MaxTrackedProducts:   20,
+		MinRefreshFrequency:  "daily",
+		MaxCompetitorGroups:  3,
+		ReportsPerMonth:      10,
+		HistoryRetentionDays: 90,
+		RateLimitPerMinute:   100,
+	},
+	PlanPremium: {
+		Plan:                 PlanPremium,
+		MaxTrackedProducts:   200,
+		MinRefreshFrequency:  "hourly",
+		MaxCompetitorGroups:  20,
+		ReportsPerMonth:      100,
+		HistoryRetentionDays: 365,
+		RateLimitPerMinute:   500,
+	},
+	PlanEnterprise: {
+		Plan:                 PlanEnterprise,
+		MaxTrackedProducts:   Unlimited,
+		MinRefreshFrequency:  "hourly",
+		MaxCompetitorGroups:  Unlimited,
+		ReportsPerMonth:      Unlimited,
+		HistoryRetentionDays: Unlimited,
+		RateLimitPerMinute:   2000,
+	},
+}
+
+// frequencyRanks 追踪频率由低到高
+var frequencyRanks = map[string]int{
+	"weekly": 1,
+	"daily":  2,
+	"hourly": 3,
+}
+
+// ForPlan 返回计划的权益，未知计划按 basic 处理
+func ForPlan(plan string) Entitlements {
+	if e, ok := plans[plan]; ok {
+		return e
+	}
+	return plans[PlanBasic]
+}
+
+// AllowsFrequency 判断计划是否允许该追踪频率
+func (e Entitlements) AllowsFrequency(frequency string) bool {
+	rank, ok := frequencyRanks[frequency]
+	return ok && rank <= frequencyRanks[e.MinRefreshFrequency]
+}
+
+// RetentionStart 返回可查询历史数据的最早时间，不限时返回零值
+func (e Entitlements) RetentionStart(now time.Time) time.Time {
+	if e.HistoryRetentionDays == Unlimited {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -e.HistoryRetentionDays)
+}
+
+// Remaining 返回上限内的剩余数量，不限时返回 -1
+func Remaining(limit int, used int64) int {
+	if limit == Unlimited {
+		return -1
+	}
+	if remaining := int64(limit) - used; remaining > 0 {
+		return int(remaining)
+	}
+	return 0
+}
+
+// MonthStart 返回报告额度所在自然月 (UTC) 的开始时间
+func MonthStart(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
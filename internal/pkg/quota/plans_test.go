@@ -0,0 +1,57 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForPlan(t *testing.T) {
+	assert.Equal(t, PlanBasic, ForPlan("basic").Plan)
+	assert.Equal(t, PlanPremium, ForPlan("premium").Plan)
+	assert.Equal(t, PlanEnterprise, ForPlan("enterprise").Plan)
+
+	// 未知或为空的计划按 basic 处理
+	assert.Equal(t, PlanBasic, ForPlan("").Plan)
+	assert.Equal(t, PlanBasic, ForPlan("pro").Plan)
+
+	// 计划越高权益越多
+	basic, premium := ForPlan(PlanBasic), ForPlan(PlanPremium)
+	assert.Less(t, basic.MaxTrackedProducts, premium.MaxTrackedProducts)
+	assert.Less(t, basic.MaxCompetitorGroups, premium.MaxCompetitorGroups)
+	assert.Less(t, basic.ReportsPerMonth, premium.ReportsPerMonth)
+	assert.Less(t, basic.HistoryRetentionDays, premium.HistoryRetentionDays)
+	assert.Less(t, basic.RateLimitPerMinute, premium.RateLimitPerMinute)
+}
+
+func TestAllowsFrequency(t *testing.T) {
+	basic := ForPlan(PlanBasic)
+	assert.True(t, basic.AllowsFrequency("weekly"))
+	assert.True(t, basic.AllowsFrequency("daily"))
+	assert.False(t, basic.AllowsFrequency("hourly"))
+	assert.False(t, basic.AllowsFrequency("minutely"))
+
+	premium := ForPlan(PlanPremium)
+	assert.True(t, premium.AllowsFrequency("hourly"))
+}
+
+func TestRetentionStart(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, now.AddDate(0, 0, -90), ForPlan(PlanBasic).RetentionStart(now))
+	assert.True(t, ForPlan(PlanEnterprise).RetentionStart(now).IsZero())
+}
+
+func TestRemaining(t *testing.T) {
+	assert.Equal(t, 15, Remaining(20, 5))
+	assert.Equal(t, 0, Remaining(20, 20))
+	assert.Equal(t, 0, Remaining(20, 25)) // 降级后已有数量可能超过上限
+	assert.Equal(t, -1, Remaining(Unlimited, 1000))
+}
+
+func TestMonthStart(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	// 本地时间已是 4 月 1 日，UTC 仍为 3 月
+	now := time.Date(2026, 4, 1, 6, 0, 0, 0, loc)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), MonthStart(now))
+}
@@ -0,0 +1,187 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// Usage 工作区当前的额度消耗
+type Usage struct {
+	TrackedProducts  int64
+	CompetitorGroups int64
+	ReportsThisMonth int64
+}
+
+// ForWorkspace 返回工作区所有者计划的权益，团队成员共享所有者的额度
+func ForWorkspace(ctx context.Context, db *gorm.DB, workspaceID string) (Entitlements, error) {
+	var plans []string
+	if err := db.WithContext(ctx).Table("workspaces w").
+		Joins("JOIN users u ON u.id = w.owner_id").
+		Where("w.id = ?", workspaceID).
+		Pluck("u.plan_type", &plans).Error; err != nil {
+		utils.LogError(ctx, "Failed to load workspace plan", "error", err, "workspace_id", workspaceID)
+		return Entitlements{}, errors.ErrInternalServer
+	}
+	if len(plans) == 0 {
+		return ForPlan(PlanBasic), nil
+	}
+	return ForPlan(plans[0]), nil
+}
+
+// CountTrackedProducts 统计工作区的追踪产品数 (包括已暂停的)
+func CountTrackedProducts(db *gorm.DB, workspaceID string) (int64, error) {
+	var count int64
+	err := db.Model(&models.TrackedProduct{}).Where("workspace_id = ?", workspaceID).Count(&count).Error
+	return count, err
+}
+
+// CountCompetitorGroups 统计工作区的竞品分析组数
+func CountCompetitorGroups(db *gorm.DB, workspaceID string) (int64, error) {
+	var count int64
+	err := db.Model(&models.CompetitorAnalysisGroup{}).Where("workspace_id = ?", workspaceID).Count(&count).Error
+	return count, err
+}
+
+// CountReports 统计工作区自 since 起生成的 LLM 报告数，失败的竞品报告不计入
+func CountReports(db *gorm.DB, workspaceID string, since time.Time) (int64, error) {
+	var competitorReports, optimizations int64
+	if err := db.Table("competitor_analysis_results r").
+		Joins("JOIN competitor_analysis_groups g ON g.id = r.analysis_group_id").
+		Where("g.workspace_id = ? AND r.started_at >= ? AND r.status <> ?", workspaceID, since, "failed").
+		Count(&competitorReports).Error; err != nil {
+		return 0, err
+	}
+	if err := db.Model(&models.OptimizationAnalysis{}).
+		Where("workspace_id = ? AND started_at >= ?", workspaceID, since).
+		Count(&optimizations).Error; err != nil {
+		return 0, err
+	}
+	return competitorReports + optimizations, nil
+}
+
+// LoadUsage 查询工作区当前的额度消耗
+func LoadUsage(ctx context.Context, db *gorm.DB, workspaceID string) (*Usage, error) {
+	db = db.WithContext(ctx)
+	var usage Usage
+	var err error
+	if usage.TrackedProducts, err = CountTrackedProducts(db, workspaceID); err == nil {
+		if usage.CompetitorGroups, err = CountCompetitorGroups(db, workspaceID); err == nil {
+			usage.ReportsThisMonth, err = CountReports(db, workspaceID, MonthStart(time.Now()))
+		}
+	}
+	if err != nil {
+		utils.LogError(ctx, "Failed to load workspace usage", "error", err, "workspace_id", workspaceID)
+		return nil, errors.ErrInternalServer
+	}
+	return &usage, nil
+}
+
+// RemainingTrackedProducts 返回工作区还可以追踪的产品数，不限时返回 -1
+func RemainingTrackedProducts(ctx context.Context, db *gorm.DB, workspaceID string) (int, error) {
+	_, remaining, err := trackedProductRoom(ctx, db, workspaceID)
+	return remaining, err
+}
+
+// CheckTrackedProducts 校验工作区追踪 adding 个新产品后不超过计划上限
+func CheckTrackedProducts(ctx context.Context, db *gorm.DB, workspaceID string, adding int) error {
+	entitlements, remaining, err := trackedProductRoom(ctx, db, workspaceID)
+	if err != nil {
+		return err
+	}
+	if remaining >= 0 && remaining < adding {
+		return errors.NewQuotaExceededError(fmt.Sprintf("Tracked product limit reached: the %s plan allows %d tracked products",
+			entitlements.Plan, entitlements.MaxTrackedProducts))
+	}
+	return nil
+}
+
+// trackedProductRoom 返回工作区的计划权益和剩余可追踪产品数
+func trackedProductRoom(ctx context.Context, db *gorm.DB, workspaceID string) (Entitlements, int, error) {
+	entitlements, err := ForWorkspace(ctx, db, workspaceID)
+	if err != nil {
+		return entitlements, 0, err
+	}
+	if entitlements.MaxTrackedProducts == Unlimited {
+		return entitlements, -1, nil
+	}
+	count, err := CountTrackedProducts(db.WithContext(ctx), workspaceID)
+	if err != nil {
+		utils.LogError(ctx, "Failed to count tracked products", "error", err, "workspace_id", workspaceID)
+		return entitlements, 0, errors.ErrInternalServer
+	}
+	return entitlements, Remaining(entitlements.MaxTrackedProducts, count), nil
+}
+
+// CheckFrequency 校验计划允许该追踪频率
+func CheckFrequency(ctx context.Context, db *gorm.DB, workspaceID, frequency string) error {
+	entitlements, err := ForWorkspace(ctx, db, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !entitlements.AllowsFrequency(frequency) {
+		return errors.NewQuotaExceededError(fmt.Sprintf("The %s plan allows refreshing at most %s",
+			entitlements.Plan, entitlements.MinRefreshFrequency))
+	}
+	return nil
+}
+
+// CheckCompetitorGroups 校验工作区还可以创建竞品分析组
+func CheckCompetitorGroups(ctx context.Context, db *gorm.DB, workspaceID string) error {
+	entitlements, err := ForWorkspace(ctx, db, workspaceID)
+	if err != nil {
+		return err
+	}
+	if entitlements.MaxCompetitorGroups == Unlimited {
+		return nil
+	}
+	count, err := CountCompetitorGroups(db.WithContext(ctx), workspaceID)
+	if err != nil {
+		utils.LogError(ctx, "Failed to count competitor groups", "error", err, "workspace_id", workspaceID)
+		return errors.ErrInternalServer
+	}
+	if Remaining(entitlements.MaxCompetitorGroups, count) == 0 {
+		return errors.NewQuotaExceededError(fmt.Sprintf("Competitor group limit reached: the %s plan allows %d analysis groups",
+			entitlements.Plan, entitlements.MaxCompetitorGroups))
+	}
+	return nil
+}
+
+// CheckReports 校验工作区本月还可以生成 LLM 报告
+func CheckReports(ctx context.Context, db *gorm.DB, workspaceID string) error {
+	entitlements, err := ForWorkspace(ctx, db, workspaceID)
+	if err != nil {
+		return err
+	}
+	if entitlements.ReportsPerMonth == Unlimited {
+		return nil
+	}
+	count, err := CountReports(db.WithContext(ctx), workspaceID, MonthStart(time.Now()))
+	if err != nil {
+		utils.LogError(ctx, "Failed to count monthly reports", "error", err, "workspace_id", workspaceID)
+		return errors.ErrInternalServer
+	}
+	if Remaining(entitlements.ReportsPerMonth, count) == 0 {
+		return errors.NewQuotaExceededError(fmt.Sprintf("Monthly report limit reached: the %s plan allows %d reports per month",
+			entitlements.Plan, entitlements.ReportsPerMonth))
+	}
+	return nil
+}
+
+// HistoryStart 将历史查询的开始时间限制在计划的保留期内
+func HistoryStart(ctx context.Context, db *gorm.DB, workspaceID string, from time.Time) (time.Time, error) {
+	entitlements, err := ForWorkspace(ctx, db, workspaceID)
+	if err != nil {
+		return from, err
+	}
+	if earliest := entitlements.RetentionStart(time.Now()); from.Before(earliest) {
+		return earliest, nil
+	}
+	return from, nil
+}
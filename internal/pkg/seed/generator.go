@@ -0,0 +1,382 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"amazonpilot/internal/pkg/estimation"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/tasks"
+
+	"gorm.io/datatypes"
+)
+
+// DataSource 生成数据的 data_source 标记，便于清理
+const DataSource = "seed"
+
+// 模拟参数 (按天计，记录间隔不同时按比例缩放)
+const (
+	priceVolatility  = 0.02 // 常规价格每日对数波动
+	priceReversion   = 0.1  // 向基准价格回归的速度
+	promotionRate    = 0.04 // 每天开始促销的概率
+	bsrElasticity    = 2.5  // BSR 对价格的弹性，降价 10% 约使 BSR 下降 23%
+	bsrNoise         = 0.08 // BSR 自相关噪声
+	reviewRate       = 0.015
+	ratingVolatility = 0.01
+	buyBoxChurnRate  = 0.08 // 每天 Buy Box 易主的概率
+)
+
+// 异常检测默认阈值 (价格 >10%，BSR >30%)，注入的异常幅度高于阈值
+const (
+	priceAnomalyMin = 0.15
+	priceAnomalyMax = 0.4
+	bsrAnomalyMin   = 1.5
+	bsrAnomalyMax   = 3.0
+)
+
+var (
+	categories   = []string{"Home & Kitchen", "Electronics", "Toys & Games", "Beauty & Personal Care", "Sports & Outdoors", "Pet Supplies"}
+	brands       = []string{"Northwind", "Acme", "Brightline", "Cobalt", "Evergreen", "Summit"}
+	nouns        = []string{"Water Bottle", "Wireless Earbuds", "Yoga Mat", "Desk Lamp", "Pet Brush", "Chef Knife", "Phone Stand", "Travel Mug"}
+	dealTypes    = []string{"lightning_deal", "limited_time_deal", "deal", ""} // 空字符串表示优惠券
+	amazonSeller = seller{Name: "Amazon.com", ID: "ATVPDKIKX0DER", FBA: true}
+)
+
+// Config 历史数据生成参数
+type Config struct {
+	Start       time.Time     // 第一条记录的时间
+	Points      int           // 每个产品的记录数
+	Interval    time.Duration // 记录间隔
+	AnomalyRate float64       // 每条记录注入异常的概率，0 表示不注入
+	Currency    string
+}
+
+// Profile 产品的基准参数
+type Profile struct {
+	ASIN        string
+	Title       string
+	Brand       string
+	Category    string
+	BasePrice   float64
+	BaseBSR     int
+	BaseRating  float64
+	BaseReviews int
+}
+
+// Anomaly 注入的异常，变化幅度与异常检测的计算方式一致
+type Anomaly struct {
+	EventType        string // price_change / bsr_change
+	RecordedAt       time.Time
+	OldValue         float64
+	NewValue         float64
+	ChangePercentage float64
+}
+
+// History 单个产品生成的历史数据，按时间升序
+type History struct {
+	Prices    []models.PriceHistory
+	Rankings  []models.RankingHistory
+	Reviews   []models.ReviewHistory
+	BuyBoxes  []models.BuyBoxHistory
+	Anomalies []Anomaly
+}
+
+type seller struct {
+	Name string
+	ID   string
+	FBA  bool
+}
+
+// Generator 基于随机游走的历史数据生成器，相同 seed 生成相同数据
+type Generator struct {
+	rng   *rand.Rand
+	sales *estimation.Model
+}
+
+// NewGenerator 创建生成器，sales 用于由 BSR 估算日销量和评论增长
+func NewGenerator(seed int64, sales *estimation.Model) *Generator {
+	if sales == nil {
+		sales = estimation.DefaultModel()
+	}
+	return &Generator{rng: rand.New(rand.NewSource(seed)), sales: sales}
+}
+
+// Profile 生成第 index 个产品的基准参数，ASIN 形如 B0SD000001
+func (g *Generator) Profile(index int) Profile {
+	brand := brands[g.rng.Intn(len(brands))]
+	noun := nouns[g.rng.Intn(len(nouns))]
+	return Profile{
+		ASIN:        fmt.Sprintf("B0SD%06d", index),
+		Title:       fmt.Sprintf("%s %s %d", brand, noun, index),
+		Brand:       brand,
+		Category:    categories[g.rng.Intn(len(categories))],
+		BasePrice:   round2(math.Exp(2.3 + g.rng.Float64()*2.7)), // 约 $10 - $150
+		BaseBSR:     int(math.Exp(5 + g.rng.Float64()*6)),        // 约 150 - 60000
+		BaseRating:  math.Round((3.6+g.rng.Float64()*1.3)*10) / 10,
+		BaseReviews: 20 + g.rng.Intn(5000),
+	}
+}
+
+// Generate 生成产品的价格、排名、评论和 Buy Box 历史
+func (g *Generator) Generate(productID string, profile Profile, cfg Config) History {
+	history := History{}
+	if cfg.Points <= 0 || cfg.Interval <= 0 {
+		return history
+	}
+	currency := cfg.Currency
+	if currency == "" {
+		currency = "USD"
+	}
+	dt := cfg.Interval.Hours() / 24
+	sellers := g.sellers()
+
+	logPrice := math.Log(profile.BasePrice)
+	bsrShock := 0.0
+	promoLeft := 0.0
+	discount := 0.0
+	dealType := ""
+	rating := profile.BaseRating
+	reviews := profile.BaseReviews
+	winner := 0
+	var lastPrice float64
+	var lastBSR int
+
+	for i := 0; i < cfg.Points; i++ {
+		at := cfg.Start.Add(time.Duration(i) * cfg.Interval)
+
+		// 常规价格：向基准价格回归的对数随机游走
+		logPrice += priceReversion*(math.Log(profile.BasePrice)-logPrice)*dt + priceVolatility*math.Sqrt(dt)*g.rng.NormFloat64()
+		regular := round2(math.Exp(logPrice))
+
+		// 促销：随机开始，持续 1-7 天，折扣 10%-30%
+		if promoLeft <= 0 && g.rng.Float64() < promotionRate*dt {
+			promoLeft = 1 + g.rng.Float64()*6
+			discount = float64(10 + g.rng.Intn(21))
+			dealType = dealTypes[g.rng.Intn(len(dealTypes))]
+		}
+		onSale := promoLeft > 0
+		price := regular
+		if onSale {
+			price = round2(regular * (1 - discount/100))
+			promoLeft -= dt
+		}
+
+		// BSR 与价格负相关，叠加自相关噪声
+		bsrShock = 0.7*bsrShock + bsrNoise*math.Sqrt(dt)*g.rng.NormFloat64()
+		bsr := int(math.Round(float64(profile.BaseBSR) * math.Pow(price/profile.BasePrice, bsrElasticity) * math.Exp(bsrShock)))
+
+		// 注入异常：价格或 BSR 单点突变
+		if i > 0 && cfg.AnomalyRate > 0 && g.rng.Float64() < cfg.AnomalyRate {
+			if g.rng.Intn(2) == 0 {
+				factor := priceAnomalyMin + g.rng.Float64()*(priceAnomalyMax-priceAnomalyMin)
+				if g.rng.Intn(2) == 0 {
+					factor = -factor
+				}
+				price = round2(lastPrice * (1 + factor))
+				history.Anomalies = append(history.Anomalies, newAnomaly("price_change", at, lastPrice, price))
+			} else {
+				factor := bsrAnomalyMin + g.rng.Float64()*(bsrAnomalyMax-bsrAnomalyMin)
+				if g.rng.Intn(2) == 0 {
+					bsr = int(float64(lastBSR) * factor)
+				} else {
+					bsr = int(math.Max(1, float64(lastBSR)/factor))
+				}
+				history.Anomalies = append(history.Anomalies, newAnomaly("bsr_change", at, float64(lastBSR), float64(bsr)))
+			}
+		}
+		if bsr < 1 {
+			bsr = 1
+		}
+
+		// 评论按估算销量增长，评分缓慢漂移
+		units := g.sales.UnitsPerDay(profile.Category, bsr)
+		reviews += g.poisson(units * reviewRate * dt)
+		rating += 0.05*(profile.BaseRating-rating)*dt + ratingVolatility*math.Sqrt(dt)*g.rng.NormFloat64()
+		rating = math.Max(1, math.Min(5, rating))
+		displayRating := math.Round(rating*10) / 10
+
+		// Buy Box 卖家随机易主，第三方卖家价格略有浮动
+		if g.rng.Float64() < buyBoxChurnRate*dt {
+			winner = g.rng.Intn(len(sellers))
+		}
+		current := sellers[winner]
+		winnerPrice := price
+		if current != amazonSeller {
+			winnerPrice = round2(price * (1 + (g.rng.Float64()-0.5)*0.04))
+		}
+
+		history.Prices = append(history.Prices, g.priceRecord(productID, currency, price, regular, winnerPrice, onSale, discount, dealType, at))
+		history.Rankings = append(history.Rankings, models.RankingHistory{
+			ProductID:      productID,
+			Category:       profile.Category,
+			BSRRank:        intPtr(bsr),
+			Rating:         floatPtr(displayRating),
+			ReviewCount:    reviews,
+			EstUnitsPerDay: floatPtr(units),
+			RecordedAt:     at,
+			DataSource:     DataSource,
+		})
+		history.Reviews = append(history.Reviews, models.ReviewHistory{
+			ProductID:     productID,
+			ReviewCount:   reviews,
+			AverageRating: floatPtr(displayRating),
+			RecordedAt:    at,
+			DataSource:    DataSource,
+		})
+		availability := "In Stock"
+		history.BuyBoxes = append(history.BuyBoxes, models.BuyBoxHistory{
+			ProductID:        productID,
+			WinnerSeller:     stringPtr(current.Name),
+			WinnerSellerID:   stringPtr(current.ID),
+			WinnerPrice:      floatPtr(winnerPrice),
+			Currency:         currency,
+			IsPrime:          current.FBA,
+			IsFBA:            current.FBA,
+			AvailabilityText: &availability,
+			RecordedAt:       at,
+			DataSource:       DataSource,
+		})
+
+		lastPrice = price
+		lastBSR = bsr
+	}
+	return history
+}
+
+// priceRecord 生成价格记录，促销期间记录原价、折扣和活动类型
+func (g *Generator) priceRecord(productID, currency string, price, regular, buyBoxPrice float64, onSale bool, discount float64, dealType string, at time.Time) models.PriceHistory {
+	record := models.PriceHistory{
+		ProductID:   productID,
+		Price:       price,
+		Currency:    currency,
+		BuyBoxPrice: floatPtr(buyBoxPrice),
+		IsOnSale:    onSale,
+		RecordedAt:  at,
+		DataSource:  DataSource,
+	}
+	if !onSale {
+		return record
+	}
+	record.DiscountPercentage = floatPtr(discount)
+	record.ListPrice = floatPtr(regular)
+	if dealType != "" {
+		record.DealType = stringPtr(dealType)
+	} else {
+		record.CouponText = stringPtr(fmt.Sprintf("Save %.0f%% with coupon", discount))
+	}
+	return record
+}
+
+// sellers 生成 Amazon 自营和 1-3 个第三方卖家
+func (g *Generator) sellers() []seller {
+	sellers := []seller{amazonSeller}
+	for i := 0; i < 1+g.rng.Intn(3); i++ {
+		id := make([]byte, 13)
+		for j := range id {
+			id[j] = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"[g.rng.Intn(36)]
+		}
+		sellers = append(sellers, seller{
+			Name: fmt.Sprintf("%s Trading %d", brands[g.rng.Intn(len(brands))], i+1),
+			ID:   "A" + string(id),
+			FBA:  g.rng.Intn(2) == 0,
+		})
+	}
+	return sellers
+}
+
+// poisson 按期望值生成泊松分布的整数，期望值较大时使用正态近似
+func (g *Generator) poisson(lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+	if lambda > 30 {
+		return int(math.Max(0, math.Round(lambda+math.Sqrt(lambda)*g.rng.NormFloat64())))
+	}
+	l := math.Exp(-lambda)
+	k, p := 0, 1.0
+	for {
+		p *= g.rng.Float64()
+		if p <= l {
+			return k
+		}
+		k++
+	}
+}
+
+func newAnomaly(eventType string, at time.Time, oldValue, newValue float64) Anomaly {
+	return Anomaly{
+		EventType:        eventType,
+		RecordedAt:       at,
+		OldValue:         oldValue,
+		NewValue:         newValue,
+		ChangePercentage: math.Round(math.Abs((newValue-oldValue)/oldValue)*10000) / 100,
+	}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
+
+// AnomalyEvents 将注入的异常转换为异常事件记录，metadata 标记 source=seed
+func (h History) AnomalyEvents(productID, asin string) []models.AnomalyEvent {
+	events := make([]models.AnomalyEvent, 0, len(h.Anomalies))
+	for _, anomaly := range h.Anomalies {
+		threshold, severity := 10.0, tasks.SeverityForPriceChange(anomaly.ChangePercentage)
+		if anomaly.EventType == "bsr_change" {
+			threshold, severity = 30.0, tasks.SeverityForBSRChange(anomaly.ChangePercentage)
+		}
+		events = append(events, models.AnomalyEvent{
+			ProductID:        productID,
+			ASIN:             asin,
+			EventType:        anomaly.EventType,
+			OldValue:         floatPtr(anomaly.OldValue),
+			NewValue:         floatPtr(anomaly.NewValue),
+			ChangePercentage: floatPtr(anomaly.ChangePercentage),
+			Threshold:        floatPtr(threshold),
+			Severity:         severity,
+			Metadata:         datatypes.JSON(`{"source":"seed"}`),
+			CreatedAt:        anomaly.RecordedAt,
+		})
+	}
+	return events
+}
+
+// LatestSnapshot 由最后一条记录生成产品最新快照，24小时变化与刷新任务的计算方式一致
+func (h History) LatestSnapshot() (models.LatestSnapshot, bool) {
+	n := len(h.Prices)
+	if n == 0 {
+		return models.LatestSnapshot{}, false
+	}
+	price, ranking := h.Prices[n-1], h.Rankings[n-1]
+	availability := models.AvailabilityHistory{
+		ProductID:        price.ProductID,
+		State:            estimation.AvailabilityInStock,
+		AvailabilityText: h.BuyBoxes[n-1].AvailabilityText,
+		RecordedAt:       price.RecordedAt,
+	}
+
+	var dayAgoPrice *models.PriceHistory
+	var dayAgoRanking *models.RankingHistory
+	dayAgo := price.RecordedAt.Add(-24 * time.Hour)
+	for i := n - 2; i >= 0; i-- {
+		if !h.Prices[i].RecordedAt.After(dayAgo) {
+			dayAgoPrice, dayAgoRanking = &h.Prices[i], &h.Rankings[i]
+			break
+		}
+	}
+	return tasks.BuildLatestSnapshot(price, ranking, availability, dayAgoPrice, dayAgoRanking), true
+}
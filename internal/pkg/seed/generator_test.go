@@ -0,0 +1,128 @@
+package seed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig() Config {
+	return Config{
+		Start:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Points:   180,
+		Interval: 24 * time.Hour,
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	a := NewGenerator(42, nil)
+	b := NewGenerator(42, nil)
+	profileA, profileB := a.Profile(1), b.Profile(1)
+	assert.Equal(t, profileA, profileB)
+	assert.Equal(t, "B0SD000001", profileA.ASIN)
+	assert.Len(t, profileA.ASIN, 10)
+
+	historyA := a.Generate("p1", profileA, testConfig())
+	historyB := b.Generate("p1", profileB, testConfig())
+	assert.Equal(t, historyA, historyB)
+
+	// 不同 seed 生成不同数据
+	other := NewGenerator(7, nil)
+	historyC := other.Generate("p1", profileA, testConfig())
+	assert.NotEqual(t, historyA.Prices, historyC.Prices)
+}
+
+func TestGenerateHistory(t *testing.T) {
+	g := NewGenerator(1, nil)
+	profile := g.Profile(3)
+	cfg := testConfig()
+	history := g.Generate("p1", profile, cfg)
+
+	assert.Len(t, history.Prices, cfg.Points)
+	assert.Len(t, history.Rankings, cfg.Points)
+	assert.Len(t, history.Reviews, cfg.Points)
+	assert.Len(t, history.BuyBoxes, cfg.Points)
+	assert.Empty(t, history.Anomalies)
+
+	onSale := 0
+	for i := range history.Prices {
+		price := history.Prices[i]
+		assert.Equal(t, cfg.Start.Add(time.Duration(i)*cfg.Interval), price.RecordedAt)
+		assert.Equal(t, DataSource, price.DataSource)
+		assert.Greater(t, price.Price, 0.0)
+		if price.IsOnSale {
+			onSale++
+			assert.NotNil(t, price.ListPrice)
+			assert.Greater(t, *price.ListPrice, price.Price)
+			assert.True(t, price.DealType != nil || price.CouponText != nil)
+		}
+
+		assert.GreaterOrEqual(t, *history.Rankings[i].BSRRank, 1)
+		assert.GreaterOrEqual(t, *history.Rankings[i].Rating, 1.0)
+		assert.LessOrEqual(t, *history.Rankings[i].Rating, 5.0)
+		if i > 0 {
+			// 评论数只增不减
+			assert.GreaterOrEqual(t, history.Reviews[i].ReviewCount, history.Reviews[i-1].ReviewCount)
+		}
+		assert.NotNil(t, history.BuyBoxes[i].WinnerSeller)
+	}
+	// 半年内应出现促销
+	assert.Greater(t, onSale, 0)
+}
+
+func TestGenerateInjectedAnomalies(t *testing.T) {
+	g := NewGenerator(5, nil)
+	cfg := testConfig()
+	cfg.AnomalyRate = 0.1
+	history := g.Generate("p1", g.Profile(1), cfg)
+
+	assert.NotEmpty(t, history.Anomalies)
+	for _, anomaly := range history.Anomalies {
+		// 注入的异常幅度高于默认检测阈值
+		switch anomaly.EventType {
+		case "price_change":
+			assert.GreaterOrEqual(t, anomaly.ChangePercentage, 10.0)
+		case "bsr_change":
+			assert.GreaterOrEqual(t, anomaly.ChangePercentage, 30.0)
+		default:
+			t.Fatalf("unexpected anomaly type %s", anomaly.EventType)
+		}
+	}
+}
+
+func TestGenerateEmptyConfig(t *testing.T) {
+	g := NewGenerator(1, nil)
+	history := g.Generate("p1", g.Profile(1), Config{})
+	assert.Empty(t, history.Prices)
+}
+
+func TestHistoryAnomalyEventsAndSnapshot(t *testing.T) {
+	g := NewGenerator(5, nil)
+	cfg := testConfig()
+	cfg.AnomalyRate = 0.1
+	profile := g.Profile(1)
+	history := g.Generate("p1", profile, cfg)
+
+	events := history.AnomalyEvents("p1", profile.ASIN)
+	assert.Len(t, events, len(history.Anomalies))
+	for _, event := range events {
+		assert.Equal(t, profile.ASIN, event.ASIN)
+		assert.JSONEq(t, `{"source":"seed"}`, string(event.Metadata))
+		assert.NotEqual(t, "info", event.Severity)
+	}
+
+	snapshot, ok := history.LatestSnapshot()
+	assert.True(t, ok)
+	last := len(history.Prices) - 1
+	assert.Equal(t, history.Prices[last].Price, snapshot.Price)
+	assert.Equal(t, history.Prices[last].RecordedAt, snapshot.RecordedAt)
+	assert.Equal(t, "in_stock", snapshot.AvailabilityState)
+	// 按日记录时与前一条记录比较24小时变化
+	assert.NotNil(t, snapshot.PriceChange24h)
+	assert.NotNil(t, snapshot.ReviewCountChange24h)
+	assert.Equal(t, history.Reviews[last].ReviewCount-history.Reviews[last-1].ReviewCount, *snapshot.ReviewCountChange24h)
+
+	_, ok = History{}.LatestSnapshot()
+	assert.False(t, ok)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -13,26 +14,35 @@ import (
 	"amazonpilot/internal/pkg/cache"
 	"amazonpilot/internal/pkg/constants"
 	"amazonpilot/internal/pkg/database"
+	"amazonpilot/internal/pkg/estimation"
+	"amazonpilot/internal/pkg/events"
+	"amazonpilot/internal/pkg/fx"
 	"amazonpilot/internal/pkg/llm"
 	"amazonpilot/internal/pkg/logger"
 	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/workspace"
 
 	"github.com/hibiken/asynq"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const (
 	TypeRefreshProductData = "refresh_product_data"
 	TypeGenerateReport     = "generate_competitor_report"
+	TypeBulkImport         = "bulk_import_tracking"
+	TypeHistoryExport      = "history_export"
 )
 
 type RefreshProductDataPayload struct {
-	ProductID   string `json:"product_id"`
-	TrackedID   string `json:"tracked_id"`
-	ASIN        string `json:"asin"`
-	UserID      string `json:"user_id"`
-	RequestedAt string `json:"requested_at"`
+	ProductID    string `json:"product_id"`
+	TrackedID    string `json:"tracked_id"`
+	ASIN         string `json:"asin"`
+	UserID       string `json:"user_id"`
+	RequestedAt  string `json:"requested_at"`
+	InitialFetch bool   `json:"initial_fetch,omitempty"` // 添加追踪后的初始数据获取
 }
 
 type GenerateReportPayload struct {
@@ -40,18 +50,27 @@ type GenerateReportPayload struct {
 	UserID      string `json:"user_id"`
 	TaskID      string `json:"task_id"`
 	Force       bool   `json:"force"`
+	Currency    string `json:"currency,omitempty"` // 报告展示货币，为空时使用默认货币
 	RequestedAt string `json:"requested_at"`
 }
 
 type ApifyTaskProcessor struct {
-	db          *gorm.DB
-	redisClient *redis.Client
-	apifyClient *apify.Client
-	asynqClient *asynq.Client
-	logger      *logger.ServiceLogger
+	db           *gorm.DB
+	redisClient  *redis.Client
+	productCache *cache.Store[cache.ProductData]
+	eventBus     *events.Bus
+	apifyClient  *apify.Client
+	asynqClient  *asynq.Client
+	fxConverter  *fx.Converter
+	salesModel   *estimation.Model
+	logger       *logger.ServiceLogger
+
+	// 历史数据导出
+	exportDir       string
+	exportRetention time.Duration
 }
 
-func NewApifyTaskProcessor(dsn string, apifyToken string, redisAddr string) *ApifyTaskProcessor {
+func NewApifyTaskProcessor(dsn string, apifyToken string, redisAddr string, fxConverter *fx.Converter) *ApifyTaskProcessor {
 	// 连接数据库
 	db, err := database.NewConnectionWithDSN(dsn, &database.Config{
 		MaxIdleConns:    10,
@@ -72,18 +91,44 @@ func NewApifyTaskProcessor(dsn string, apifyToken string, redisAddr string) *Api
 	}
 	asynqClient := asynq.NewClient(redisOpt)
 
+	// 初始化Redis客户端 (刷新后写入产品数据缓存、发布用户事件)
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+		DB:   0,
+	})
+
 	serviceLogger := logger.GlobalLogger(constants.ServiceWorker)
 
 	return &ApifyTaskProcessor{
-		db:          db,
-		apifyClient: apifyClient,
-		asynqClient: asynqClient,
-		logger:      serviceLogger,
+		db:           db,
+		redisClient:  redisClient,
+		productCache: cache.NewProductStore(redisClient),
+		eventBus:     events.NewBus(redisClient),
+		apifyClient:  apifyClient,
+		asynqClient:  asynqClient,
+		fxConverter:  fxConverter,
+		salesModel:   estimation.DefaultModel(),
+		logger:       serviceLogger,
+
+		exportDir:       filepath.Join(os.TempDir(), "amazonpilot", "exports"),
+		exportRetention: 72 * time.Hour,
+	}
+}
+
+// SetOffersActor 配置抓取全部卖家报价的 actor，为空时只记录 Buy Box 卖家
+func (p *ApifyTaskProcessor) SetOffersActor(actor string) {
+	p.apifyClient.SetOffersActor(actor)
+}
+
+// SetSalesModel 配置 BSR -> 销量估算模型，未配置时使用内置曲线
+func (p *ApifyTaskProcessor) SetSalesModel(model *estimation.Model) {
+	if model != nil {
+		p.salesModel = model
 	}
 }
 
 // HandleRefreshProductData 处理产品数据刷新任务
-func (processor *ApifyTaskProcessor) HandleRefreshProductData(ctx context.Context, t *asynq.Task) error {
+func (processor *ApifyTaskProcessor) HandleRefreshProductData(ctx context.Context, t *asynq.Task) (err error) {
 	var payload RefreshProductDataPayload
 	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
 		return fmt.Errorf("failed to unmarshal payload: %w", err)
@@ -94,6 +139,18 @@ func (processor *ApifyTaskProcessor) HandleRefreshProductData(ctx context.Contex
 		"task_id", t.Type(),
 	)
 
+	// 通知追踪记录所在工作区的成员，任务失败时一并推送失败事件
+	subscribers := processor.workspaceMemberIDs(ctx, &models.TrackedProduct{}, payload.TrackedID, payload.UserID)
+	processor.publishEvent(ctx, events.TypeRefreshStarted, subscribers, payload.ProductID, map[string]interface{}{
+		"tracked_id": payload.TrackedID,
+		"asin":       payload.ASIN,
+	})
+	defer func() {
+		if err != nil {
+			processor.publishRefreshFailed(ctx, payload, subscribers, err)
+		}
+	}()
+
 	// 直接使用apify.Client调用产品详情actor
 	productData, err := processor.apifyClient.FetchProductData(ctx, []string{payload.ASIN}, 60*time.Second)
 	if err != nil {
@@ -114,6 +171,16 @@ func (processor *ApifyTaskProcessor) HandleRefreshProductData(ctx context.Contex
 	// 直接使用返回的数据，因为apify client已经解析过了
 	data := productData[0]
 
+	// 抓取全部卖家报价（失败时退回到只记录 Buy Box 卖家）
+	if len(data.Offers) == 0 && processor.apifyClient.OffersEnabled() {
+		offers, err := processor.apifyClient.FetchOffers(ctx, payload.ASIN, 60*time.Second)
+		if err != nil {
+			processor.logger.Error(ctx, "Failed to fetch offers, falling back to buy box seller", "asin", payload.ASIN, "error", err)
+		} else {
+			data.Offers = offers
+		}
+	}
+
 	// 记录获取到的数据
 	processor.logger.LogBusinessOperation(ctx, "apify_data_received", "apify_worker", payload.ProductID, "success",
 		"asin", data.ASIN,
@@ -133,6 +200,13 @@ func (processor *ApifyTaskProcessor) HandleRefreshProductData(ctx context.Contex
 		}
 	}()
 
+	// 在覆盖产品内容之前，与上一版本Listing内容比较并保存快照
+	listingDiffs, snapshotID, err := processor.snapshotListingContent(tx, payload.ProductID, &data, now)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to snapshot listing content: %w", err)
+	}
+
 	// 使用标准化的映射函数处理数据
 	updates := MapApifyDataToProduct(&data, nil)
 	updates["last_updated_at"] = now
@@ -142,14 +216,24 @@ func (processor *ApifyTaskProcessor) HandleRefreshProductData(ctx context.Contex
 		return fmt.Errorf("failed to update product: %w", err)
 	}
 
-	// 保存价格历史
+	// 保存价格历史 (包含折扣、优惠券和秒杀活动)
+	deal := ExtractDealInfo(&data)
 	priceHistory := models.PriceHistory{
-		ProductID:   payload.ProductID,
-		Price:       data.Price,
-		Currency:    data.Currency,
-		BuyBoxPrice: data.BuyBoxPrice, // 使用实际的Buy Box价格，可能为nil
-		RecordedAt:  now,
-		DataSource:  "apify",
+		ProductID:          payload.ProductID,
+		Price:              data.Price,
+		Currency:           data.Currency,
+		BuyBoxPrice:        data.BuyBoxPrice, // 使用实际的Buy Box价格，可能为nil
+		IsOnSale:           deal.IsOnSale,
+		DiscountPercentage: deal.DiscountPercentage,
+		ListPrice:          deal.ListPrice,
+		RecordedAt:         now,
+		DataSource:         "apify",
+	}
+	if deal.DealType != "" {
+		priceHistory.DealType = &deal.DealType
+	}
+	if deal.CouponText != "" {
+		priceHistory.CouponText = &deal.CouponText
 	}
 
 	if err := tx.Create(&priceHistory).Error; err != nil {
@@ -167,12 +251,30 @@ func (processor *ApifyTaskProcessor) HandleRefreshProductData(ctx context.Contex
 		RecordedAt:  now,
 		DataSource:  "apify",
 	}
+	if data.BSR > 0 {
+		estUnits := processor.salesModel.UnitsPerDay(data.BSRCategory, data.BSR)
+		rankingHistory.EstUnitsPerDay = &estUnits
+	}
 
 	if err := tx.Create(&rankingHistory).Error; err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to save ranking history: %w", err)
 	}
 
+	// 保存根类目和各小类目排名
+	categoryRanks := ParseCategoryRanks(&data)
+	for i := range categoryRanks {
+		categoryRanks[i].RankingHistoryID = rankingHistory.ID
+		categoryRanks[i].ProductID = payload.ProductID
+		categoryRanks[i].RecordedAt = now
+	}
+	if len(categoryRanks) > 0 {
+		if err := tx.Create(&categoryRanks).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to save category ranks: %w", err)
+		}
+	}
+
 	// 保存评论历史
 	reviewHistory := models.ReviewHistory{
 		ProductID:     payload.ProductID,
@@ -201,11 +303,49 @@ func (processor *ApifyTaskProcessor) HandleRefreshProductData(ctx context.Contex
 		DataSource:       "apify",
 	}
 
+	if data.SellerID != "" {
+		buyboxHistory.WinnerSellerID = &data.SellerID
+	}
+
 	if err := tx.Create(&buyboxHistory).Error; err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to save buybox history: %w", err)
 	}
 
+	// 保存库存状态历史
+	state, stockQuantity := estimation.ParseAvailability(data.Availability)
+	availabilityHistory := models.AvailabilityHistory{
+		ProductID:     payload.ProductID,
+		State:         state,
+		StockQuantity: stockQuantity,
+		RecordedAt:    now,
+		DataSource:    "apify",
+	}
+	if data.Availability != "" {
+		availabilityText := estimation.TruncateAvailabilityText(data.Availability)
+		availabilityHistory.AvailabilityText = &availabilityText
+	}
+
+	if err := tx.Create(&availabilityHistory).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to save availability history: %w", err)
+	}
+
+	// 保存卖家报价快照
+	offerHistory := BuildOfferHistory(payload.ProductID, &data, now)
+	if len(offerHistory) > 0 {
+		if err := tx.Create(&offerHistory).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to save offer history: %w", err)
+		}
+	}
+
+	// 更新产品最新快照，与历史记录在同一事务中提交
+	if err := upsertLatestSnapshot(tx, priceHistory, rankingHistory, availabilityHistory, now); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to save latest snapshot: %w", err)
+	}
+
 	// 获取历史数据用于异常检测 (排除刚插入的记录)
 	var lastPrice models.PriceHistory
 	processor.db.Where("product_id = ? AND id != ?", payload.ProductID, priceHistory.ID).
@@ -227,10 +367,21 @@ func (processor *ApifyTaskProcessor) HandleRefreshProductData(ctx context.Contex
 		Order("recorded_at DESC").
 		First(&lastBuybox)
 
-	// 更新追踪记录的检查时间 (在事务提交前)
-	if err := tx.Table("tracked_products").Where("id = ?", payload.TrackedID).Updates(map[string]interface{}{
+	// 与最近一次已知状态比较，抓取失败产生的 unknown 记录不打断缺货/补货判断
+	var lastAvailability models.AvailabilityHistory
+	processor.db.Where("product_id = ? AND id != ? AND state <> ?", payload.ProductID, availabilityHistory.ID, estimation.AvailabilityUnknown).
+		Order("recorded_at DESC").
+		First(&lastAvailability)
+
+	// 更新追踪记录的检查时间，并按追踪频率推迟下次定时刷新 (在事务提交前)
+	trackingUpdates := map[string]interface{}{
 		"last_checked_at": now,
-	}).Error; err != nil {
+	}
+	var tracking models.TrackedProduct
+	if err := tx.Select("is_active", "tracking_frequency").Where("id = ?", payload.TrackedID).First(&tracking).Error; err == nil && tracking.IsActive {
+		trackingUpdates["next_check_at"] = NextCheckTime(tracking.TrackingFrequency, now)
+	}
+	if err := tx.Table("tracked_products").Where("id = ?", payload.TrackedID).Updates(trackingUpdates).Error; err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to update tracked product: %w", err)
 	}
@@ -242,9 +393,16 @@ func (processor *ApifyTaskProcessor) HandleRefreshProductData(ctx context.Contex
 
 	// 🚀 数据保存成功，现在进行异常检测
 	processor.detectAndRecordAnomalies(ctx, payload, data, lastPrice, lastRanking, lastReview, lastBuybox, priceHistory.ID, rankingHistory.ID, reviewHistory.ID, buyboxHistory.ID, now)
+	processor.recordListingChange(ctx, payload, listingDiffs, snapshotID, now)
+	processor.detectHijackers(ctx, payload, offerHistory, now)
+	processor.detectMAPViolations(ctx, payload, &data, offerHistory, now)
+	processor.detectAvailabilityChange(ctx, payload, lastAvailability, availabilityHistory, now)
+
+	// 发现变体子ASIN，并在用户开启时自动追踪
+	processor.discoverVariations(ctx, payload, data)
 
-	// 清理相关缓存，确保前端获取最新数据
-	processor.invalidateProductCache(ctx, payload.ASIN, payload.ProductID, payload.UserID)
+	// 将最新数据写入产品缓存，确保前端获取最新数据
+	processor.writeProductCache(ctx, payload.ASIN, payload.ProductID, payload.UserID)
 
 	processor.logger.LogBusinessOperation(ctx, "refresh_task_completed", "apify_worker", payload.ProductID, "success",
 		"asin", payload.ASIN,
@@ -253,10 +411,40 @@ func (processor *ApifyTaskProcessor) HandleRefreshProductData(ctx context.Contex
 		"rating", data.Rating,
 		"review_count", data.ReviewCount,
 		"seller", data.Seller,
+		"offers", len(offerHistory),
 		"prime", data.Prime,
 		"availability", data.Availability,
 	)
 
+	// 写入任务结果，随任务保留供刷新状态和最近刷新记录查询
+	result, _ := json.Marshal(RefreshProductDataResult{
+		Price:        data.Price,
+		Currency:     data.Currency,
+		BSR:          data.BSR,
+		Rating:       data.Rating,
+		ReviewCount:  data.ReviewCount,
+		Availability: availabilityHistory.State,
+		Offers:       len(offerHistory),
+		UpdatedAt:    now,
+	})
+	if w := t.ResultWriter(); w != nil {
+		if _, err := w.Write(result); err != nil {
+			processor.logger.Error(ctx, "Failed to write refresh task result", "product_id", payload.ProductID, "error", err)
+		}
+	}
+
+	processor.publishEvent(ctx, events.TypeRefreshCompleted, subscribers, payload.ProductID, map[string]interface{}{
+		"tracked_id":   payload.TrackedID,
+		"asin":         payload.ASIN,
+		"price":        data.Price,
+		"currency":     data.Currency,
+		"bsr":          data.BSR,
+		"rating":       data.Rating,
+		"review_count": data.ReviewCount,
+		"availability": availabilityHistory.State,
+		"updated_at":   now,
+	})
+
 	return nil
 }
 
@@ -284,7 +472,7 @@ func (p *ApifyTaskProcessor) detectAndRecordAnomalies(ctx context.Context, paylo
 
 		if priceChangePercentage > threshold {
 			thresholdPtr := &threshold
-			severity := getSeverityForPriceChange(priceChangePercentage)
+			severity := SeverityForPriceChange(priceChangePercentage)
 			anomalyEvent := models.AnomalyEvent{
 				ProductID:        payload.ProductID,
 				ASIN:             payload.ASIN,
@@ -312,7 +500,7 @@ func (p *ApifyTaskProcessor) detectAndRecordAnomalies(ctx context.Context, paylo
 
 		if bsrChangePercentage > threshold {
 			thresholdPtr := &threshold
-			severity := getSeverityForBSRChange(bsrChangePercentage)
+			severity := SeverityForBSRChange(bsrChangePercentage)
 			anomalyEvent := models.AnomalyEvent{
 				ProductID:        payload.ProductID,
 				ASIN:             payload.ASIN,
@@ -328,7 +516,41 @@ func (p *ApifyTaskProcessor) detectAndRecordAnomalies(ctx context.Context, paylo
 		}
 	}
 
-	// 3. 批量保存异常事件
+	// 3. 小类目BSR异常检测 (与根类目使用相同的阈值)
+	if lastRanking.ID != "" {
+		threshold := trackedProduct.BSRChangeThreshold
+		if threshold == 0 {
+			threshold = 30.0 // 默认阈值
+		}
+
+		var previousRanks, currentRanks []models.CategoryRank
+		p.db.Where("ranking_history_id = ?", lastRanking.ID).Find(&previousRanks)
+		p.db.Where("ranking_history_id = ?", newRankingID).Find(&currentRanks)
+
+		for _, change := range FindSubCategoryRankChanges(previousRanks, currentRanks, threshold) {
+			oldRank := float64(change.OldRank)
+			newRank := float64(change.NewRank)
+			changePercentage := change.ChangePercentage
+			thresholdValue := threshold
+			metadata, _ := json.Marshal(map[string]interface{}{
+				"category": change.Category,
+			})
+			anomalyEvents = append(anomalyEvents, models.AnomalyEvent{
+				ProductID:        payload.ProductID,
+				ASIN:             payload.ASIN,
+				EventType:        "subcategory_bsr_change",
+				OldValue:         &oldRank,
+				NewValue:         &newRank,
+				ChangePercentage: &changePercentage,
+				Threshold:        &thresholdValue,
+				Severity:         SeverityForBSRChange(changePercentage),
+				Metadata:         metadata,
+				CreatedAt:        now,
+			})
+		}
+	}
+
+	// 4. 批量保存异常事件
 	if len(anomalyEvents) > 0 {
 		if err := p.db.Create(&anomalyEvents).Error; err != nil {
 			p.logger.LogBusinessOperation(ctx, "anomaly_record_failed", "apify_worker", payload.ProductID, "failed",
@@ -341,12 +563,458 @@ func (p *ApifyTaskProcessor) detectAndRecordAnomalies(ctx context.Context, paylo
 				"events_count", len(anomalyEvents),
 				"events", getEventSummary(anomalyEvents),
 			)
+			p.publishAnomalies(ctx, payload.ProductID, anomalyEvents)
 		}
 	}
 }
 
-// getSeverityForPriceChange 根据价格变化百分比确定严重程度
-func getSeverityForPriceChange(percentage float64) string {
+// snapshotListingContent 比较本次抓取的Listing内容与上一版本，有变化时写入新快照
+// 产品尚无快照时以当前产品记录为基线；返回字段差异和新快照ID
+func (p *ApifyTaskProcessor) snapshotListingContent(tx *gorm.DB, productID string, data *apify.ProductData, now time.Time) ([]estimation.ListingFieldDiff, string, error) {
+	current := estimation.ListingContentFromApify(data)
+	if current.IsEmpty() {
+		return nil, "", nil
+	}
+
+	var previous estimation.ListingContent
+	var lastSnapshot models.ListingSnapshot
+	err := tx.Where("product_id = ?", productID).Order("recorded_at DESC").First(&lastSnapshot).Error
+	if err == nil {
+		previous = estimation.ListingContentFromSnapshot(&lastSnapshot)
+	} else if err == gorm.ErrRecordNotFound {
+		var product models.Product
+		if err := tx.Where("id = ?", productID).First(&product).Error; err != nil {
+			return nil, "", err
+		}
+		previous = estimation.ListingContentFromProduct(&product)
+	} else {
+		return nil, "", err
+	}
+
+	// 首次抓取只建立基线，不产生变更
+	diffs := []estimation.ListingFieldDiff{}
+	if !previous.IsEmpty() {
+		diffs = estimation.DiffListingContent(previous, current)
+	}
+	if lastSnapshot.ID != "" && len(diffs) == 0 {
+		return nil, "", nil
+	}
+
+	snapshot := current.FillMissing(previous).ToSnapshot(productID)
+	snapshot.RecordedAt = now
+	snapshot.DataSource = "apify"
+	if err := tx.Create(&snapshot).Error; err != nil {
+		return nil, "", err
+	}
+
+	return diffs, snapshot.ID, nil
+}
+
+// recordListingChange 记录 listing_changed 异常事件，Metadata 中包含字段级差异
+func (p *ApifyTaskProcessor) recordListingChange(ctx context.Context, payload RefreshProductDataPayload, diffs []estimation.ListingFieldDiff, snapshotID string, now time.Time) {
+	if len(diffs) == 0 {
+		return
+	}
+
+	fields := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		fields = append(fields, diff.Field)
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"snapshot_id":    snapshotID,
+		"changed_fields": fields,
+		"changes":        diffs,
+	})
+
+	event := models.AnomalyEvent{
+		ProductID: payload.ProductID,
+		ASIN:      payload.ASIN,
+		EventType: "listing_changed",
+		Severity:  getSeverityForListingChange(diffs),
+		Metadata:  metadata,
+		CreatedAt: now,
+	}
+	if err := p.db.Create(&event).Error; err != nil {
+		p.logger.LogBusinessOperation(ctx, "anomaly_record_failed", "apify_worker", payload.ProductID, "failed",
+			"error", err.Error(),
+			"event_type", event.EventType,
+		)
+		return
+	}
+
+	p.logger.LogBusinessOperation(ctx, "anomaly_detected", "apify_worker", payload.ProductID, "success",
+		"asin", payload.ASIN,
+		"events_count", 1,
+		"events", "listing_changed:"+strings.Join(fields, "|"),
+	)
+	p.publishAnomalies(ctx, payload.ProductID, []models.AnomalyEvent{event})
+}
+
+// detectHijackers 将本次报价与用户的自有卖家及授权卖家白名单比较，记录 hijacker_detected 异常
+// 同一跟卖卖家24小时内只记录一次，除非其在此期间夺得了 Buy Box
+func (p *ApifyTaskProcessor) detectHijackers(ctx context.Context, payload RefreshProductDataPayload, offers []models.OfferHistory, now time.Time) {
+	if len(offers) == 0 {
+		return
+	}
+
+	var trackedProduct models.TrackedProduct
+	if err := p.db.Where("id = ?", payload.TrackedID).First(&trackedProduct).Error; err != nil {
+		p.logger.Error(ctx, "Failed to load tracked product for hijacker detection", "tracked_id", payload.TrackedID, "error", err)
+		return
+	}
+
+	var accounts []models.SellerAccount
+	p.db.Where("workspace_id = ?", trackedProduct.WorkspaceID).Find(&accounts)
+
+	unauthorized := FindUnauthorizedOffers(offers, AuthorizedSellers(accounts, &trackedProduct))
+	if len(unauthorized) == 0 {
+		return
+	}
+
+	// 当前工作区最近24小时已记录的跟卖卖家
+	var recentEvents []models.AnomalyEvent
+	p.db.Where("product_id = ? AND workspace_id = ? AND event_type = ? AND created_at >= ?",
+		payload.ProductID, trackedProduct.WorkspaceID, "hijacker_detected", now.Add(-24*time.Hour)).
+		Find(&recentEvents)
+	reported := map[string]bool{}
+	for _, event := range recentEvents {
+		var metadata struct {
+			SellerKey      string `json:"seller_key"`
+			IsBuyBoxWinner bool   `json:"is_buy_box_winner"`
+		}
+		if json.Unmarshal(event.Metadata, &metadata) == nil {
+			// 记录该卖家是否已经以 Buy Box 赢家身份被报告过
+			reported[metadata.SellerKey] = reported[metadata.SellerKey] || metadata.IsBuyBoxWinner
+		}
+	}
+
+	events := []models.AnomalyEvent{}
+	for _, offer := range unauthorized {
+		key := hijackerKey(offer)
+		if wonBuyBox, ok := reported[key]; ok && (wonBuyBox || !offer.IsBuyBoxWinner) {
+			continue
+		}
+
+		severity := "warning"
+		if offer.IsBuyBoxWinner {
+			severity = "critical"
+		}
+
+		metadata, _ := json.Marshal(map[string]interface{}{
+			"seller_key":        key,
+			"seller_name":       offer.SellerName,
+			"seller_id":         offer.SellerID,
+			"price":             offer.Price,
+			"shipping_price":    offer.ShippingPrice,
+			"currency":          offer.Currency,
+			"condition":         offer.Condition,
+			"is_fba":            offer.IsFBA,
+			"is_prime":          offer.IsPrime,
+			"is_buy_box_winner": offer.IsBuyBoxWinner,
+			"tracked_id":        payload.TrackedID,
+		})
+
+		price := offer.Price
+		events = append(events, models.AnomalyEvent{
+			ProductID:   payload.ProductID,
+			WorkspaceID: &trackedProduct.WorkspaceID,
+			ASIN:        payload.ASIN,
+			EventType:   "hijacker_detected",
+			NewValue:    &price,
+			Severity:    severity,
+			Metadata:    metadata,
+			CreatedAt:   now,
+		})
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	if err := p.db.Create(&events).Error; err != nil {
+		p.logger.LogBusinessOperation(ctx, "anomaly_record_failed", "apify_worker", payload.ProductID, "failed",
+			"error", err.Error(),
+			"event_type", "hijacker_detected",
+		)
+		return
+	}
+
+	p.logger.LogBusinessOperation(ctx, "anomaly_detected", "apify_worker", payload.ProductID, "success",
+		"asin", payload.ASIN,
+		"events_count", len(events),
+		"events", getEventSummary(events),
+	)
+	p.publishAnomalies(ctx, payload.ProductID, events)
+}
+
+// detectMAPViolations 检查本次报价是否低于追踪设置中的 MAP，每条违规报价记录一个 map_violation 事件作为证据
+func (p *ApifyTaskProcessor) detectMAPViolations(ctx context.Context, payload RefreshProductDataPayload, data *apify.ProductData, offers []models.OfferHistory, now time.Time) {
+	var trackedProduct models.TrackedProduct
+	if err := p.db.Where("id = ?", payload.TrackedID).First(&trackedProduct).Error; err != nil {
+		p.logger.Error(ctx, "Failed to load tracked product for MAP monitoring", "tracked_id", payload.TrackedID, "error", err)
+		return
+	}
+	if trackedProduct.MAPPrice == nil {
+		return
+	}
+
+	violations := FindMAPViolations(offers, data, *trackedProduct.MAPPrice)
+	if len(violations) == 0 {
+		return
+	}
+
+	events := make([]models.AnomalyEvent, 0, len(violations))
+	for _, violation := range violations {
+		offer := violation.Offer
+		metadata, _ := json.Marshal(map[string]interface{}{
+			"seller_key":        hijackerKey(offer),
+			"seller_name":       offer.SellerName,
+			"seller_id":         offer.SellerID,
+			"price":             offer.Price,
+			"currency":          offer.Currency,
+			"map_price":         violation.MAPPrice,
+			"is_buy_box_winner": offer.IsBuyBoxWinner,
+			"observed_at":       offer.RecordedAt.Format(time.RFC3339),
+			"tracked_id":        payload.TrackedID,
+		})
+
+		mapPrice := violation.MAPPrice
+		price := offer.Price
+		discount := violation.DiscountPercent
+		events = append(events, models.AnomalyEvent{
+			ProductID:        payload.ProductID,
+			WorkspaceID:      &trackedProduct.WorkspaceID,
+			ASIN:             payload.ASIN,
+			EventType:        "map_violation",
+			OldValue:         &mapPrice,
+			NewValue:         &price,
+			ChangePercentage: &discount,
+			Threshold:        &mapPrice,
+			Severity:         getSeverityForMAPViolation(discount),
+			Metadata:         metadata,
+			CreatedAt:        now,
+		})
+	}
+
+	if err := p.db.Create(&events).Error; err != nil {
+		p.logger.LogBusinessOperation(ctx, "anomaly_record_failed", "apify_worker", payload.ProductID, "failed",
+			"error", err.Error(),
+			"event_type", "map_violation",
+		)
+		return
+	}
+
+	p.logger.LogBusinessOperation(ctx, "anomaly_detected", "apify_worker", payload.ProductID, "success",
+		"asin", payload.ASIN,
+		"events_count", len(events),
+		"events", getEventSummary(events),
+	)
+	p.publishAnomalies(ctx, payload.ProductID, events)
+}
+
+// detectAvailabilityChange 库存状态在可购买与不可购买之间切换时记录 out_of_stock / back_in_stock 异常
+// 竞品断货意味着机会，因此对竞品同样记录，并在 Metadata 中标记 is_competitor
+func (p *ApifyTaskProcessor) detectAvailabilityChange(ctx context.Context, payload RefreshProductDataPayload, previous, current models.AvailabilityHistory, now time.Time) {
+	eventType := estimation.AvailabilityTransition(previous.State, current.State)
+	if eventType == "" {
+		return
+	}
+
+	// 该产品是否为所在工作区竞品分析组中的竞品
+	var competitorCount int64
+	p.db.Table("competitor_products cp").
+		Joins("INNER JOIN competitor_analysis_groups g ON cp.analysis_group_id = g.id").
+		Joins("INNER JOIN tracked_products tp ON tp.workspace_id = g.workspace_id").
+		Where("tp.id = ? AND cp.product_id = ?", payload.TrackedID, payload.ProductID).
+		Count(&competitorCount)
+
+	severity := "info"
+	if eventType == "out_of_stock" {
+		severity = "warning"
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"previous_state":    previous.State,
+		"current_state":     current.State,
+		"stock_quantity":    current.StockQuantity,
+		"availability_text": current.AvailabilityText,
+		"previous_seen_at":  previous.RecordedAt.Format(time.RFC3339),
+		"is_competitor":     competitorCount > 0,
+	})
+
+	event := models.AnomalyEvent{
+		ProductID: payload.ProductID,
+		ASIN:      payload.ASIN,
+		EventType: eventType,
+		Severity:  severity,
+		Metadata:  metadata,
+		CreatedAt: now,
+	}
+	if err := p.db.Create(&event).Error; err != nil {
+		p.logger.LogBusinessOperation(ctx, "anomaly_record_failed", "apify_worker", payload.ProductID, "failed",
+			"error", err.Error(),
+			"event_type", eventType,
+		)
+		return
+	}
+
+	p.logger.LogBusinessOperation(ctx, "anomaly_detected", "apify_worker", payload.ProductID, "success",
+		"asin", payload.ASIN,
+		"events_count", 1,
+		"events", eventType,
+		"previous_state", previous.State,
+		"current_state", current.State,
+	)
+	p.publishAnomalies(ctx, payload.ProductID, []models.AnomalyEvent{event})
+}
+
+// discoverVariations 登记同一父ASIN下的子变体，追踪设置开启 track_variations 时在同一工作区自动追踪
+func (p *ApifyTaskProcessor) discoverVariations(ctx context.Context, payload RefreshProductDataPayload, data apify.ProductData) {
+	parentASIN := VariationParentASIN(&data)
+	children := VariationChildASINs(&data)
+	if parentASIN == "" || len(children) == 0 {
+		return
+	}
+
+	var trackedProduct models.TrackedProduct
+	if err := p.db.Where("id = ?", payload.TrackedID).First(&trackedProduct).Error; err != nil {
+		p.logger.Error(ctx, "Failed to load tracked product for variation discovery", "tracked_id", payload.TrackedID, "error", err)
+		return
+	}
+
+	// 自动追踪子变体同样占用工作区计划的追踪产品额度
+	capacity := -1
+	if trackedProduct.TrackVariations {
+		var err error
+		if capacity, err = quota.RemainingTrackedProducts(ctx, p.db, trackedProduct.WorkspaceID); err != nil {
+			capacity = 0 // 额度未知时不新建追踪
+		}
+	}
+
+	now := time.Now()
+	var discovered int64
+	skipped := 0
+	newTrackings := []models.TrackedProduct{}
+	childASINs := map[string]string{} // product_id -> asin
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		// 批量创建尚不存在的子变体产品，并发刷新已创建的由唯一约束跳过
+		products := make([]models.Product, 0, len(children))
+		for _, childASIN := range children {
+			products = append(products, models.Product{
+				ASIN:          childASIN,
+				ParentASIN:    &parentASIN,
+				FirstSeenAt:   now,
+				LastUpdatedAt: now,
+				DataSource:    "variation",
+			})
+		}
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "asin"}},
+			DoNothing: true,
+		}).Create(&products)
+		if result.Error != nil {
+			return result.Error
+		}
+		discovered = result.RowsAffected
+
+		// 已存在但尚未关联父变体的产品补上 parent_asin
+		if err := tx.Model(&models.Product{}).
+			Where("asin IN ? AND parent_asin IS NULL", children).
+			Update("parent_asin", parentASIN).Error; err != nil {
+			return err
+		}
+
+		if !trackedProduct.TrackVariations {
+			return nil
+		}
+
+		var childProducts []models.Product
+		if err := tx.Where("asin IN ?", children).Find(&childProducts).Error; err != nil {
+			return err
+		}
+		productIDs := make([]string, 0, len(childProducts))
+		for _, child := range childProducts {
+			productIDs = append(productIDs, child.ID)
+			childASINs[child.ID] = child.ASIN
+		}
+
+		// 工作区已追踪的子变体跳过
+		var trackedIDs []string
+		if err := tx.Model(&models.TrackedProduct{}).
+			Where("workspace_id = ? AND product_id IN ?", trackedProduct.WorkspaceID, productIDs).
+			Pluck("product_id", &trackedIDs).Error; err != nil {
+			return err
+		}
+		alreadyTracked := make(map[string]bool, len(trackedIDs))
+		for _, id := range trackedIDs {
+			alreadyTracked[id] = true
+		}
+
+		nextCheck := now.Add(24 * time.Hour)
+		for _, child := range childProducts {
+			if alreadyTracked[child.ID] {
+				continue
+			}
+			if capacity >= 0 && len(newTrackings) >= capacity {
+				skipped++
+				continue
+			}
+			childTracking := models.TrackedProduct{
+				UserID:               trackedProduct.UserID,
+				WorkspaceID:          trackedProduct.WorkspaceID,
+				ProductID:            child.ID,
+				IsActive:             true,
+				TrackingFrequency:    trackedProduct.TrackingFrequency,
+				PriceChangeThreshold: trackedProduct.PriceChangeThreshold,
+				BSRChangeThreshold:   trackedProduct.BSRChangeThreshold,
+				NextCheckAt:          &nextCheck,
+			}
+			result := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "workspace_id"}, {Name: "product_id"}},
+				DoNothing: true,
+			}).Create(&childTracking)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				newTrackings = append(newTrackings, childTracking)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		p.logger.Error(ctx, "Failed to save variations", "asin", payload.ASIN, "parent_asin", parentASIN, "error", err)
+		return
+	}
+
+	// 事务提交后为新追踪的子变体获取初始数据
+	for _, childTracking := range newTrackings {
+		childASIN := childASINs[childTracking.ProductID]
+		if _, err := EnqueueRefreshTask(ctx, p.asynqClient, p.redisClient, RefreshProductDataPayload{
+			ProductID:    childTracking.ProductID,
+			TrackedID:    childTracking.ID,
+			ASIN:         childASIN,
+			UserID:       trackedProduct.UserID,
+			RequestedAt:  now.Format(time.RFC3339),
+			InitialFetch: true,
+		}); err != nil {
+			p.logger.Error(ctx, "Failed to enqueue variation refresh", "asin", childASIN, "error", err)
+		}
+	}
+
+	p.logger.LogBusinessOperation(ctx, "variations_discovered", "apify_worker", payload.ProductID, "success",
+		"asin", payload.ASIN,
+		"parent_asin", parentASIN,
+		"variations", len(children),
+		"new_products", discovered,
+		"new_trackings", len(newTrackings),
+		"over_quota", skipped,
+	)
+}
+
+// SeverityForPriceChange 根据价格变化百分比确定严重程度
+func SeverityForPriceChange(percentage float64) string {
 	if percentage >= 20 {
 		return "critical"
 	} else if percentage >= 10 {
@@ -355,8 +1023,8 @@ func getSeverityForPriceChange(percentage float64) string {
 	return "info"
 }
 
-// getSeverityForBSRChange 根据BSR变化百分比确定严重程度
-func getSeverityForBSRChange(percentage float64) string {
+// SeverityForBSRChange 根据BSR变化百分比确定严重程度
+func SeverityForBSRChange(percentage float64) string {
 	if percentage >= 50 {
 		return "critical"
 	} else if percentage >= 30 {
@@ -369,30 +1037,126 @@ func getSeverityForBSRChange(percentage float64) string {
 func getEventSummary(events []models.AnomalyEvent) string {
 	summary := make([]string, len(events))
 	for i, event := range events {
+		if event.ChangePercentage == nil {
+			summary[i] = event.EventType
+			continue
+		}
 		summary[i] = fmt.Sprintf("%s:%.1f%%", event.EventType, *event.ChangePercentage)
 	}
 	return strings.Join(summary, ",")
 }
 
-// invalidateProductCache 清理产品相关缓存（使用统一缓存key）
-func (p *ApifyTaskProcessor) invalidateProductCache(ctx context.Context, asin, productID, userID string) {
-	// 使用统一的缓存key清理产品数据缓存
-	productDataKey := cache.ProductDataKey(productID)
-	if err := p.redisClient.Del(ctx, productDataKey).Err(); err != nil {
-		p.logger.Error(ctx, "Failed to delete product data cache", "key", productDataKey, "error", err)
+// writeProductCache 刷新提交后将产品和最新快照写入产品缓存 (写穿)，失败时删除缓存避免读到旧数据
+func (p *ApifyTaskProcessor) writeProductCache(ctx context.Context, asin, productID, userID string) {
+	var product models.Product
+	err := p.db.Preload("LatestSnapshot").Where("id = ?", productID).First(&product).Error
+	if err == nil {
+		err = p.productCache.Set(ctx, productID, cache.NewProductData(&product))
+	}
+	if err != nil {
+		p.logger.Error(ctx, "Failed to write product data cache", "product_id", productID, "error", err)
+		if err := p.productCache.Delete(ctx, productID); err != nil {
+			p.logger.Error(ctx, "Failed to delete product data cache", "key", p.productCache.Key(productID), "error", err)
+		}
+		return
 	}
 
-	p.logger.Info(ctx, "Invalidated product cache",
-		"product_id", productID,
-		"asin", asin,
-		"initiator_user_id", userID)
-
-	p.logger.LogBusinessOperation(ctx, "cache_invalidated", "apify_worker", productID, "success",
+	p.logger.LogBusinessOperation(ctx, "cache_written", "apify_worker", productID, "success",
 		"asin", asin,
 		"initiator_user_id", userID,
 	)
 }
 
+// publishEvent 向一组用户推送事件，推送失败只记录日志不影响任务
+func (p *ApifyTaskProcessor) publishEvent(ctx context.Context, eventType string, userIDs []string, productID string, data interface{}) {
+	pending := make([]events.Event, 0, len(userIDs))
+	for _, userID := range userIDs {
+		event, err := events.New(eventType, userID, productID, data)
+		if err != nil {
+			p.logger.Error(ctx, "Failed to build event", "event_type", eventType, "user_id", userID, "error", err)
+			return
+		}
+		pending = append(pending, event)
+	}
+	if err := p.eventBus.Publish(ctx, pending...); err != nil {
+		p.logger.Error(ctx, "Failed to publish event", "event_type", eventType, "error", err)
+	}
+}
+
+// workspaceMemberIDs 返回资源 (追踪记录、分析组) 所在工作区的成员，查询失败时只通知发起任务的用户
+func (p *ApifyTaskProcessor) workspaceMemberIDs(ctx context.Context, model interface{}, id, initiatorID string) []string {
+	var workspaceIDs []string
+	err := p.db.Model(model).Where("id = ?", id).Pluck("workspace_id", &workspaceIDs).Error
+	if err == nil && len(workspaceIDs) > 0 {
+		var userIDs []string
+		if userIDs, err = workspace.WorkspaceMemberIDs(p.db, workspaceIDs[0]); err == nil {
+			return userIDs
+		}
+	}
+	p.logger.Error(ctx, "Failed to load event subscribers", "resource_id", id, "error", err)
+	return []string{initiatorID}
+}
+
+// publishRefreshFailed 推送刷新失败事件，will_retry 表示任务是否还会重试
+func (p *ApifyTaskProcessor) publishRefreshFailed(ctx context.Context, payload RefreshProductDataPayload, subscribers []string, taskErr error) {
+	retryCount, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	p.publishEvent(ctx, events.TypeRefreshFailed, subscribers, payload.ProductID, map[string]interface{}{
+		"tracked_id":  payload.TrackedID,
+		"asin":        payload.ASIN,
+		"error":       taskErr.Error(),
+		"retry_count": retryCount,
+		"will_retry":  retryCount < maxRetry,
+	})
+}
+
+// publishAnomalies 推送异常事件：工作区私有事件只推送给该工作区的成员，其余推送给正在追踪该产品的所有工作区的成员
+func (p *ApifyTaskProcessor) publishAnomalies(ctx context.Context, productID string, anomalies []models.AnomalyEvent) {
+	subscribers := map[string][]string{} // workspace_id ("" 表示所有追踪工作区) -> 成员
+	pending := []events.Event{}
+	for _, anomaly := range anomalies {
+		scope := ""
+		if anomaly.WorkspaceID != nil {
+			scope = *anomaly.WorkspaceID
+		}
+		userIDs, ok := subscribers[scope]
+		if !ok {
+			var err error
+			if scope == "" {
+				userIDs, err = workspace.MemberUserIDs(p.db, productID)
+			} else {
+				userIDs, err = workspace.WorkspaceMemberIDs(p.db, scope)
+			}
+			if err != nil {
+				p.logger.Error(ctx, "Failed to load anomaly subscribers", "product_id", productID, "workspace_id", scope, "error", err)
+				continue
+			}
+			subscribers[scope] = userIDs
+		}
+
+		for _, userID := range userIDs {
+			event, err := events.New(events.TypeAnomalyDetected, userID, productID, map[string]interface{}{
+				"anomaly_id":        anomaly.ID,
+				"asin":              anomaly.ASIN,
+				"event_type":        anomaly.EventType,
+				"severity":          anomaly.Severity,
+				"old_value":         anomaly.OldValue,
+				"new_value":         anomaly.NewValue,
+				"change_percentage": anomaly.ChangePercentage,
+				"metadata":          anomaly.Metadata,
+				"created_at":        anomaly.CreatedAt,
+			})
+			if err != nil {
+				continue
+			}
+			pending = append(pending, event)
+		}
+	}
+	if err := p.eventBus.Publish(ctx, pending...); err != nil {
+		p.logger.Error(ctx, "Failed to publish anomaly events", "product_id", productID, "error", err)
+	}
+}
+
 // HandleGenerateReport 处理异步报告生成任务
 func (p *ApifyTaskProcessor) HandleGenerateReport(ctx context.Context, t *asynq.Task) error {
 	// 解析任务载荷
@@ -437,6 +1201,12 @@ func (p *ApifyTaskProcessor) HandleGenerateReport(ctx context.Context, t *asynq.
 		"user_id", payload.UserID,
 	)
 
+	subscribers := p.workspaceMemberIDs(ctx, &models.CompetitorAnalysisGroup{}, payload.AnalysisID, payload.UserID)
+	p.publishEvent(ctx, events.TypeReportCompleted, subscribers, "", map[string]interface{}{
+		"analysis_id": payload.AnalysisID,
+		"task_id":     payload.TaskID,
+	})
+
 	return nil
 }
 
@@ -483,13 +1253,15 @@ func (p *ApifyTaskProcessor) generateReportAsync(ctx context.Context, payload Ge
 		return fmt.Errorf("failed to fetch main product data: %w", err)
 	}
 
-	// 4. 准备分析数据
+	// 4. 准备分析数据 (价格统一换算为展示货币)
+	displayCurrency := fx.NormalizeCurrency(payload.Currency)
+	mainPrice := p.fxConverter.ConvertOrKeep(mainProductData.Price, mainProductData.Currency, displayCurrency, mainProductData.RecordedAt)
 	analysisData := llm.CompetitorAnalysisData{
 		MainProduct: llm.ProductData{
 			ASIN:        analysisGroup.MainProduct.ASIN,
 			Title:       p.getStringValue(analysisGroup.MainProduct.Title),
-			Price:       mainProductData.Price,
-			Currency:    mainProductData.Currency,
+			Price:       mainPrice.Amount,
+			Currency:    mainPrice.Currency,
 			BSR:         mainProductData.BSR,
 			Rating:      mainProductData.Rating,
 			ReviewCount: mainProductData.ReviewCount,
@@ -497,6 +1269,9 @@ func (p *ApifyTaskProcessor) generateReportAsync(ctx context.Context, payload Ge
 		Competitors:     make([]llm.ProductData, len(analysisGroup.Competitors)),
 		AnalysisMetrics: []string{"price", "bsr", "rating", "features"},
 	}
+	if err := estimation.FillReportSalesEstimate(p.db, p.salesModel, p.fxConverter, &analysisData.MainProduct, analysisGroup.MainProduct.ID); err != nil {
+		p.logger.Error(ctx, "Failed to estimate main product sales", "asin", analysisGroup.MainProduct.ASIN, "error", err)
+	}
 
 	// 5. 获取竞品数据
 	for i, comp := range analysisGroup.Competitors {
@@ -509,15 +1284,19 @@ func (p *ApifyTaskProcessor) generateReportAsync(ctx context.Context, payload Ge
 			}
 		}
 
+		competitorPrice := p.fxConverter.ConvertOrKeep(competitorData.Price, competitorData.Currency, displayCurrency, competitorData.RecordedAt)
 		analysisData.Competitors[i] = llm.ProductData{
 			ASIN:        comp.Product.ASIN,
 			Title:       p.getStringValue(comp.Product.Title),
-			Price:       competitorData.Price,
-			Currency:    competitorData.Currency,
+			Price:       competitorPrice.Amount,
+			Currency:    competitorPrice.Currency,
 			BSR:         competitorData.BSR,
 			Rating:      competitorData.Rating,
 			ReviewCount: competitorData.ReviewCount,
 		}
+		if err := estimation.FillReportSalesEstimate(p.db, p.salesModel, p.fxConverter, &analysisData.Competitors[i], comp.Product.ID); err != nil {
+			p.logger.Error(ctx, "Failed to estimate competitor sales", "asin", comp.Product.ASIN, "error", err)
+		}
 	}
 
 	// 6. 调用DeepSeek生成报告
@@ -568,40 +1347,33 @@ type ProductDataForReport struct {
 	BSR         int
 	Rating      float64
 	ReviewCount int
+	RecordedAt  time.Time
 }
 
-// getLatestProductDataForReport 获取产品的最新数据
+// getLatestProductDataForReport 从最新快照获取产品的最新数据
 func (p *ApifyTaskProcessor) getLatestProductDataForReport(productID string) (*ProductDataForReport, error) {
-	// 获取最新价格数据
-	var latestPrice models.PriceHistory
-	err := p.db.Where("product_id = ?", productID).
-		Order("recorded_at DESC").
-		First(&latestPrice).Error
-	if err != nil && err != gorm.ErrRecordNotFound {
-		return nil, err
-	}
-
-	// 获取最新排名数据
-	var latestRanking models.RankingHistory
-	err = p.db.Where("product_id = ?", productID).
-		Order("recorded_at DESC").
-		First(&latestRanking).Error
-	if err != nil && err != gorm.ErrRecordNotFound {
+	var snapshot models.LatestSnapshot
+	err := p.db.Where("product_id = ?", productID).First(&snapshot).Error
+	if err == gorm.ErrRecordNotFound {
+		// 尚未刷新过的产品没有快照
+		return &ProductDataForReport{}, nil
+	} else if err != nil {
 		return nil, err
 	}
 
 	data := &ProductDataForReport{
-		Price:       latestPrice.Price,
-		Currency:    latestPrice.Currency,
-		ReviewCount: latestRanking.ReviewCount,
+		Price:       snapshot.Price,
+		Currency:    snapshot.Currency,
+		ReviewCount: snapshot.ReviewCount,
+		RecordedAt:  snapshot.RecordedAt,
 	}
 
 	// 安全设置BSR和Rating
-	if latestRanking.BSRRank != nil {
-		data.BSR = *latestRanking.BSRRank
+	if snapshot.BSRRank != nil {
+		data.BSR = *snapshot.BSRRank
 	}
-	if latestRanking.Rating != nil {
-		data.Rating = *latestRanking.Rating
+	if snapshot.Rating != nil {
+		data.Rating = *snapshot.Rating
 	}
 
 	return data, nil
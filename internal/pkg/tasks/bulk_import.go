@@ -0,0 +1,520 @@
+package tasks
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/utils"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	// ImportSyncLimit 不超过该行数时同步导入，否则创建异步任务
+	ImportSyncLimit = 100
+	// ImportMaxRows 单次导入的最大行数
+	ImportMaxRows = 5000
+	// ImportChunkSize 每个事务处理的行数
+	ImportChunkSize = 100
+	// ImportFetchBatchSize 初始数据抓取每批的任务数，批次之间错开 ImportFetchBatchInterval
+	ImportFetchBatchSize     = 50
+	ImportFetchBatchInterval = time.Minute
+)
+
+// 逐行导入结果状态
+const (
+	ImportStatusCreated        = "created"
+	ImportStatusAlreadyTracked = "already_tracked"
+	ImportStatusInvalid        = "invalid"
+	ImportStatusFailed         = "failed"
+)
+
+// BulkImportPayload 异步批量导入任务载荷，待导入的行保存在 ImportJob.Rows 中
+type BulkImportPayload struct {
+	JobID  string `json:"job_id"`
+	UserID string `json:"user_id"`
+}
+
+// ImportRow 待导入的一行
+type ImportRow struct {
+	Line                 int      `json:"line"`
+	ASIN                 string   `json:"asin"`
+	Alias                string   `json:"alias,omitempty"`
+	PriceChangeThreshold *float64 `json:"price_change_threshold,omitempty"`
+	BSRChangeThreshold   *float64 `json:"bsr_change_threshold,omitempty"`
+	Tags                 []string `json:"tags,omitempty"`
+	// InvalidNumbers 无法解析为数字的列及其原始值，校验时作为字段错误返回
+	InvalidNumbers map[string]string `json:"invalid_numbers,omitempty"`
+}
+
+// ImportRowResult 单行导入结果
+type ImportRowResult struct {
+	Line      int    `json:"line"`
+	ASIN      string `json:"asin"`
+	Status    string `json:"status"` // created, already_tracked, invalid, failed
+	TrackedID string `json:"tracked_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ParseImportCSV 解析带表头的CSV，支持列: asin, alias, price_change_threshold, bsr_change_threshold, tags
+// tags 列中多个标签用 ; 或 | 分隔；行号从数据第一行开始计为 1
+func ParseImportCSV(r io.Reader) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []ImportRow{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["asin"]; !ok {
+		return nil, fmt.Errorf("csv header must contain an asin column")
+	}
+
+	value := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	rows := []ImportRow{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read csv line %d: %w", line, err)
+		}
+
+		row := ImportRow{
+			Line:  line,
+			ASIN:  value(record, "asin"),
+			Alias: value(record, "alias"),
+		}
+		if row.ASIN == "" && row.Alias == "" {
+			continue // 空行
+		}
+		row.PriceChangeThreshold = row.parseOptionalFloat("price_change_threshold", value(record, "price_change_threshold"))
+		row.BSRChangeThreshold = row.parseOptionalFloat("bsr_change_threshold", value(record, "bsr_change_threshold"))
+		row.Tags = splitTags(value(record, "tags"))
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ParseImportJSON 解析JSON数组格式的导入数据，行号为数组下标 + 1
+func ParseImportJSON(data []byte) ([]ImportRow, error) {
+	var rows []ImportRow
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode json import: %w", err)
+	}
+	for i := range rows {
+		rows[i].Line = i + 1
+	}
+	return rows, nil
+}
+
+// ValidateImportRow 标准化并校验单行数据
+func ValidateImportRow(row *ImportRow) error {
+	row.ASIN = strings.ToUpper(strings.TrimSpace(row.ASIN))
+	row.Alias = strings.TrimSpace(row.Alias)
+
+	if err := utils.ValidateASIN(row.ASIN); err != nil {
+		return err
+	}
+	if err := utils.ValidateProductAlias(row.Alias); err != nil {
+		return err
+	}
+	for _, field := range []string{"price_change_threshold", "bsr_change_threshold"} {
+		if raw, ok := row.InvalidNumbers[field]; ok {
+			return errors.NewValidationError("Invalid number", []errors.FieldError{
+				{Field: field, Message: fmt.Sprintf("%s must be a number, got %q", field, raw)},
+			})
+		}
+	}
+	if row.PriceChangeThreshold != nil {
+		if err := utils.ValidateThreshold(*row.PriceChangeThreshold, "price_change_threshold"); err != nil {
+			return err
+		}
+	}
+	if row.BSRChangeThreshold != nil {
+		if err := utils.ValidateThreshold(*row.BSRChangeThreshold, "bsr_change_threshold"); err != nil {
+			return err
+		}
+	}
+
+	tags := make([]string, 0, len(row.Tags))
+	for _, tag := range row.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			if len(tag) > 50 {
+				return errors.NewValidationError("Tag too long", []errors.FieldError{
+					{Field: "tags", Message: "Tag must be at most 50 characters"},
+				})
+			}
+			tags = append(tags, tag)
+		}
+	}
+	row.Tags = tags
+	return nil
+}
+
+// ImportTrackedProducts 按块在事务中为工作区创建产品、追踪记录和标签，userID 记录为追踪的创建者
+// capacity 为计划还允许新追踪的产品数 (负数表示不限)，超出的行记为失败；整块保存失败时逐行重试，只有出错的行记为失败
+// progress 在每个块处理完成后回调；返回逐行结果和需要抓取初始数据的任务载荷
+func ImportTrackedProducts(db *gorm.DB, userID, workspaceID string, capacity int, rows []ImportRow, progress func(processed int, results []ImportRowResult)) ([]ImportRowResult, []RefreshProductDataPayload) {
+	results := make([]ImportRowResult, 0, len(rows))
+	payloads := []RefreshProductDataPayload{}
+
+	// 先校验所有行，文件内重复的ASIN只保留第一行
+	valid := make([]ImportRow, 0, len(rows))
+	seen := make(map[string]int, len(rows))
+	for _, row := range rows {
+		if err := ValidateImportRow(&row); err != nil {
+			results = append(results, ImportRowResult{Line: row.Line, ASIN: row.ASIN, Status: ImportStatusInvalid, Error: importErrorMessage(err)})
+			continue
+		}
+		if line, ok := seen[row.ASIN]; ok {
+			results = append(results, ImportRowResult{Line: row.Line, ASIN: row.ASIN, Status: ImportStatusInvalid, Error: fmt.Sprintf("duplicate ASIN, first seen on line %d", line)})
+			continue
+		}
+		seen[row.ASIN] = row.Line
+		valid = append(valid, row)
+	}
+	if progress != nil {
+		progress(len(results), results)
+	}
+
+	for start := 0; start < len(valid); start += ImportChunkSize {
+		end := start + ImportChunkSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+		chunk := valid[start:end]
+
+		chunkResults, chunkPayloads, err := importChunkInTransaction(db, userID, workspaceID, capacity, chunk)
+		if err != nil {
+			chunkResults, chunkPayloads = nil, nil
+			for _, row := range chunk {
+				rowCapacity := capacity
+				if capacity >= 0 {
+					rowCapacity = capacity - len(chunkPayloads)
+				}
+				rowResults, rowPayloads, err := importChunkInTransaction(db, userID, workspaceID, rowCapacity, []ImportRow{row})
+				if err != nil {
+					rowResults = []ImportRowResult{{Line: row.Line, ASIN: row.ASIN, Status: ImportStatusFailed, Error: "failed to save tracking"}}
+				}
+				chunkResults = append(chunkResults, rowResults...)
+				chunkPayloads = append(chunkPayloads, rowPayloads...)
+			}
+		}
+		if capacity >= 0 {
+			capacity -= len(chunkPayloads)
+		}
+
+		results = append(results, chunkResults...)
+		payloads = append(payloads, chunkPayloads...)
+		if progress != nil {
+			progress(len(results), results)
+		}
+	}
+
+	return results, payloads
+}
+
+// importChunkInTransaction 在独立事务中导入一块数据，出错时整块回滚
+func importChunkInTransaction(db *gorm.DB, userID, workspaceID string, capacity int, rows []ImportRow) ([]ImportRowResult, []RefreshProductDataPayload, error) {
+	var results []ImportRowResult
+	var payloads []RefreshProductDataPayload
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		results, payloads, err = importChunk(tx, userID, workspaceID, capacity, rows)
+		return err
+	})
+	return results, payloads, err
+}
+
+// importChunk 在一个事务中导入一块数据，最多新建 capacity 条追踪记录 (负数表示不限)
+func importChunk(tx *gorm.DB, userID, workspaceID string, capacity int, rows []ImportRow) ([]ImportRowResult, []RefreshProductDataPayload, error) {
+	now := time.Now()
+	asins := make([]string, len(rows))
+	for i, row := range rows {
+		asins[i] = row.ASIN
+	}
+
+	// 查找或创建产品
+	var products []models.Product
+	if err := tx.Where("asin IN ?", asins).Find(&products).Error; err != nil {
+		return nil, nil, err
+	}
+	productsByASIN := make(map[string]models.Product, len(rows))
+	for _, product := range products {
+		productsByASIN[product.ASIN] = product
+	}
+
+	newProducts := []models.Product{}
+	for _, row := range rows {
+		if _, ok := productsByASIN[row.ASIN]; !ok {
+			newProducts = append(newProducts, models.Product{
+				ASIN:          row.ASIN,
+				FirstSeenAt:   now,
+				LastUpdatedAt: now,
+				DataSource:    "import",
+			})
+		}
+	}
+	if len(newProducts) > 0 {
+		if err := tx.Create(&newProducts).Error; err != nil {
+			return nil, nil, err
+		}
+		for _, product := range newProducts {
+			productsByASIN[product.ASIN] = product
+		}
+	}
+
+	// 已在追踪的产品不重复创建
+	productIDs := make([]string, 0, len(productsByASIN))
+	for _, product := range productsByASIN {
+		productIDs = append(productIDs, product.ID)
+	}
+	var existing []models.TrackedProduct
+	if err := tx.Where("workspace_id = ? AND product_id IN ?", workspaceID, productIDs).Find(&existing).Error; err != nil {
+		return nil, nil, err
+	}
+	existingByProduct := make(map[string]string, len(existing))
+	for _, tracked := range existing {
+		existingByProduct[tracked.ProductID] = tracked.ID
+	}
+
+	results := make([]ImportRowResult, 0, len(rows))
+	payloads := []RefreshProductDataPayload{}
+	nextCheck := calculateImportNextCheck(now)
+	for _, row := range rows {
+		product := productsByASIN[row.ASIN]
+		if trackedID, ok := existingByProduct[product.ID]; ok {
+			results = append(results, ImportRowResult{Line: row.Line, ASIN: row.ASIN, Status: ImportStatusAlreadyTracked, TrackedID: trackedID})
+			continue
+		}
+		if capacity >= 0 && len(payloads) >= capacity {
+			results = append(results, ImportRowResult{Line: row.Line, ASIN: row.ASIN, Status: ImportStatusFailed, Error: "tracked product limit of the plan reached"})
+			continue
+		}
+
+		tracked := models.TrackedProduct{
+			UserID:               userID,
+			WorkspaceID:          workspaceID,
+			ProductID:            product.ID,
+			IsActive:             true,
+			TrackingFrequency:    "daily",
+			PriceChangeThreshold: 10.0,
+			BSRChangeThreshold:   30.0,
+			NextCheckAt:          &nextCheck,
+		}
+		if row.Alias != "" {
+			alias := row.Alias
+			tracked.Alias = &alias
+		}
+		if row.PriceChangeThreshold != nil {
+			tracked.PriceChangeThreshold = *row.PriceChangeThreshold
+		}
+		if row.BSRChangeThreshold != nil {
+			tracked.BSRChangeThreshold = *row.BSRChangeThreshold
+		}
+		if err := tx.Omit("Tags").Create(&tracked).Error; err != nil {
+			return nil, nil, err
+		}
+		if err := attachTags(tx, userID, workspaceID, tracked.ID, row.Tags); err != nil {
+			return nil, nil, err
+		}
+
+		existingByProduct[product.ID] = tracked.ID
+		results = append(results, ImportRowResult{Line: row.Line, ASIN: row.ASIN, Status: ImportStatusCreated, TrackedID: tracked.ID})
+		payloads = append(payloads, RefreshProductDataPayload{
+			ProductID:   product.ID,
+			TrackedID:   tracked.ID,
+			ASIN:        product.ASIN,
+			UserID:      userID,
+			RequestedAt: now.Format(time.RFC3339),
+		})
+	}
+
+	return results, payloads, nil
+}
+
+// attachTags 按名称查找或创建工作区标签并关联到追踪产品（名称不区分大小写）
+func attachTags(tx *gorm.DB, userID, workspaceID, trackedID string, names []string) error {
+	for _, name := range names {
+		var tag models.Tag
+		err := tx.Where("workspace_id = ? AND LOWER(name) = LOWER(?)", workspaceID, name).First(&tag).Error
+		if err == gorm.ErrRecordNotFound {
+			tag = models.Tag{UserID: userID, WorkspaceID: workspaceID, Name: name}
+			if err := tx.Create(&tag).Error; err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		link := models.TrackedProductTag{TrackedProductID: trackedID, TagID: tag.ID}
+		if err := tx.Where(link).FirstOrCreate(&link).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnqueueInitialFetches 分批发送初始数据抓取任务，批次之间错开以免集中调用 Apify
+func EnqueueInitialFetches(ctx context.Context, client *asynq.Client, rdb *redis.Client, payloads []RefreshProductDataPayload) (int, error) {
+	enqueued := 0
+	var lastErr error
+	for i, payload := range payloads {
+		delay := time.Duration(i/ImportFetchBatchSize) * ImportFetchBatchInterval
+		if _, err := EnqueueRefreshTask(ctx, client, rdb, payload, asynq.ProcessIn(delay)); err != nil {
+			lastErr = err
+			continue
+		}
+		enqueued++
+	}
+	return enqueued, lastErr
+}
+
+// CountImportResults 统计逐行结果
+func CountImportResults(results []ImportRowResult) (created, existing, failed int) {
+	for _, result := range results {
+		switch result.Status {
+		case ImportStatusCreated:
+			created++
+		case ImportStatusAlreadyTracked:
+			existing++
+		default:
+			failed++
+		}
+	}
+	return created, existing, failed
+}
+
+// HandleBulkImport 处理异步批量导入任务，每个块完成后更新任务进度
+func (p *ApifyTaskProcessor) HandleBulkImport(ctx context.Context, t *asynq.Task) error {
+	var payload BulkImportPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	var job models.ImportJob
+	if err := p.db.Where("id = ? AND user_id = ?", payload.JobID, payload.UserID).First(&job).Error; err != nil {
+		return fmt.Errorf("failed to get import job: %w", err)
+	}
+
+	var rows []ImportRow
+	if err := json.Unmarshal(job.Rows, &rows); err != nil {
+		errorMsg := "failed to decode import rows: " + err.Error()
+		p.db.Model(&job).Updates(map[string]interface{}{"status": "failed", "error_message": errorMsg})
+		return fmt.Errorf("%s", errorMsg)
+	}
+
+	startedAt := time.Now()
+	p.db.Model(&job).Updates(map[string]interface{}{"status": "processing", "started_at": startedAt})
+
+	// 排队期间额度可能已被占用，按处理时的剩余额度导入
+	capacity, err := quota.RemainingTrackedProducts(ctx, p.db, job.WorkspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to load tracked product quota: %w", err)
+	}
+
+	results, payloads := ImportTrackedProducts(p.db, payload.UserID, job.WorkspaceID, capacity, rows, func(processed int, results []ImportRowResult) {
+		created, existing, failed := CountImportResults(results)
+		p.db.Model(&job).Updates(map[string]interface{}{
+			"processed_rows": processed,
+			"created_rows":   created,
+			"existing_rows":  existing,
+			"failed_rows":    failed,
+		})
+	})
+
+	enqueued, err := EnqueueInitialFetches(ctx, p.asynqClient, p.redisClient, payloads)
+	if err != nil {
+		p.logger.Error(ctx, "Failed to enqueue some initial fetches", "job_id", job.ID, "error", err)
+	}
+
+	resultsJSON, _ := json.Marshal(results)
+	completedAt := time.Now()
+	created, existing, failed := CountImportResults(results)
+	if err := p.db.Model(&job).Updates(map[string]interface{}{
+		"status":         "completed",
+		"processed_rows": len(results),
+		"created_rows":   created,
+		"existing_rows":  existing,
+		"failed_rows":    failed,
+		"results":        resultsJSON,
+		"completed_at":   completedAt,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to save import results: %w", err)
+	}
+
+	p.logger.LogBusinessOperation(ctx, "bulk_import_completed", "import_job", job.ID, "success",
+		"user_id", payload.UserID,
+		"rows", len(rows),
+		"created", created,
+		"already_tracked", existing,
+		"failed", failed,
+		"fetches_enqueued", enqueued,
+	)
+
+	return nil
+}
+
+// calculateImportNextCheck 导入的产品固定每天检查
+func calculateImportNextCheck(now time.Time) time.Time {
+	return now.Add(24 * time.Hour)
+}
+
+// importErrorMessage 提取校验错误中的字段信息
+func importErrorMessage(err error) string {
+	if apiErr, ok := err.(*errors.APIError); ok && len(apiErr.ErrorDetail.Details) > 0 {
+		return apiErr.ErrorDetail.Details[0].Message
+	}
+	return err.Error()
+}
+
+// parseOptionalFloat 解析可选的数字列，空值时返回 nil；无法解析时记录原始值，由 ValidateImportRow 报告
+func (row *ImportRow) parseOptionalFloat(column, value string) *float64 {
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		if row.InvalidNumbers == nil {
+			row.InvalidNumbers = map[string]string{}
+		}
+		row.InvalidNumbers[column] = value
+		return nil
+	}
+	return &parsed
+}
+
+// splitTags 拆分标签列
+func splitTags(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == '|' })
+}
@@ -0,0 +1,59 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	data := "\ufeffASIN,Alias,price_change_threshold,bsr_change_threshold,tags\n" +
+		"b08n5wrwnw,Echo Dot,5,,kitchen;gift\n" +
+		",,,,\n" +
+		"B07XJ8C8F5,,,25.5,\n"
+
+	rows, err := ParseImportCSV(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+
+	assert.Equal(t, 1, rows[0].Line)
+	assert.Equal(t, "b08n5wrwnw", rows[0].ASIN)
+	assert.Equal(t, "Echo Dot", rows[0].Alias)
+	assert.Equal(t, 5.0, *rows[0].PriceChangeThreshold)
+	assert.Nil(t, rows[0].BSRChangeThreshold)
+	assert.Equal(t, []string{"kitchen", "gift"}, rows[0].Tags)
+
+	// 空行跳过但保留原始行号
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Nil(t, rows[1].PriceChangeThreshold)
+	assert.Equal(t, 25.5, *rows[1].BSRChangeThreshold)
+
+	// 无法解析的数字保留原始值，校验时按字段报错而不是静默使用默认阈值
+	rows, err = ParseImportCSV(strings.NewReader("asin,price_change_threshold\nB08N5WRWNW,ten\n"))
+	assert.NoError(t, err)
+	assert.Nil(t, rows[0].PriceChangeThreshold)
+	assert.Equal(t, "ten", rows[0].InvalidNumbers["price_change_threshold"])
+	err = ValidateImportRow(&rows[0])
+	assert.Error(t, err)
+	assert.Contains(t, importErrorMessage(err), "price_change_threshold")
+
+	_, err = ParseImportCSV(strings.NewReader("alias,tags\nfoo,bar\n"))
+	assert.Error(t, err)
+}
+
+func TestValidateImportRow(t *testing.T) {
+	rows, err := ParseImportJSON([]byte(`[{"asin":" b08n5wrwnw ","tags":["gift"," ",""]},{"asin":"bad"}]`))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, 2, rows[1].Line)
+
+	assert.NoError(t, ValidateImportRow(&rows[0]))
+	assert.Equal(t, "B08N5WRWNW", rows[0].ASIN)
+	assert.Equal(t, []string{"gift"}, rows[0].Tags)
+
+	assert.Error(t, ValidateImportRow(&rows[1]))
+
+	threshold := 150.0
+	assert.Error(t, ValidateImportRow(&ImportRow{ASIN: "B08N5WRWNW", PriceChangeThreshold: &threshold}))
+}
@@ -0,0 +1,90 @@
+package tasks
+
+import (
+	"math"
+	"time"
+
+	"amazonpilot/internal/pkg/models"
+)
+
+// PromotionPeriod 一段连续的促销期（相邻的在售记录合并为一段）
+type PromotionPeriod struct {
+	Type          string     `json:"type"` // 活动类型；没有活动标识时为 coupon 或 discount
+	StartedAt     time.Time  `json:"started_at"`
+	EndedAt       *time.Time `json:"ended_at,omitempty"` // 首个非促销记录的时间，进行中时为空
+	DurationHours float64    `json:"duration_hours"`
+	MaxDiscount   float64    `json:"max_discount"`
+	LowestPrice   float64    `json:"lowest_price"`
+	CouponText    string     `json:"coupon_text,omitempty"`
+}
+
+// Ongoing 促销是否仍在进行
+func (p PromotionPeriod) Ongoing() bool {
+	return p.EndedAt == nil
+}
+
+// BuildPromotionPeriods 根据按时间升序排列的价格历史计算促销期
+// 进行中的促销以 now 计算持续时间
+func BuildPromotionPeriods(history []models.PriceHistory, now time.Time) []PromotionPeriod {
+	periods := []PromotionPeriod{}
+	var current *PromotionPeriod
+
+	for _, h := range history {
+		if !h.IsOnSale {
+			if current != nil {
+				endedAt := h.RecordedAt
+				current.EndedAt = &endedAt
+				current.DurationHours = hoursBetween(current.StartedAt, endedAt)
+				current = nil
+			}
+			continue
+		}
+
+		promotionType := promotionType(h)
+		if current == nil || (current.Type != promotionType && promotionType != "discount") {
+			// 活动类型切换（例如普通折扣升级为秒杀）时开始新的促销期
+			if current != nil {
+				endedAt := h.RecordedAt
+				current.EndedAt = &endedAt
+				current.DurationHours = hoursBetween(current.StartedAt, endedAt)
+			}
+			periods = append(periods, PromotionPeriod{
+				Type:        promotionType,
+				StartedAt:   h.RecordedAt,
+				LowestPrice: h.Price,
+			})
+			current = &periods[len(periods)-1]
+		}
+
+		if h.DiscountPercentage != nil && *h.DiscountPercentage > current.MaxDiscount {
+			current.MaxDiscount = *h.DiscountPercentage
+		}
+		if h.Price > 0 && h.Price < current.LowestPrice {
+			current.LowestPrice = h.Price
+		}
+		if h.CouponText != nil && current.CouponText == "" {
+			current.CouponText = *h.CouponText
+		}
+	}
+
+	if current != nil {
+		current.DurationHours = hoursBetween(current.StartedAt, now)
+	}
+
+	return periods
+}
+
+// promotionType 单条价格记录的促销类型
+func promotionType(h models.PriceHistory) string {
+	if h.DealType != nil && *h.DealType != "" {
+		return *h.DealType
+	}
+	if h.CouponText != nil && *h.CouponText != "" {
+		return "coupon"
+	}
+	return "discount"
+}
+
+func hoursBetween(start, end time.Time) float64 {
+	return math.Round(end.Sub(start).Hours()*10) / 10
+}
@@ -0,0 +1,36 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"amazonpilot/internal/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPromotionPeriods(t *testing.T) {
+	start := time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC)
+	discount := func(v float64) *float64 { return &v }
+	lightning := "lightning_deal"
+	history := []models.PriceHistory{
+		{Price: 39.99, RecordedAt: start},
+		{Price: 29.99, IsOnSale: true, DiscountPercentage: discount(25), DealType: &lightning, RecordedAt: start.Add(2 * time.Hour)},
+		{Price: 27.99, IsOnSale: true, DiscountPercentage: discount(30), DealType: &lightning, RecordedAt: start.Add(4 * time.Hour)},
+		{Price: 39.99, RecordedAt: start.Add(8 * time.Hour)},
+		{Price: 35.99, IsOnSale: true, DiscountPercentage: discount(10), RecordedAt: start.Add(24 * time.Hour)},
+	}
+
+	periods := BuildPromotionPeriods(history, start.Add(36*time.Hour))
+	assert.Len(t, periods, 2)
+
+	assert.Equal(t, "lightning_deal", periods[0].Type)
+	assert.False(t, periods[0].Ongoing())
+	assert.Equal(t, 6.0, periods[0].DurationHours)
+	assert.Equal(t, 30.0, periods[0].MaxDiscount)
+	assert.Equal(t, 27.99, periods[0].LowestPrice)
+
+	assert.Equal(t, "discount", periods[1].Type)
+	assert.True(t, periods[1].Ongoing())
+	assert.Equal(t, 12.0, periods[1].DurationHours)
+}
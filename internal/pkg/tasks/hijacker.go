@@ -0,0 +1,75 @@
+package tasks
+
+import (
+	"encoding/json"
+	"strings"
+
+	"amazonpilot/internal/pkg/models"
+)
+
+// AuthorizedSellers 合并用户自有卖家与产品授权卖家白名单
+func AuthorizedSellers(accounts []models.SellerAccount, trackedProduct *models.TrackedProduct) []models.SellerIdentity {
+	sellers := make([]models.SellerIdentity, 0, len(accounts))
+	for _, account := range accounts {
+		sellers = append(sellers, account.Identity())
+	}
+
+	if trackedProduct.AuthorizedSellers != nil {
+		var allowList []models.SellerIdentity
+		if err := json.Unmarshal(trackedProduct.AuthorizedSellers, &allowList); err == nil {
+			sellers = append(sellers, allowList...)
+		}
+	}
+
+	return sellers
+}
+
+// FindUnauthorizedOffers 返回不在授权列表中的卖家报价（同一卖家只返回一次，优先保留 Buy Box 赢家的报价）
+// 授权列表为空时表示用户未配置，不做检测
+func FindUnauthorizedOffers(offers []models.OfferHistory, authorized []models.SellerIdentity) []models.OfferHistory {
+	if len(authorized) == 0 {
+		return nil
+	}
+
+	index := map[string]int{}
+	unauthorized := []models.OfferHistory{}
+	for _, offer := range offers {
+		sellerID := ""
+		if offer.SellerID != nil {
+			sellerID = *offer.SellerID
+		}
+		if isAuthorizedSeller(authorized, offer.SellerName, sellerID) {
+			continue
+		}
+
+		key := hijackerKey(offer)
+		if i, ok := index[key]; ok {
+			if offer.IsBuyBoxWinner {
+				unauthorized[i] = offer
+			}
+			continue
+		}
+		index[key] = len(unauthorized)
+		unauthorized = append(unauthorized, offer)
+	}
+
+	return unauthorized
+}
+
+// isAuthorizedSeller 判断卖家是否在授权列表中
+func isAuthorizedSeller(authorized []models.SellerIdentity, name, id string) bool {
+	for _, seller := range authorized {
+		if seller.Matches(name, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// hijackerKey 跟卖卖家的去重键，优先使用卖家ID
+func hijackerKey(offer models.OfferHistory) string {
+	if offer.SellerID != nil && *offer.SellerID != "" {
+		return *offer.SellerID
+	}
+	return strings.ToLower(strings.TrimSpace(offer.SellerName))
+}
@@ -0,0 +1,38 @@
+package tasks
+
+import (
+	"testing"
+
+	"amazonpilot/internal/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindUnauthorizedOffers(t *testing.T) {
+	brandID := "A294P4X9EWVXLJ"
+	hijackerID := "A1HIJACKER0001"
+	offers := []models.OfferHistory{
+		{SellerName: "AnkerDirect", SellerID: &brandID, Price: 23.49},
+		{SellerName: "Cheap Deals", SellerID: &hijackerID, Price: 19.99},
+		{SellerName: "Cheap Deals", SellerID: &hijackerID, Price: 21.99, IsBuyBoxWinner: true},
+		{SellerName: "authorized reseller", Price: 22.99},
+		{SellerName: "Unknown Seller", Price: 24.99},
+	}
+
+	// 未配置授权列表时不做检测
+	assert.Empty(t, FindUnauthorizedOffers(offers, nil))
+
+	tracked := &models.TrackedProduct{
+		AuthorizedSellers: []byte(`[{"seller_name": "Authorized Reseller"}]`),
+	}
+	accounts := []models.SellerAccount{{SellerName: "Anker Official", SellerID: &brandID}}
+	authorized := AuthorizedSellers(accounts, tracked)
+	assert.Len(t, authorized, 2)
+
+	unauthorized := FindUnauthorizedOffers(offers, authorized)
+	assert.Len(t, unauthorized, 2)
+	assert.Equal(t, "Cheap Deals", unauthorized[0].SellerName)
+	assert.True(t, unauthorized[0].IsBuyBoxWinner)
+	assert.Equal(t, hijackerID, hijackerKey(unauthorized[0]))
+	assert.Equal(t, "unknown seller", hijackerKey(unauthorized[1]))
+}
@@ -0,0 +1,177 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"amazonpilot/internal/pkg/export"
+	"amazonpilot/internal/pkg/models"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// HistoryExportPayload 后台历史导出任务载荷
+type HistoryExportPayload struct {
+	JobID  string `json:"job_id"`
+	UserID string `json:"user_id"`
+}
+
+// HistoryExportParams 导出参数，保存在 ExportJob.Params 中
+type HistoryExportParams struct {
+	TrackedIDs []string  `json:"tracked_ids,omitempty"` // 为空时导出全部追踪中的产品
+	TagIDs     []string  `json:"tag_ids,omitempty"`     // 只导出带有其中任一标签的产品
+	Metrics    []string  `json:"metrics"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`                 // 不含
+	Currency   string    `json:"currency,omitempty"` // 价格类指标换算的货币，为空时保留原始币种
+}
+
+// ExportFilePath 导出文件在导出目录中的路径
+func ExportFilePath(dir, jobID, format string) string {
+	return filepath.Join(dir, jobID+"."+format)
+}
+
+// SetExportConfig 配置后台导出文件目录和保留时间
+func (p *ApifyTaskProcessor) SetExportConfig(dir string, retention time.Duration) {
+	p.exportDir = dir
+	p.exportRetention = retention
+}
+
+// HandleHistoryExport 生成历史数据导出文件，先写临时文件，完成后重命名
+func (p *ApifyTaskProcessor) HandleHistoryExport(ctx context.Context, t *asynq.Task) error {
+	var payload HistoryExportPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	var job models.ExportJob
+	if err := p.db.Where("id = ? AND user_id = ?", payload.JobID, payload.UserID).First(&job).Error; err != nil {
+		return fmt.Errorf("failed to get export job: %w", err)
+	}
+
+	startedAt := time.Now()
+	p.db.Model(&job).Updates(map[string]interface{}{"status": "processing", "started_at": startedAt})
+
+	rowCount, fileSize, params, err := p.writeHistoryExport(&job)
+	if err != nil {
+		errorMsg := err.Error()
+		p.db.Model(&job).Updates(map[string]interface{}{"status": "failed", "error_message": errorMsg})
+		p.logger.LogBusinessOperation(ctx, "history_export_failed", "export_job", job.ID, "failed",
+			"user_id", payload.UserID,
+			"error", errorMsg,
+		)
+		return fmt.Errorf("history export failed: %w", err)
+	}
+
+	completedAt := time.Now()
+	expiresAt := completedAt.Add(p.exportRetention)
+	fileName := export.FileName(job.Format, params.From, params.To)
+	if err := p.db.Model(&job).Updates(map[string]interface{}{
+		"status":        "completed",
+		"file_name":     fileName,
+		"file_size":     fileSize,
+		"row_count":     rowCount,
+		"error_message": nil,
+		"completed_at":  completedAt,
+		"expires_at":    expiresAt,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to save export result: %w", err)
+	}
+
+	p.logger.LogBusinessOperation(ctx, "history_export_completed", "export_job", job.ID, "success",
+		"user_id", payload.UserID,
+		"format", job.Format,
+		"rows", rowCount,
+		"file_size", fileSize,
+		"duration_ms", completedAt.Sub(startedAt).Milliseconds(),
+	)
+
+	// 顺带清理过期的导出文件
+	if removed, err := CleanupExpiredExports(p.db, p.exportDir, completedAt); err != nil {
+		p.logger.Error(ctx, "Failed to cleanup expired exports", "error", err)
+	} else if removed > 0 {
+		p.logger.Info(ctx, "Removed expired export files", "count", removed)
+	}
+
+	return nil
+}
+
+// writeHistoryExport 按任务参数写出导出文件，返回记录数和文件大小
+func (p *ApifyTaskProcessor) writeHistoryExport(job *models.ExportJob) (int, int64, HistoryExportParams, error) {
+	var params HistoryExportParams
+	if err := json.Unmarshal(job.Params, &params); err != nil {
+		return 0, 0, params, fmt.Errorf("failed to decode export params: %w", err)
+	}
+
+	products, err := export.LoadProducts(p.db, job.WorkspaceID, params.TrackedIDs, params.TagIDs)
+	if err != nil {
+		return 0, 0, params, fmt.Errorf("failed to load tracked products: %w", err)
+	}
+
+	if err := os.MkdirAll(p.exportDir, 0o755); err != nil {
+		return 0, 0, params, fmt.Errorf("failed to create export dir: %w", err)
+	}
+	path := ExportFilePath(p.exportDir, job.ID, job.Format)
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, 0, params, fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer os.Remove(tmpPath)
+
+	writer, err := export.NewWriter(job.Format, file)
+	if err != nil {
+		file.Close()
+		return 0, 0, params, err
+	}
+
+	rowCount, err := export.Export(p.db, writer, export.Options{
+		Products:  products,
+		Metrics:   params.Metrics,
+		From:      params.From,
+		To:        params.To,
+		Currency:  params.Currency,
+		Converter: p.fxConverter,
+	})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, 0, params, err
+	}
+
+	info, err := os.Stat(tmpPath)
+	if err != nil {
+		return 0, 0, params, fmt.Errorf("failed to stat export file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, 0, params, fmt.Errorf("failed to move export file: %w", err)
+	}
+
+	return rowCount, info.Size(), params, nil
+}
+
+// CleanupExpiredExports 删除已过期的导出文件并标记任务为 expired，返回处理的任务数
+func CleanupExpiredExports(db *gorm.DB, dir string, now time.Time) (int, error) {
+	var jobs []models.ExportJob
+	if err := db.Select("id, format").
+		Where("status = ? AND expires_at < ?", "completed", now).
+		Find(&jobs).Error; err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs {
+		if err := os.Remove(ExportFilePath(dir, job.ID, job.Format)); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+		if err := db.Model(&models.ExportJob{}).Where("id = ?", job.ID).Update("status", "expired").Error; err != nil {
+			return 0, err
+		}
+	}
+	return len(jobs), nil
+}
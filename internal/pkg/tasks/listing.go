@@ -0,0 +1,13 @@
+package tasks
+
+import "amazonpilot/internal/pkg/estimation"
+
+// getSeverityForListingChange 标题或五点描述被改写视为 warning，其余为 info
+func getSeverityForListingChange(diffs []estimation.ListingFieldDiff) string {
+	for _, diff := range diffs {
+		if diff.Field == estimation.ListingFieldTitle || diff.Field == estimation.ListingFieldBulletPoints {
+			return "warning"
+		}
+	}
+	return "info"
+}
@@ -0,0 +1,19 @@
+package tasks
+
+import (
+	"testing"
+
+	"amazonpilot/internal/pkg/estimation"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSeverityForListingChange(t *testing.T) {
+	assert.Equal(t, "warning", getSeverityForListingChange([]estimation.ListingFieldDiff{
+		{Field: estimation.ListingFieldImages},
+		{Field: estimation.ListingFieldBulletPoints},
+	}))
+	assert.Equal(t, "info", getSeverityForListingChange([]estimation.ListingFieldDiff{
+		{Field: estimation.ListingFieldDescription},
+	}))
+}
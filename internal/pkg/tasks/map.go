@@ -0,0 +1,67 @@
+package tasks
+
+import (
+	"math"
+
+	"amazonpilot/internal/pkg/apify"
+	"amazonpilot/internal/pkg/models"
+)
+
+// MAPViolation 低于最低广告价的报价
+type MAPViolation struct {
+	Offer           models.OfferHistory
+	MAPPrice        float64
+	DiscountPercent float64 // 低于 MAP 的百分比
+}
+
+// FindMAPViolations 返回价格低于 MAP 的报价（按商品价格比较，不含运费）
+// 报价列表中没有 Buy Box 赢家时，额外检查产品详情中的 Buy Box 价格
+func FindMAPViolations(offers []models.OfferHistory, data *apify.ProductData, mapPrice float64) []MAPViolation {
+	if mapPrice <= 0 {
+		return nil
+	}
+
+	violations := []MAPViolation{}
+	hasWinner := false
+	for _, offer := range offers {
+		if offer.IsBuyBoxWinner {
+			hasWinner = true
+		}
+		if offer.Price > 0 && offer.Price < mapPrice {
+			violations = append(violations, newMAPViolation(offer, mapPrice))
+		}
+	}
+
+	if !hasWinner && data.BuyBoxPrice != nil && *data.BuyBoxPrice > 0 && *data.BuyBoxPrice < mapPrice && len(offers) > 0 {
+		buyBox := models.OfferHistory{
+			ProductID:      offers[0].ProductID,
+			SellerName:     data.Seller,
+			Price:          *data.BuyBoxPrice,
+			Currency:       offers[0].Currency,
+			IsBuyBoxWinner: true,
+			RecordedAt:     offers[0].RecordedAt,
+		}
+		if data.SellerID != "" {
+			buyBox.SellerID = &data.SellerID
+		}
+		violations = append(violations, newMAPViolation(buyBox, mapPrice))
+	}
+
+	return violations
+}
+
+func newMAPViolation(offer models.OfferHistory, mapPrice float64) MAPViolation {
+	return MAPViolation{
+		Offer:           offer,
+		MAPPrice:        mapPrice,
+		DiscountPercent: math.Round((mapPrice-offer.Price)/mapPrice*10000) / 100,
+	}
+}
+
+// getSeverityForMAPViolation 低于 MAP 10% 及以上视为 critical
+func getSeverityForMAPViolation(discountPercent float64) string {
+	if discountPercent >= 10 {
+		return "critical"
+	}
+	return "warning"
+}
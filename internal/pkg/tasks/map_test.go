@@ -0,0 +1,39 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"amazonpilot/internal/pkg/apify"
+	"amazonpilot/internal/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindMAPViolations(t *testing.T) {
+	now := time.Now()
+	offers := []models.OfferHistory{
+		{SellerName: "AnkerDirect", Price: 29.99, Currency: "USD", RecordedAt: now},
+		{SellerName: "Cheap Deals", Price: 26.99, Currency: "USD", RecordedAt: now},
+		{SellerName: "Discounter", Price: 29.50, Currency: "USD", RecordedAt: now},
+	}
+	data := &apify.ProductData{Seller: "AnkerDirect"}
+
+	// 未配置 MAP
+	assert.Empty(t, FindMAPViolations(offers, data, 0))
+
+	violations := FindMAPViolations(offers, data, 29.99)
+	assert.Len(t, violations, 2)
+	assert.Equal(t, "Cheap Deals", violations[0].Offer.SellerName)
+	assert.Equal(t, 10.0, violations[0].DiscountPercent)
+	assert.Equal(t, "critical", getSeverityForMAPViolation(violations[0].DiscountPercent))
+	assert.Equal(t, "warning", getSeverityForMAPViolation(violations[1].DiscountPercent))
+
+	// 报价中没有 Buy Box 赢家时检查 Buy Box 价格
+	buyBoxPrice := 27.49
+	data.BuyBoxPrice = &buyBoxPrice
+	violations = FindMAPViolations(offers[:1], data, 29.99)
+	assert.Len(t, violations, 1)
+	assert.True(t, violations[0].Offer.IsBuyBoxWinner)
+	assert.Equal(t, 27.49, violations[0].Offer.Price)
+}
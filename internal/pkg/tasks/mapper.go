@@ -2,6 +2,11 @@ package tasks
 
 import (
 	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
 	"amazonpilot/internal/pkg/apify"
 )
 
@@ -39,6 +44,117 @@ func MapApifyDataToProduct(data *apify.ProductData, rawJSON []byte) map[string]i
 		updates["images"] = imagesJSON
 	}
 
+	// 映射变体信息
+	if parentASIN := VariationParentASIN(data); parentASIN != "" {
+		updates["parent_asin"] = parentASIN
+		attributesJSON, _ := json.Marshal(VariationAttributes(data))
+		updates["variation_attributes"] = attributesJSON
+	}
+
 	return updates
 }
 
+// DealInfo 促销信息
+type DealInfo struct {
+	IsOnSale           bool
+	DiscountPercentage *float64 // 相对标价的折扣百分比
+	ListPrice          *float64
+	DealType           string // 标准化的秒杀/活动类型，没有活动时为空
+	CouponText         string
+}
+
+// ExtractDealInfo 计算相对标价 (retailPrice) 的折扣，并提取优惠券和活动标识
+// 标价缺失时退回到 priceSaving (例如 "-53%")
+func ExtractDealInfo(data *apify.ProductData) DealInfo {
+	info := DealInfo{
+		DealType:   NormalizeDealType(data.DealBadge, data.Deal),
+		CouponText: strings.TrimSpace(data.Coupon),
+	}
+
+	if data.RetailPrice > 0 {
+		listPrice := data.RetailPrice
+		info.ListPrice = &listPrice
+	}
+
+	discount := 0.0
+	if data.RetailPrice > 0 && data.Price > 0 && data.Price < data.RetailPrice {
+		discount = math.Round((data.RetailPrice-data.Price)/data.RetailPrice*10000) / 100
+	} else if saving := strings.Trim(strings.TrimSpace(data.PriceSaving), "-%"); saving != "" {
+		if parsed, err := strconv.ParseFloat(saving, 64); err == nil && parsed > 0 && parsed < 100 {
+			discount = parsed
+		}
+	}
+	if discount > 0 {
+		info.DiscountPercentage = &discount
+	}
+
+	info.IsOnSale = discount > 0 || info.DealType != "" || info.CouponText != ""
+	return info
+}
+
+// NormalizeDealType 将活动标识标准化，例如 "Lightning Deal" -> lightning_deal
+func NormalizeDealType(badge string, deal bool) string {
+	normalized := strings.ToLower(strings.TrimSpace(badge))
+	switch {
+	case strings.Contains(normalized, "lightning"):
+		return "lightning_deal"
+	case strings.Contains(normalized, "prime day"), strings.Contains(normalized, "prime big deal"), strings.Contains(normalized, "prime exclusive"):
+		return "prime_day"
+	case strings.Contains(normalized, "deal of the day"):
+		return "deal_of_the_day"
+	case strings.Contains(normalized, "limited time"):
+		return "limited_time_deal"
+	case normalized != "" || deal:
+		return "deal"
+	}
+	return ""
+}
+
+// VariationParentASIN 计算变体家族的父ASIN
+// actor 返回 parentAsin 时直接使用；否则取家族中最小的ASIN作为稳定的分组键，
+// 这样无论从哪个子ASIN抓取，同一家族都会得到相同的值
+func VariationParentASIN(data *apify.ProductData) string {
+	if data.ParentASIN != "" {
+		return data.ParentASIN
+	}
+
+	family := VariationChildASINs(data)
+	if len(family) == 0 {
+		return ""
+	}
+
+	family = append(family, data.ASIN)
+	sort.Strings(family)
+	return family[0]
+}
+
+// VariationChildASINs 返回同一家族中除自身以外的所有子ASIN（去重）
+func VariationChildASINs(data *apify.ProductData) []string {
+	seen := map[string]bool{data.ASIN: true}
+	children := []string{}
+	for _, variation := range data.Variations {
+		for _, value := range variation.Values {
+			if value.ASIN == "" || seen[value.ASIN] {
+				continue
+			}
+			seen[value.ASIN] = true
+			children = append(children, value.ASIN)
+		}
+	}
+	return children
+}
+
+// VariationAttributes 返回当前ASIN的变体属性，例如 {"color_name": "Black"}
+func VariationAttributes(data *apify.ProductData) map[string]string {
+	attributes := make(map[string]string, len(data.Variations))
+	for _, variation := range data.Variations {
+		for _, value := range variation.Values {
+			if value.Selected || value.ASIN == data.ASIN {
+				attributes[variation.VariationName] = value.Value
+				break
+			}
+		}
+	}
+	return attributes
+}
+
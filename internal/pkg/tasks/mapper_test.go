@@ -171,4 +171,61 @@ func TestMapApifyDataToProduct_CompleteData(t *testing.T) {
 	var images []string
 	json.Unmarshal(imagesJSON, &images)
 	assert.Equal(t, 3, len(images))
-}
\ No newline at end of file
+}
+
+func TestVariationFamily(t *testing.T) {
+	data := &apify.ProductData{
+		ASIN: "B0D2XRXNGY",
+		Variations: []apify.Variation{
+			{
+				VariationName: "color_name",
+				Values: []apify.VariationValue{
+					{Value: "Black", Selected: true, ASIN: "B0D2XRXNGY"},
+					{Value: "Blue", ASIN: "B0D31C567W"},
+					{Value: "White", ASIN: "B0D319J6T2"},
+				},
+			},
+		},
+	}
+
+	// 没有 parentAsin 时使用家族中最小的ASIN作为分组键
+	assert.Equal(t, "B0D2XRXNGY", VariationParentASIN(data))
+	assert.ElementsMatch(t, []string{"B0D31C567W", "B0D319J6T2"}, VariationChildASINs(data))
+	assert.Equal(t, map[string]string{"color_name": "Black"}, VariationAttributes(data))
+
+	updates := MapApifyDataToProduct(data, nil)
+	assert.Equal(t, "B0D2XRXNGY", updates["parent_asin"])
+
+	// actor 返回 parentAsin 时优先使用
+	data.ParentASIN = "B0PARENT01"
+	assert.Equal(t, "B0PARENT01", VariationParentASIN(data))
+
+	// 没有变体的产品不设置父ASIN
+	single := &apify.ProductData{ASIN: "B08N5WRWNW"}
+	assert.Equal(t, "", VariationParentASIN(single))
+	_, ok := MapApifyDataToProduct(single, nil)["parent_asin"]
+	assert.False(t, ok)
+}
+
+func TestExtractDealInfo(t *testing.T) {
+	info := ExtractDealInfo(&apify.ProductData{Price: 29.99, RetailPrice: 39.99, DealBadge: "Lightning Deal", Coupon: " Save 5% with coupon "})
+	assert.True(t, info.IsOnSale)
+	assert.Equal(t, 25.01, *info.DiscountPercentage)
+	assert.Equal(t, 39.99, *info.ListPrice)
+	assert.Equal(t, "lightning_deal", info.DealType)
+	assert.Equal(t, "Save 5% with coupon", info.CouponText)
+
+	// 没有标价时使用 priceSaving
+	info = ExtractDealInfo(&apify.ProductData{Price: 19.99, PriceSaving: "-20%"})
+	assert.True(t, info.IsOnSale)
+	assert.Equal(t, 20.0, *info.DiscountPercentage)
+	assert.Nil(t, info.ListPrice)
+
+	info = ExtractDealInfo(&apify.ProductData{Price: 19.99, RetailPrice: 19.99})
+	assert.False(t, info.IsOnSale)
+	assert.Nil(t, info.DiscountPercentage)
+
+	assert.Equal(t, "prime_day", NormalizeDealType("Prime Big Deal", true))
+	assert.Equal(t, "deal", NormalizeDealType("", true))
+	assert.Equal(t, "", NormalizeDealType("", false))
+}
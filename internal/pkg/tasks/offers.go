@@ -0,0 +1,72 @@
+package tasks
+
+import (
+	"strings"
+	"time"
+
+	"amazonpilot/internal/pkg/apify"
+	"amazonpilot/internal/pkg/models"
+)
+
+// BuildOfferHistory 将抓取到的报价转换为报价快照记录
+// 未抓取到完整报价列表时，使用产品详情中的 Buy Box 卖家作为唯一报价
+func BuildOfferHistory(productID string, data *apify.ProductData, recordedAt time.Time) []models.OfferHistory {
+	offers := data.Offers
+	if len(offers) == 0 {
+		if data.Seller == "" || data.Price <= 0 {
+			return nil
+		}
+		offers = []apify.Offer{{
+			SellerName:    data.Seller,
+			SellerID:      data.SellerID,
+			Price:         data.Price,
+			ShippingPrice: data.ShippingPrice,
+			Currency:      data.Currency,
+			Condition:     "new",
+			FBA:           data.FulfilledBy == "Amazon",
+			Prime:         data.Prime,
+			BuyBoxWinner:  true,
+		}}
+	}
+
+	history := make([]models.OfferHistory, 0, len(offers))
+	for _, offer := range offers {
+		if offer.SellerName == "" || offer.Price <= 0 {
+			continue
+		}
+
+		currency := offer.Currency
+		if currency == "" {
+			currency = data.Currency
+		}
+		if currency == "" {
+			currency = "USD"
+		}
+
+		condition := strings.ToLower(strings.TrimSpace(offer.Condition))
+		if condition == "" {
+			condition = "new"
+		}
+
+		record := models.OfferHistory{
+			ProductID:      productID,
+			SellerName:     offer.SellerName,
+			Price:          offer.Price,
+			ShippingPrice:  offer.ShippingPrice,
+			Currency:       currency,
+			Condition:      condition,
+			IsFBA:          offer.FBA,
+			IsPrime:        offer.Prime,
+			IsBuyBoxWinner: offer.BuyBoxWinner,
+			RecordedAt:     recordedAt,
+			DataSource:     "apify",
+		}
+		if offer.SellerID != "" {
+			sellerID := offer.SellerID
+			record.SellerID = &sellerID
+		}
+		history = append(history, record)
+	}
+
+	return history
+}
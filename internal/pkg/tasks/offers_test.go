@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"amazonpilot/internal/pkg/apify"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildOfferHistory(t *testing.T) {
+	now := time.Now()
+
+	// 没有完整报价列表时使用 Buy Box 卖家
+	data := &apify.ProductData{
+		Price:       23.49,
+		Currency:    "USD",
+		Seller:      "AnkerDirect",
+		SellerID:    "A294P4X9EWVXLJ",
+		FulfilledBy: "Amazon",
+		Prime:       true,
+	}
+	offers := BuildOfferHistory("product-1", data, now)
+	assert.Len(t, offers, 1)
+	assert.Equal(t, "AnkerDirect", offers[0].SellerName)
+	assert.Equal(t, "A294P4X9EWVXLJ", *offers[0].SellerID)
+	assert.True(t, offers[0].IsFBA)
+	assert.True(t, offers[0].IsBuyBoxWinner)
+	assert.Equal(t, "new", offers[0].Condition)
+
+	// 完整报价列表，跳过无效报价
+	data.Offers = []apify.Offer{
+		{SellerName: "AnkerDirect", SellerID: "A294P4X9EWVXLJ", Price: 23.49, FBA: true, BuyBoxWinner: true},
+		{SellerName: "Reseller", Price: 21.99, ShippingPrice: 4.99, Condition: "Used - Like New"},
+		{SellerName: "", Price: 19.99},
+	}
+	offers = BuildOfferHistory("product-1", data, now)
+	assert.Len(t, offers, 2)
+	assert.Nil(t, offers[1].SellerID)
+	assert.Equal(t, "used - like new", offers[1].Condition)
+	assert.Equal(t, "USD", offers[1].Currency)
+	assert.InDelta(t, 26.98, offers[1].LandedPrice(), 0.001)
+	assert.Equal(t, now, offers[1].RecordedAt)
+}
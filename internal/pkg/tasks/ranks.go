@@ -0,0 +1,90 @@
+package tasks
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"amazonpilot/internal/pkg/apify"
+	"amazonpilot/internal/pkg/models"
+)
+
+// bsrTextPattern 匹配页面排名文本中的 "#1,234 in Home & Kitchen"
+var bsrTextPattern = regexp.MustCompile(`#\s*([\d,.]+)\s+in\s+([^#(]+)`)
+
+// ParseCategoryRanks 合并 actor 返回的类目排名列表、页面排名文本和主排名 (salesRank)，按类目去重
+// 主排名所在类目（没有时为第一个类目）标记为根类目，并排在第一位
+func ParseCategoryRanks(data *apify.ProductData) []models.CategoryRank {
+	ranks := []models.CategoryRank{}
+	seen := map[string]int{}
+	add := func(category string, rank int) {
+		category = strings.TrimSpace(category)
+		if category == "" || rank <= 0 {
+			return
+		}
+		key := strings.ToLower(category)
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = len(ranks)
+		ranks = append(ranks, models.CategoryRank{Category: category, Rank: rank})
+	}
+
+	add(data.BSRCategory, data.BSR)
+	for _, rank := range data.CategoryRanks {
+		add(rank.Category, rank.Rank)
+	}
+	for _, match := range bsrTextPattern.FindAllStringSubmatch(data.BSRText, -1) {
+		rank, err := strconv.Atoi(strings.NewReplacer(",", "", ".", "").Replace(match[1]))
+		if err != nil {
+			continue
+		}
+		add(match[2], rank)
+	}
+
+	if len(ranks) > 0 {
+		ranks[0].IsRoot = true
+	}
+	return ranks
+}
+
+// CategoryRankChange 小类目排名变化
+type CategoryRankChange struct {
+	Category         string
+	OldRank          int
+	NewRank          int
+	ChangePercentage float64
+}
+
+// FindSubCategoryRankChanges 比较两次快照的小类目排名，返回变化超过阈值的类目（不含根类目）
+func FindSubCategoryRankChanges(previous, current []models.CategoryRank, threshold float64) []CategoryRankChange {
+	previousRanks := make(map[string]int, len(previous))
+	for _, rank := range previous {
+		if !rank.IsRoot {
+			previousRanks[strings.ToLower(rank.Category)] = rank.Rank
+		}
+	}
+
+	changes := []CategoryRankChange{}
+	for _, rank := range current {
+		if rank.IsRoot {
+			continue
+		}
+		oldRank, ok := previousRanks[strings.ToLower(rank.Category)]
+		if !ok || oldRank <= 0 || rank.Rank <= 0 {
+			continue
+		}
+
+		changePercentage := math.Abs(float64(rank.Rank-oldRank)/float64(oldRank)) * 100
+		if changePercentage > threshold {
+			changes = append(changes, CategoryRankChange{
+				Category:         rank.Category,
+				OldRank:          oldRank,
+				NewRank:          rank.Rank,
+				ChangePercentage: changePercentage,
+			})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,60 @@
+package tasks
+
+import (
+	"testing"
+
+	"amazonpilot/internal/pkg/apify"
+	"amazonpilot/internal/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCategoryRanks(t *testing.T) {
+	data := &apify.ProductData{
+		BSR:         1234,
+		BSRCategory: "Home & Kitchen",
+		CategoryRanks: []apify.CategoryRank{
+			{Rank: 1234, Category: "Home & Kitchen"},
+			{Rank: 5, Category: "Kitchen Utensil Sets"},
+		},
+		BSRText: "#1,234 in Home & Kitchen (See Top 100 in Home & Kitchen) #5 in Kitchen Utensil Sets #17 in Spatulas",
+	}
+
+	ranks := ParseCategoryRanks(data)
+	assert.Len(t, ranks, 3)
+	assert.True(t, ranks[0].IsRoot)
+	assert.Equal(t, "Home & Kitchen", ranks[0].Category)
+	assert.Equal(t, 1234, ranks[0].Rank)
+	assert.False(t, ranks[1].IsRoot)
+	assert.Equal(t, "Spatulas", ranks[2].Category)
+	assert.Equal(t, 17, ranks[2].Rank)
+
+	// 只有排名文本时第一个类目为根类目
+	ranks = ParseCategoryRanks(&apify.ProductData{BSRText: "#2.345 in Küche, Haushalt & Wohnen #12 in Pfannenwender"})
+	assert.Len(t, ranks, 2)
+	assert.True(t, ranks[0].IsRoot)
+	assert.Equal(t, 2345, ranks[0].Rank)
+
+	assert.Empty(t, ParseCategoryRanks(&apify.ProductData{}))
+}
+
+func TestFindSubCategoryRankChanges(t *testing.T) {
+	previous := []models.CategoryRank{
+		{Category: "Home & Kitchen", Rank: 1000, IsRoot: true},
+		{Category: "Kitchen Utensil Sets", Rank: 10},
+		{Category: "Spatulas", Rank: 20},
+	}
+	current := []models.CategoryRank{
+		{Category: "Home & Kitchen", Rank: 2000, IsRoot: true},
+		{Category: "kitchen utensil sets", Rank: 15},
+		{Category: "Spatulas", Rank: 22},
+		{Category: "Turners", Rank: 3},
+	}
+
+	changes := FindSubCategoryRankChanges(previous, current, 30)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "kitchen utensil sets", changes[0].Category)
+	assert.Equal(t, 10, changes[0].OldRank)
+	assert.Equal(t, 15, changes[0].NewRank)
+	assert.Equal(t, 50.0, changes[0].ChangePercentage)
+}
@@ -0,0 +1,98 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"amazonpilot/internal/pkg/utils"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+)
+
+// 刷新任务所在队列，完成后任务和结果在 asynq 中保留的时间 (用于查询任务状态和最近刷新记录)
+const (
+	RefreshTaskQueue     = "default"
+	RefreshTaskRetention = 24 * time.Hour
+)
+
+// 每个追踪产品最近刷新任务ID的索引 (ZSET，score 为入队时间)，查询最近刷新记录时只读取这些任务
+// 索引只保留最近 RefreshTaskIndexSize 个任务；延迟执行或重试中的任务可能晚于入队很久才结束，因此过期时间比任务保留期长
+const (
+	refreshTaskIndexPrefix = "amazon_pilot:refresh_tasks:"
+	RefreshTaskIndexSize   = 100
+	refreshTaskIndexTTL    = 7 * 24 * time.Hour
+)
+
+// RefreshProductDataResult 刷新任务结果，写入 asynq 任务结果并随任务保留
+type RefreshProductDataResult struct {
+	Price        float64   `json:"price"`
+	Currency     string    `json:"currency"`
+	BSR          int       `json:"bsr,omitempty"`
+	Rating       float64   `json:"rating,omitempty"`
+	ReviewCount  int       `json:"review_count"`
+	Availability string    `json:"availability"`
+	Offers       int       `json:"offers"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// NewRefreshProductDataTask 创建产品数据刷新任务，opts 追加在默认队列和保留时间之后
+func NewRefreshProductDataTask(payload RefreshProductDataPayload, opts ...asynq.Option) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]asynq.Option{asynq.Queue(RefreshTaskQueue), asynq.Retention(RefreshTaskRetention)}, opts...)
+	return asynq.NewTask(TypeRefreshProductData, data, opts...), nil
+}
+
+// EnqueueRefreshTask 发送产品数据刷新任务，并将任务ID记入追踪产品的最近刷新索引
+// 索引写入失败只记录日志，不影响已入队的任务
+func EnqueueRefreshTask(ctx context.Context, client *asynq.Client, rdb *redis.Client, payload RefreshProductDataPayload, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	task, err := NewRefreshProductDataTask(payload, opts...)
+	if err != nil {
+		return nil, err
+	}
+	info, err := client.Enqueue(task)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := RecordRefreshTask(ctx, rdb, payload.TrackedID, info.ID, time.Now()); err != nil {
+		utils.LogError(ctx, "Failed to index refresh task", "tracked_id", payload.TrackedID, "task_id", info.ID, "error", err)
+	}
+	return info, nil
+}
+
+// RecordRefreshTask 记录追踪产品的刷新任务ID，超出 RefreshTaskIndexSize 的最旧记录被移除
+func RecordRefreshTask(ctx context.Context, rdb *redis.Client, trackedID, taskID string, enqueuedAt time.Time) error {
+	key := refreshTaskIndexPrefix + trackedID
+	pipe := rdb.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(enqueuedAt.UnixMilli()), Member: taskID})
+	pipe.ZRemRangeByRank(ctx, key, 0, -RefreshTaskIndexSize-1)
+	pipe.Expire(ctx, key, refreshTaskIndexTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RecentRefreshTaskIDs 返回追踪产品最近入队的刷新任务ID，新的在前
+func RecentRefreshTaskIDs(ctx context.Context, rdb *redis.Client, trackedID string) ([]string, error) {
+	ids, err := rdb.ZRevRange(ctx, refreshTaskIndexPrefix+trackedID, 0, RefreshTaskIndexSize-1).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return ids, err
+}
+
+// NextCheckTime 按追踪频率计算下次定时刷新的时间，未知频率按每天处理
+func NextCheckTime(frequency string, now time.Time) time.Time {
+	switch frequency {
+	case "hourly":
+		return now.Add(time.Hour)
+	case "weekly":
+		return now.Add(7 * 24 * time.Hour)
+	default:
+		return now.Add(24 * time.Hour)
+	}
+}
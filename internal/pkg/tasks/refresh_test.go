@@ -0,0 +1,41 @@
+package tasks
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRefreshProductDataTask(t *testing.T) {
+	payload := RefreshProductDataPayload{
+		ProductID:    "p1",
+		TrackedID:    "t1",
+		ASIN:         "B08N5WRWNW",
+		UserID:       "u1",
+		RequestedAt:  "2025-03-01T08:00:00Z",
+		InitialFetch: true,
+	}
+
+	task, err := NewRefreshProductDataTask(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, TypeRefreshProductData, task.Type())
+
+	var decoded RefreshProductDataPayload
+	assert.NoError(t, json.Unmarshal(task.Payload(), &decoded))
+	assert.Equal(t, payload, decoded)
+
+	// 非初始获取时不输出 initial_fetch
+	payload.InitialFetch = false
+	task, _ = NewRefreshProductDataTask(payload)
+	assert.NotContains(t, string(task.Payload()), "initial_fetch")
+}
+
+func TestNextCheckTime(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, now.Add(time.Hour), NextCheckTime("hourly", now))
+	assert.Equal(t, now.Add(24*time.Hour), NextCheckTime("daily", now))
+	assert.Equal(t, now.Add(7*24*time.Hour), NextCheckTime("weekly", now))
+	assert.Equal(t, now.Add(24*time.Hour), NextCheckTime("", now)) // 未知频率按每天处理
+}
@@ -0,0 +1,87 @@
+package tasks
+
+import (
+	"math"
+	"time"
+
+	"amazonpilot/internal/pkg/estimation"
+	"amazonpilot/internal/pkg/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BuildLatestSnapshot 根据本次刷新写入的价格、排名和库存记录生成最新快照
+// dayAgoPrice / dayAgoRanking 为24小时前最后一条记录，为 nil 时不计算对应的变化
+func BuildLatestSnapshot(price models.PriceHistory, ranking models.RankingHistory, availability models.AvailabilityHistory, dayAgoPrice *models.PriceHistory, dayAgoRanking *models.RankingHistory) models.LatestSnapshot {
+	snapshot := models.LatestSnapshot{
+		ProductID:         price.ProductID,
+		Price:             price.Price,
+		Currency:          price.Currency,
+		BuyBoxPrice:       price.BuyBoxPrice,
+		BSRRank:           ranking.BSRRank,
+		Rating:            ranking.Rating,
+		ReviewCount:       ranking.ReviewCount,
+		AvailabilityState: availability.State,
+		AvailabilityText:  availability.AvailabilityText,
+		RecordedAt:        price.RecordedAt,
+	}
+	if ranking.Category != "" {
+		category := ranking.Category
+		snapshot.BSRCategory = &category
+	}
+	if snapshot.AvailabilityState == "" {
+		snapshot.AvailabilityState = estimation.AvailabilityUnknown
+	}
+
+	if dayAgoPrice != nil && dayAgoPrice.Price > 0 && price.Price > 0 {
+		change := math.Round((price.Price-dayAgoPrice.Price)/dayAgoPrice.Price*10000) / 100
+		snapshot.PriceChange24h = &change
+	}
+	if dayAgoRanking != nil {
+		if ranking.BSRRank != nil && dayAgoRanking.BSRRank != nil && *ranking.BSRRank > 0 && *dayAgoRanking.BSRRank > 0 {
+			change := *ranking.BSRRank - *dayAgoRanking.BSRRank
+			snapshot.BSRChange24h = &change
+		}
+		if ranking.Rating != nil && dayAgoRanking.Rating != nil {
+			change := math.Round((*ranking.Rating-*dayAgoRanking.Rating)*100) / 100
+			snapshot.RatingChange24h = &change
+		}
+		change := ranking.ReviewCount - dayAgoRanking.ReviewCount
+		snapshot.ReviewCountChange24h = &change
+	}
+	return snapshot
+}
+
+// upsertLatestSnapshot 在刷新事务中写入产品最新快照，24小时变化以事务内的历史记录为准
+func upsertLatestSnapshot(tx *gorm.DB, price models.PriceHistory, ranking models.RankingHistory, availability models.AvailabilityHistory, now time.Time) error {
+	dayAgo := now.Add(-24 * time.Hour)
+
+	var dayAgoPrice *models.PriceHistory
+	var priceRecord models.PriceHistory
+	err := tx.Where("product_id = ? AND recorded_at <= ?", price.ProductID, dayAgo).
+		Order("recorded_at DESC").
+		First(&priceRecord).Error
+	if err == nil {
+		dayAgoPrice = &priceRecord
+	} else if err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	var dayAgoRanking *models.RankingHistory
+	var rankingRecord models.RankingHistory
+	err = tx.Where("product_id = ? AND recorded_at <= ?", ranking.ProductID, dayAgo).
+		Order("recorded_at DESC").
+		First(&rankingRecord).Error
+	if err == nil {
+		dayAgoRanking = &rankingRecord
+	} else if err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	snapshot := BuildLatestSnapshot(price, ranking, availability, dayAgoPrice, dayAgoRanking)
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}},
+		UpdateAll: true,
+	}).Create(&snapshot).Error
+}
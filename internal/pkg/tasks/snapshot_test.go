@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"amazonpilot/internal/pkg/estimation"
+	"amazonpilot/internal/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildLatestSnapshot(t *testing.T) {
+	now := time.Date(2025, 3, 2, 8, 0, 0, 0, time.UTC)
+	bsr, dayAgoBSR := 120, 100
+	rating, dayAgoRating := 4.4, 4.5
+	buyBox := 21.99
+	text := "Only 3 left in stock"
+
+	price := models.PriceHistory{ProductID: "p1", Price: 22, Currency: "USD", BuyBoxPrice: &buyBox, RecordedAt: now}
+	ranking := models.RankingHistory{ProductID: "p1", Category: "Kitchen", BSRRank: &bsr, Rating: &rating, ReviewCount: 130}
+	availability := models.AvailabilityHistory{ProductID: "p1", State: estimation.AvailabilityLowStock, AvailabilityText: &text}
+
+	// 24小时前没有数据时不计算变化
+	snapshot := BuildLatestSnapshot(price, ranking, availability, nil, nil)
+	assert.Equal(t, "p1", snapshot.ProductID)
+	assert.Equal(t, 22.0, snapshot.Price)
+	assert.Equal(t, 21.99, *snapshot.BuyBoxPrice)
+	assert.Equal(t, 120, *snapshot.BSRRank)
+	assert.Equal(t, "Kitchen", *snapshot.BSRCategory)
+	assert.Equal(t, estimation.AvailabilityLowStock, snapshot.AvailabilityState)
+	assert.Equal(t, now, snapshot.RecordedAt)
+	assert.Nil(t, snapshot.PriceChange24h)
+	assert.Nil(t, snapshot.BSRChange24h)
+	assert.Nil(t, snapshot.ReviewCountChange24h)
+
+	dayAgoPrice := models.PriceHistory{Price: 20}
+	dayAgoRanking := models.RankingHistory{BSRRank: &dayAgoBSR, Rating: &dayAgoRating, ReviewCount: 100}
+	snapshot = BuildLatestSnapshot(price, ranking, models.AvailabilityHistory{}, &dayAgoPrice, &dayAgoRanking)
+	assert.Equal(t, 10.0, *snapshot.PriceChange24h)
+	assert.Equal(t, 20, *snapshot.BSRChange24h)
+	assert.Equal(t, -0.1, *snapshot.RatingChange24h)
+	assert.Equal(t, 30, *snapshot.ReviewCountChange24h)
+	assert.Equal(t, estimation.AvailabilityUnknown, snapshot.AvailabilityState)
+}
@@ -0,0 +1,106 @@
+package timeseries
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 多序列对比的归一化方式
+const (
+	NormalizeNone      = "none"
+	NormalizeIndex     = "index"      // 以第一个值为 100
+	NormalizePctChange = "pct_change" // 相对第一个值的百分比变化
+)
+
+// ParseNormalize 校验归一化方式，为空时不归一化
+func ParseNormalize(mode string) (string, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	switch mode {
+	case "":
+		return NormalizeNone, nil
+	case NormalizeNone, NormalizeIndex, NormalizePctChange:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("normalize must be one of none, index, pct_change")
+	}
+}
+
+// Align 将多个已分桶序列对齐到共同的桶开始时间（所有序列桶的并集，升序）
+// 每个序列在第一个数据点之前为 nil，之后缺失的桶沿用上一个桶的值
+func Align(series [][]Bucket, agg string) ([]time.Time, [][]*float64) {
+	seen := map[int64]bool{}
+	starts := []time.Time{}
+	for _, buckets := range series {
+		for _, b := range buckets {
+			if key := b.Start.UnixNano(); !seen[key] {
+				seen[key] = true
+				starts = append(starts, b.Start)
+			}
+		}
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	values := make([][]*float64, len(series))
+	for i, buckets := range series {
+		values[i] = make([]*float64, len(starts))
+		next := 0
+		var last *float64
+		for j, start := range starts {
+			if next < len(buckets) && buckets[next].Start.Equal(start) {
+				v := buckets[next].Value(agg)
+				last = &v
+				next++
+			}
+			values[i][j] = last
+		}
+	}
+	return starts, values
+}
+
+// CommonBase 返回所有序列都有数据的第一个桶下标，作为对比归一化的共同基准
+// 没有这样的桶时返回起始桶 0
+func CommonBase(values [][]*float64) int {
+	if len(values) == 0 {
+		return 0
+	}
+	for j := range values[0] {
+		complete := true
+		for _, series := range values {
+			if j >= len(series) || series[j] == nil {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			return j
+		}
+	}
+	return 0
+}
+
+// Normalize 以 base 桶的值为基准归一化序列，base 之前的桶为 nil；基准值为空或 0 时无法归一化，全部返回 nil
+func Normalize(values []*float64, base int, mode string) []*float64 {
+	if mode != NormalizeIndex && mode != NormalizePctChange {
+		return values
+	}
+
+	result := make([]*float64, len(values))
+	if base < 0 || base >= len(values) || values[base] == nil || *values[base] == 0 {
+		return result
+	}
+	baseValue := *values[base]
+	for i := base; i < len(values); i++ {
+		if values[i] == nil {
+			continue
+		}
+		n := *values[i] / baseValue * 100
+		if mode == NormalizePctChange {
+			n -= 100
+		}
+		n = round4(n)
+		result[i] = &n
+	}
+	return result
+}
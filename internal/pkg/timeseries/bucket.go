@@ -0,0 +1,157 @@
+package timeseries
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	_ "time/tzdata" // 容器镜像中可能没有时区数据库
+)
+
+// 分桶粒度
+const (
+	BucketRaw   = "raw" // 不分桶，返回原始数据点
+	BucketHour  = "hour"
+	BucketDay   = "day"
+	BucketWeek  = "week" // 周一开始
+	BucketMonth = "month"
+)
+
+// 聚合方式
+const (
+	AggLast = "last"
+	AggMin  = "min"
+	AggMax  = "max"
+	AggAvg  = "avg"
+	AggOHLC = "ohlc" // open/high/low/close，value 为 close
+)
+
+// Point 一个原始数据点
+type Point struct {
+	At    time.Time
+	Value float64
+}
+
+// Bucket 一个时间桶的聚合结果
+type Bucket struct {
+	Start time.Time // 桶开始时间（调用方时区）
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+	Avg   float64
+	Count int
+}
+
+// Value 按聚合方式取桶的值
+func (b Bucket) Value(agg string) float64 {
+	switch agg {
+	case AggMin:
+		return b.Low
+	case AggMax:
+		return b.High
+	case AggAvg:
+		return b.Avg
+	default:
+		return b.Close
+	}
+}
+
+// ParseBucket 校验分桶粒度，为空时不分桶
+func ParseBucket(bucket string) (string, error) {
+	bucket = strings.ToLower(strings.TrimSpace(bucket))
+	switch bucket {
+	case "":
+		return BucketRaw, nil
+	case BucketRaw, BucketHour, BucketDay, BucketWeek, BucketMonth:
+		return bucket, nil
+	default:
+		return "", fmt.Errorf("bucket must be one of raw, hour, day, week, month")
+	}
+}
+
+// ParseAgg 校验聚合方式，为空时取桶内最后一个值
+func ParseAgg(agg string) (string, error) {
+	agg = strings.ToLower(strings.TrimSpace(agg))
+	switch agg {
+	case "":
+		return AggLast, nil
+	case AggLast, AggMin, AggMax, AggAvg, AggOHLC:
+		return agg, nil
+	default:
+		return "", fmt.Errorf("agg must be one of last, min, max, avg, ohlc")
+	}
+}
+
+// LoadLocation 解析 IANA 时区名称，为空时使用 UTC
+func LoadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone: %s", name)
+	}
+	return loc, nil
+}
+
+// Truncate 返回 t 在 loc 时区中所属桶的开始时间
+func Truncate(t time.Time, bucket string, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch bucket {
+	case BucketHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+	case BucketDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	case BucketWeek:
+		offset := (int(t.Weekday()) + 6) % 7 // 周一为 0
+		return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, loc)
+	case BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	default:
+		return t
+	}
+}
+
+// Aggregate 按桶聚合升序排列的数据点，只返回有数据的桶
+// bucket 为 raw 时每个点单独成桶
+func Aggregate(points []Point, bucket string, loc *time.Location) []Bucket {
+	buckets := []Bucket{}
+	var sum float64
+	for _, point := range points {
+		start := Truncate(point.At, bucket, loc)
+		last := len(buckets) - 1
+		if bucket == BucketRaw || last < 0 || !buckets[last].Start.Equal(start) {
+			if last >= 0 {
+				buckets[last].Avg = round4(sum / float64(buckets[last].Count))
+			}
+			buckets = append(buckets, Bucket{
+				Start: start,
+				Open:  point.Value,
+				High:  point.Value,
+				Low:   point.Value,
+				Close: point.Value,
+				Count: 1,
+			})
+			sum = point.Value
+			continue
+		}
+
+		b := &buckets[last]
+		b.High = math.Max(b.High, point.Value)
+		b.Low = math.Min(b.Low, point.Value)
+		b.Close = point.Value
+		b.Count++
+		sum += point.Value
+	}
+	if last := len(buckets) - 1; last >= 0 {
+		buckets[last].Avg = round4(sum / float64(buckets[last].Count))
+	}
+	return buckets
+}
+
+// round4 保留四位小数
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,123 @@
+package timeseries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	shanghai, err := LoadLocation("Asia/Shanghai")
+	assert.NoError(t, err)
+	// UTC 2025-01-05 (周日) 20:30 = 上海 2025-01-06 (周一) 04:30
+	at := time.Date(2025, 1, 5, 20, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), Truncate(at, BucketDay, time.UTC))
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, shanghai), Truncate(at, BucketDay, shanghai))
+	assert.Equal(t, time.Date(2025, 1, 6, 4, 0, 0, 0, shanghai), Truncate(at, BucketHour, shanghai))
+
+	// 周一开始的周
+	assert.Equal(t, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), Truncate(at, BucketWeek, time.UTC))
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, shanghai), Truncate(at, BucketWeek, shanghai))
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Truncate(at, BucketMonth, time.UTC))
+}
+
+func TestAggregate(t *testing.T) {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	points := []Point{
+		{At: day.Add(1 * time.Hour), Value: 10},
+		{At: day.Add(8 * time.Hour), Value: 14},
+		{At: day.Add(20 * time.Hour), Value: 9},
+		{At: day.Add(30 * time.Hour), Value: 12},
+	}
+
+	buckets := Aggregate(points, BucketDay, time.UTC)
+	assert.Len(t, buckets, 2)
+	assert.Equal(t, day, buckets[0].Start)
+	assert.Equal(t, 3, buckets[0].Count)
+	assert.Equal(t, 10.0, buckets[0].Open)
+	assert.Equal(t, 14.0, buckets[0].High)
+	assert.Equal(t, 9.0, buckets[0].Low)
+	assert.Equal(t, 9.0, buckets[0].Close)
+	assert.Equal(t, 11.0, buckets[0].Avg)
+	assert.Equal(t, 12.0, buckets[1].Avg)
+
+	assert.Equal(t, 9.0, buckets[0].Value(AggLast))
+	assert.Equal(t, 9.0, buckets[0].Value(AggMin))
+	assert.Equal(t, 14.0, buckets[0].Value(AggMax))
+	assert.Equal(t, 11.0, buckets[0].Value(AggAvg))
+
+	// 时区改变桶边界：UTC-8 下前三个点落在 2/28 和 3/1 两天
+	la := time.FixedZone("PST", -8*3600)
+	assert.Len(t, Aggregate(points, BucketDay, la), 2)
+	assert.Equal(t, 1, Aggregate(points, BucketDay, la)[0].Count)
+
+	assert.Len(t, Aggregate(points, BucketRaw, time.UTC), 4)
+	assert.Empty(t, Aggregate(nil, BucketDay, time.UTC))
+}
+
+func TestParseBucketAndAgg(t *testing.T) {
+	loc, err := LoadLocation("")
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, loc)
+	_, err = LoadLocation("Mars/Olympus")
+	assert.Error(t, err)
+
+	bucket, err := ParseBucket("")
+	assert.NoError(t, err)
+	assert.Equal(t, BucketRaw, bucket)
+	bucket, err = ParseBucket("Week")
+	assert.NoError(t, err)
+	assert.Equal(t, BucketWeek, bucket)
+	_, err = ParseBucket("year")
+	assert.Error(t, err)
+
+	agg, err := ParseAgg("")
+	assert.NoError(t, err)
+	assert.Equal(t, AggLast, agg)
+	_, err = ParseAgg("sum")
+	assert.Error(t, err)
+}
+
+func TestAlignAndNormalize(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	a := []Bucket{{Start: day(1), Close: 10, Count: 1}, {Start: day(3), Close: 12, Count: 1}}
+	b := []Bucket{{Start: day(2), Close: 50, Count: 1}, {Start: day(3), Close: 40, Count: 1}}
+
+	starts, values := Align([][]Bucket{a, b}, AggLast)
+	assert.Equal(t, []time.Time{day(1), day(2), day(3)}, starts)
+
+	// a 在 3/2 没有数据时沿用 3/1 的值，b 在第一个数据点之前为空
+	assert.Equal(t, 10.0, *values[0][1])
+	assert.Equal(t, 12.0, *values[0][2])
+	assert.Nil(t, values[1][0])
+	assert.Equal(t, 50.0, *values[1][1])
+
+	// 3/2 是两个序列都有数据的第一个桶，之前的桶不参与对比
+	base := CommonBase(values)
+	assert.Equal(t, 1, base)
+
+	index := Normalize(values[0], base, NormalizeIndex)
+	assert.Nil(t, index[0])
+	assert.Equal(t, 100.0, *index[1])
+	assert.Equal(t, 120.0, *index[2])
+
+	pct := Normalize(values[1], base, NormalizePctChange)
+	assert.Nil(t, pct[0])
+	assert.Equal(t, 0.0, *pct[1])
+	assert.Equal(t, -20.0, *pct[2])
+
+	assert.Equal(t, values[0], Normalize(values[0], base, NormalizeNone))
+
+	// 基准值为 0 时无法归一化
+	zero := 0.0
+	assert.Equal(t, []*float64{nil, nil}, Normalize([]*float64{&zero, values[0][2]}, 0, NormalizeIndex))
+	assert.Equal(t, 0, CommonBase(nil))
+
+	mode, err := ParseNormalize("")
+	assert.NoError(t, err)
+	assert.Equal(t, NormalizeNone, mode)
+	_, err = ParseNormalize("log")
+	assert.Error(t, err)
+}
@@ -24,6 +24,12 @@ func GetUserIDFromContext(ctx context.Context) (string, error) {
 	return userIDStr, nil
 }
 
+// GetWorkspaceIDFromContext 获取请求指定的工作区ID (X-Workspace-ID)，未指定时返回空字符串
+func GetWorkspaceIDFromContext(ctx context.Context) string {
+	workspaceID, _ := ctx.Value("workspace_id").(string)
+	return workspaceID
+}
+
 // GetUserEmailFromContext 从JWT context获取用户邮箱
 func GetUserEmailFromContext(ctx context.Context) (string, error) {
 	email := ctx.Value("email")
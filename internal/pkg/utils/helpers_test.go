@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -219,6 +220,70 @@ func TestValidateThreshold(t *testing.T) {
 	}
 }
 
+func TestValidateTag(t *testing.T) {
+	testCases := []struct {
+		name      string
+		tagName   string
+		color     string
+		expectErr bool
+	}{
+		{
+			name:      "Valid Tag",
+			tagName:   "Q4 Promo",
+			color:     "#3B82F6",
+			expectErr: false,
+		},
+		{
+			name:      "Short Color",
+			tagName:   "Kitchen",
+			color:     "#fa0",
+			expectErr: false,
+		},
+		{
+			name:      "Empty Color (Optional)",
+			tagName:   "Kitchen",
+			color:     "",
+			expectErr: false,
+		},
+		{
+			name:      "Empty Name",
+			tagName:   "",
+			expectErr: true,
+		},
+		{
+			name:      "Name with Separator",
+			tagName:   "a;b",
+			expectErr: true,
+		},
+		{
+			name:      "Too Long Name",
+			tagName:   strings.Repeat("x", 51),
+			expectErr: true,
+		},
+		{
+			name:      "Invalid Color",
+			tagName:   "Kitchen",
+			color:     "blue",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTagName(tc.tagName)
+			if err == nil {
+				err = ValidateTagColor(tc.color)
+			}
+
+			if tc.expectErr {
+				assert.Error(t, err, "Expected error for tag: %s %s", tc.tagName, tc.color)
+			} else {
+				assert.NoError(t, err, "Expected no error for tag: %s %s", tc.tagName, tc.color)
+			}
+		})
+	}
+}
+
 func TestValidatePaginationParams(t *testing.T) {
 	testCases := []struct {
 		name      string
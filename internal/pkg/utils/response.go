@@ -42,6 +42,8 @@ func GetHTTPStatusFromCode(code string) int {
 		return http.StatusUnprocessableEntity
 	case errors.CodeRateLimitExceeded:
 		return http.StatusTooManyRequests
+	case errors.CodeQuotaExceeded:
+		return http.StatusPaymentRequired
 	case errors.CodeServiceUnavailable:
 		return http.StatusServiceUnavailable
 	case errors.CodeInternalError:
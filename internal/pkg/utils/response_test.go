@@ -50,6 +50,11 @@ func TestGetHTTPStatusFromCode(t *testing.T) {
 			errorCode:    errors.CodeRateLimitExceeded,
 			expectedHTTP: http.StatusTooManyRequests,
 		},
+		{
+			name:         "Quota Exceeded",
+			errorCode:    errors.CodeQuotaExceeded,
+			expectedHTTP: http.StatusPaymentRequired,
+		},
 		{
 			name:         "Service Unavailable",
 			errorCode:    errors.CodeServiceUnavailable,
@@ -85,6 +90,7 @@ func TestGetHTTPStatusFromCode_AllErrorCodes(t *testing.T) {
 		errors.CodeConflict:            http.StatusConflict,
 		errors.CodeUnprocessableEntity: http.StatusUnprocessableEntity,
 		errors.CodeRateLimitExceeded:   http.StatusTooManyRequests,
+		errors.CodeQuotaExceeded:       http.StatusPaymentRequired,
 		errors.CodeServiceUnavailable:  http.StatusServiceUnavailable,
 		errors.CodeInternalError:       http.StatusInternalServerError,
 	}
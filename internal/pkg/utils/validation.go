@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/pkg/types"
 )
 
 // ValidateASIN 验证Amazon ASIN格式
@@ -109,6 +111,46 @@ func ValidateThreshold(threshold float64, fieldName string) error {
 	return nil
 }
 
+// ValidateTagName 验证标签名称
+func ValidateTagName(name string) error {
+	if name == "" {
+		return errors.NewValidationError("Tag name cannot be empty", []errors.FieldError{
+			{Field: "name", Message: "Tag name is required"},
+		})
+	}
+
+	if len(name) > 50 {
+		return errors.NewValidationError("Tag name too long", []errors.FieldError{
+			{Field: "name", Message: "Tag name must be at most 50 characters"},
+		})
+	}
+
+	// ; 和 | 是批量导入时的标签分隔符
+	if strings.ContainsAny(name, "<>\"'&;|") {
+		return errors.NewValidationError("Invalid tag name", []errors.FieldError{
+			{Field: "name", Message: "Tag name cannot contain special characters like <, >, \", ', &, ;, |"},
+		})
+	}
+
+	return nil
+}
+
+// ValidateTagColor 验证标签颜色，格式为 #RGB 或 #RRGGBB
+func ValidateTagColor(color string) error {
+	if color == "" {
+		return nil // 颜色是可选的
+	}
+
+	matched, _ := regexp.MatchString(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`, color)
+	if !matched {
+		return errors.NewValidationError("Invalid tag color", []errors.FieldError{
+			{Field: "color", Message: "Color must be a hex value like #3B82F6"},
+		})
+	}
+
+	return nil
+}
+
 // ValidatePaginationParams 验证分页参数
 func ValidatePaginationParams(page, limit int) error {
 	if page < 1 {
@@ -132,6 +174,29 @@ func ValidatePaginationParams(page, limit int) error {
 	return nil
 }
 
+// ParseCursor 解析游标分页参数，为空时返回 nil
+func ParseCursor(cursor string) (*types.Cursor, error) {
+	parsed, err := types.DecodeCursor(cursor)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid cursor", []errors.FieldError{
+			{Field: "cursor", Message: "Cursor is invalid, use next_cursor from the previous page"},
+		})
+	}
+	return parsed, nil
+}
+
+// PeriodStartTime 解析查询时间范围 (7d, 30d, 90d)，默认30天，返回规范化后的范围和起始时间
+func PeriodStartTime(period string) (string, time.Time) {
+	switch period {
+	case "7d":
+		return period, time.Now().AddDate(0, 0, -7)
+	case "90d":
+		return period, time.Now().AddDate(0, 0, -90)
+	default:
+		return "30d", time.Now().AddDate(0, 0, -30)
+	}
+}
+
 // SanitizeInput 清理用户输入
 func SanitizeInput(input string) string {
 	// 移除前后空格
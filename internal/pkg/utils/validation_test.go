@@ -2,6 +2,10 @@ package utils
 
 import (
 	"testing"
+	"time"
+
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/pkg/types"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -172,4 +176,35 @@ func TestValidatePassword(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestParseCursor(t *testing.T) {
+	cursor, err := ParseCursor("")
+	assert.NoError(t, err)
+	assert.Nil(t, cursor)
+
+	at := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	cursor, err = ParseCursor(types.EncodeCursor(at, "abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", cursor.ID)
+
+	_, err = ParseCursor("garbage")
+	var apiErr *errors.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, errors.CodeValidationError, apiErr.ErrorDetail.Code)
+}
+
+func TestPeriodStartTime(t *testing.T) {
+	period, start := PeriodStartTime("7d")
+	assert.Equal(t, "7d", period)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), start, time.Minute)
+
+	period, start = PeriodStartTime("90d")
+	assert.Equal(t, "90d", period)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -90), start, time.Minute)
+
+	// 未知范围回退到默认30天
+	period, start = PeriodStartTime("1y")
+	assert.Equal(t, "30d", period)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -30), start, time.Minute)
+}
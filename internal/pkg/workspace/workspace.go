@@ -0,0 +1,156 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 成员角色，权限依次递减
+const (
+	RoleOwner  = "owner"  // 管理工作区、成员和邀请
+	RoleEditor = "editor" // 增删改追踪产品、标签、分析组等资源
+	RoleViewer = "viewer" // 只读
+)
+
+// 邀请状态
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+)
+
+// InvitationTTL 邀请有效期
+const InvitationTTL = 7 * 24 * time.Hour
+
+var roleRanks = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// ValidRole 判断角色是否有效
+func ValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// Allows 判断角色是否满足要求的最低角色
+func Allows(role, required string) bool {
+	return ValidRole(role) && ValidRole(required) && roleRanks[role] >= roleRanks[required]
+}
+
+// Access 当前用户在请求工作区中的身份
+type Access struct {
+	WorkspaceID string
+	UserID      string
+	Role        string
+}
+
+// Can 判断是否具有要求的最低角色
+func (a *Access) Can(required string) bool {
+	return Allows(a.Role, required)
+}
+
+// Authorize 校验当前用户是请求工作区 (X-Workspace-ID，缺省为个人工作区) 的成员且角色不低于 required
+// 所有工作区资源的查询都应以返回的 WorkspaceID 过滤
+func Authorize(ctx context.Context, db *gorm.DB, required string) (*Access, error) {
+	if workspaceID := utils.GetWorkspaceIDFromContext(ctx); workspaceID != "" {
+		return AuthorizeWorkspace(ctx, db, workspaceID, required)
+	}
+
+	userID, err := utils.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var member models.WorkspaceMember
+	err = db.WithContext(ctx).
+		Joins("JOIN workspaces ON workspaces.id = workspace_members.workspace_id").
+		Where("workspace_members.user_id = ? AND workspaces.owner_id = ? AND workspaces.is_personal = ?", userID, userID, true).
+		First(&member).Error
+	return newAccess(ctx, &member, err, required)
+}
+
+// AuthorizeWorkspace 校验当前用户是指定工作区的成员且角色不低于 required
+func AuthorizeWorkspace(ctx context.Context, db *gorm.DB, workspaceID, required string) (*Access, error) {
+	userID, err := utils.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(workspaceID); err != nil {
+		return nil, errors.NewBadRequestError("Invalid workspace ID")
+	}
+
+	var member models.WorkspaceMember
+	err = db.WithContext(ctx).Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member).Error
+	return newAccess(ctx, &member, err, required)
+}
+
+// newAccess 根据成员查询结果构造访问身份并校验角色
+func newAccess(ctx context.Context, member *models.WorkspaceMember, err error, required string) (*Access, error) {
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.NewForbiddenError("You are not a member of this workspace")
+	} else if err != nil {
+		utils.LogError(ctx, "Failed to load workspace membership", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	access := &Access{WorkspaceID: member.WorkspaceID, UserID: member.UserID, Role: member.Role}
+	if !access.Can(required) {
+		return nil, errors.NewForbiddenError(fmt.Sprintf("This action requires the %s role in the workspace", required))
+	}
+	return access, nil
+}
+
+// CreatePersonal 为新用户创建个人工作区并加入为所有者，需在创建用户的事务中调用
+func CreatePersonal(tx *gorm.DB, user *models.User) (*models.Workspace, error) {
+	name := "Personal"
+	if user.CompanyName != nil && *user.CompanyName != "" {
+		name = *user.CompanyName
+	}
+	workspace := models.Workspace{Name: name, OwnerID: user.ID, IsPersonal: true}
+	if err := tx.Create(&workspace).Error; err != nil {
+		return nil, err
+	}
+	member := models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: user.ID, Role: RoleOwner}
+	if err := tx.Create(&member).Error; err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// PersonalWorkspaceID 返回用户个人工作区的ID
+func PersonalWorkspaceID(db *gorm.DB, userID string) (string, error) {
+	var workspace models.Workspace
+	if err := db.Where("owner_id = ? AND is_personal = ?", userID, true).First(&workspace).Error; err != nil {
+		return "", err
+	}
+	return workspace.ID, nil
+}
+
+// MemberUserIDs 返回追踪了产品的所有工作区的成员，用于推送对所有追踪工作区可见的产品事件
+func MemberUserIDs(db *gorm.DB, productID string) ([]string, error) {
+	var userIDs []string
+	err := db.Model(&models.WorkspaceMember{}).
+		Joins("JOIN tracked_products tp ON tp.workspace_id = workspace_members.workspace_id").
+		Where("tp.product_id = ? AND tp.is_active = ?", productID, true).
+		Distinct().
+		Pluck("workspace_members.user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// WorkspaceMemberIDs 返回工作区的所有成员，用于推送只属于该工作区的事件
+func WorkspaceMemberIDs(db *gorm.DB, workspaceID string) ([]string, error) {
+	var userIDs []string
+	err := db.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ?", workspaceID).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
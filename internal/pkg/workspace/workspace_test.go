@@ -0,0 +1,54 @@
+package workspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidRole(t *testing.T) {
+	assert.True(t, ValidRole(RoleOwner))
+	assert.True(t, ValidRole(RoleEditor))
+	assert.True(t, ValidRole(RoleViewer))
+	assert.False(t, ValidRole(""))
+	assert.False(t, ValidRole("admin"))
+}
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		role     string
+		required string
+		want     bool
+	}{
+		{RoleOwner, RoleOwner, true},
+		{RoleOwner, RoleViewer, true},
+		{RoleEditor, RoleEditor, true},
+		{RoleEditor, RoleOwner, false},
+		{RoleViewer, RoleViewer, true},
+		{RoleViewer, RoleEditor, false},
+		{"admin", RoleViewer, false},
+		{RoleOwner, "admin", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role+"_"+tt.required, func(t *testing.T) {
+			assert.Equal(t, tt.want, Allows(tt.role, tt.required))
+		})
+	}
+}
+
+func TestAccessCan(t *testing.T) {
+	access := &Access{WorkspaceID: "w1", UserID: "u1", Role: RoleEditor}
+	assert.True(t, access.Can(RoleViewer))
+	assert.True(t, access.Can(RoleEditor))
+	assert.False(t, access.Can(RoleOwner))
+}
+
+func TestAuthorizeWorkspaceRejectsInvalidID(t *testing.T) {
+	// 非 UUID 的工作区ID在查询数据库前即被拒绝
+	ctx := context.WithValue(context.Background(), "user_id", "u1")
+	_, err := AuthorizeWorkspace(ctx, nil, "not-a-uuid", RoleViewer)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid workspace ID")
+}
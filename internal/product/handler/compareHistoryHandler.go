@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/product/logic"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func compareHistoryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.CompareHistoryRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			utils.HandleError(w, err)
+			return
+		}
+
+		l := logic.NewCompareHistoryLogic(r.Context(), svcCtx)
+		resp, err := l.CompareHistory(&req)
+		if err != nil {
+			utils.HandleError(w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
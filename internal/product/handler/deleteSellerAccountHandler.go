@@ -3,23 +3,24 @@ package handler
 import (
 	"net/http"
 
-	"github.com/zeromicro/go-zero/rest/httpx"
+	"amazonpilot/internal/pkg/utils"
 	"amazonpilot/internal/product/logic"
 	"amazonpilot/internal/product/svc"
 	"amazonpilot/internal/product/types"
-	"amazonpilot/internal/pkg/utils"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
 )
 
-func addMockPriceHistoryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+func deleteSellerAccountHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req types.AddMockPriceHistoryRequest
+		var req types.DeleteSellerAccountRequest
 		if err := httpx.Parse(r, &req); err != nil {
 			utils.HandleError(w, err)
 			return
 		}
 
-		l := logic.NewAddMockPriceHistoryLogic(r.Context(), svcCtx)
-		resp, err := l.AddMockPriceHistory(&req)
+		l := logic.NewDeleteSellerAccountLogic(r.Context(), svcCtx)
+		resp, err := l.DeleteSellerAccount(&req)
 		if err != nil {
 			utils.HandleError(w, err)
 		} else {
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/product/logic"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func downloadExportHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.DownloadExportRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			utils.HandleError(w, err)
+			return
+		}
+
+		l := logic.NewDownloadExportLogic(r.Context(), svcCtx)
+		if err := l.DownloadExport(&req, w, r); err != nil {
+			utils.HandleError(w, err)
+		}
+	}
+}
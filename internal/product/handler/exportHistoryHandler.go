@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/product/logic"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func exportHistoryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ExportHistoryRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			utils.HandleError(w, err)
+			return
+		}
+
+		// 同步导出直接写入响应体 (resp 为 nil)，后台导出返回任务信息
+		l := logic.NewExportHistoryLogic(r.Context(), svcCtx)
+		resp, err := l.ExportHistory(&req, w)
+		if err != nil {
+			utils.HandleError(w, err)
+		} else if resp != nil {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
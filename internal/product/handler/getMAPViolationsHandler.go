@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/product/logic"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func getMAPViolationsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.GetMAPViolationsRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			utils.HandleError(w, err)
+			return
+		}
+
+		l := logic.NewGetMAPViolationsLogic(r.Context(), svcCtx)
+		resp, err := l.GetMAPViolations(&req)
+		if err != nil {
+			utils.HandleError(w, err)
+			return
+		}
+
+		// CSV 格式用于直接发送给经销商
+		if req.Format == "csv" {
+			data, err := logic.MAPViolationsCSV(resp)
+			if err != nil {
+				utils.HandleError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=map-violations-%s-%s-%s.csv", resp.ASIN, resp.StartDate, resp.EndDate))
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+			return
+		}
+
+		httpx.OkJsonCtx(r.Context(), w, resp)
+	}
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"net/http"
+
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/product/logic"
+	"amazonpilot/internal/product/svc"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func getSellerAccountsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewGetSellerAccountsLogic(r.Context(), svcCtx)
+		resp, err := l.GetSellerAccounts()
+		if err != nil {
+			utils.HandleError(w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/product/logic"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func importTrackingHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ImportTrackingRequest
+		if err := parseImportRequest(r, &req); err != nil {
+			utils.HandleError(w, err)
+			return
+		}
+
+		l := logic.NewImportTrackingLogic(r.Context(), svcCtx)
+		resp, err := l.ImportTracking(&req)
+		if err != nil {
+			utils.HandleError(w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
+
+// parseImportRequest 支持三种请求方式：JSON 请求体、text/csv 原始内容、multipart 上传文件 (字段名 file)
+func parseImportRequest(r *http.Request, req *types.ImportTrackingRequest) error {
+	contentType := strings.ToLower(r.Header.Get("Content-Type"))
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return errors.NewValidationError("Failed to read request body", nil)
+		}
+		req.Format = "csv"
+		req.Data = string(data)
+		req.Async = r.URL.Query().Get("async") == "true"
+		return nil
+
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			return errors.NewValidationError("Missing import file", []errors.FieldError{
+				{Field: "file", Message: "Upload a CSV or JSON file in the file field"},
+			})
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return errors.NewValidationError("Failed to read import file", nil)
+		}
+		req.Format = "csv"
+		if strings.EqualFold(filepath.Ext(header.Filename), ".json") {
+			req.Format = "json"
+		}
+		req.Data = string(data)
+		req.Async = r.FormValue("async") == "true"
+		return nil
+	}
+
+	return httpx.Parse(r, req)
+}
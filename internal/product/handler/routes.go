@@ -5,6 +5,7 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"amazonpilot/internal/product/svc"
 
@@ -45,6 +46,21 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 					Path:    "/products/tracked",
 					Handler: getTrackedProductsHandler(serverCtx),
 				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/products/import",
+					Handler: importTrackingHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/import/:job_id",
+					Handler: getImportJobHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/exports/:job_id",
+					Handler: getExportJobHandler(serverCtx),
+				},
 				{
 					Method:  http.MethodGet,
 					Path:    "/products/:product_id",
@@ -55,6 +71,121 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 					Path:    "/products/:product_id/history",
 					Handler: getProductHistoryHandler(serverCtx),
 				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/compare",
+					Handler: compareHistoryHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/:product_id/variations",
+					Handler: getProductVariationsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/:product_id/content-history",
+					Handler: getContentHistoryHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/:product_id/offers",
+					Handler: getProductOffersHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/:product_id/offers/history",
+					Handler: getOfferHistoryHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/:product_id/buybox-share",
+					Handler: getBuyBoxShareHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/:product_id/authorized-sellers",
+					Handler: getAuthorizedSellersHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/products/:product_id/authorized-sellers",
+					Handler: updateAuthorizedSellersHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/products/:product_id/map-price",
+					Handler: updateMAPPriceHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/:product_id/map-violations",
+					Handler: getMAPViolationsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/:product_id/availability",
+					Handler: getAvailabilityHistoryHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/sellers",
+					Handler: getSellerAccountsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/sellers",
+					Handler: addSellerAccountHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/sellers/:seller_account_id",
+					Handler: deleteSellerAccountHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/usage",
+					Handler: getUsageHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPatch,
+					Path:    "/products/:product_id/track",
+					Handler: updateProductTrackingHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/tags",
+					Handler: listTagsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/products/tags",
+					Handler: createTagHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/tags/summary",
+					Handler: getTagSummaryHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPatch,
+					Path:    "/products/tags/:tag_id",
+					Handler: updateTagHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/products/tags/:tag_id",
+					Handler: deleteTagHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/products/tags/:tag_id/tracked",
+					Handler: assignTagHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/products/tracked/:tracked_id/tags",
+					Handler: setTrackedTagsHandler(serverCtx),
+				},
 				{
 					Method:  http.MethodDelete,
 					Path:    "/products/:product_id/track",
@@ -65,19 +196,62 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 					Path:    "/products/:product_id/refresh",
 					Handler: refreshProductDataHandler(serverCtx),
 				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/refresh-tasks/:task_id",
+					Handler: getRefreshTaskHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/:product_id/refreshes",
+					Handler: listRefreshTasksHandler(serverCtx),
+				},
 				{
 					Method:  http.MethodGet,
 					Path:    "/products/anomaly-events",
 					Handler: getAnomalyEventsHandler(serverCtx),
 				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/product"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.RateLimitMiddleware},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/products/history/export",
+					Handler: exportHistoryHandler(serverCtx),
+				},
 				{
-					Method:  http.MethodPost,
-					Path:    "/products/tracked/:tracked_id/add-mock-price-history",
-					Handler: addMockPriceHistoryHandler(serverCtx),
+					Method:  http.MethodGet,
+					Path:    "/products/exports/:job_id/download",
+					Handler: downloadExportHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/product"),
+		rest.WithTimeout(0*time.Millisecond),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.RateLimitMiddleware},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/events/stream",
+					Handler: streamEventsHandler(serverCtx),
 				},
 			}...,
 		),
 		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
 		rest.WithPrefix("/api/product"),
+		rest.WithSSE(),
+		rest.WithTimeout(0*time.Millisecond),
 	)
 }
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"net/http"
+
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/product/logic"
+	"amazonpilot/internal/product/svc"
+)
+
+func streamEventsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewStreamEventsLogic(r.Context(), svcCtx)
+		if err := l.StreamEvents(w); err != nil {
+			utils.HandleError(w, err)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package logic
 
 import (
 	"context"
-	"encoding/json"
 	"log/slog"
 	"regexp"
 	"strings"
@@ -10,12 +9,12 @@ import (
 
 	"amazonpilot/internal/product/svc"
 	"amazonpilot/internal/product/types"
-	"amazonpilot/internal/pkg/cache"
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/models"
-	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/tasks"
+	"amazonpilot/internal/pkg/workspace"
 
-	"github.com/hibiken/asynq"
 	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
 )
@@ -35,8 +34,8 @@ func NewAddProductTrackingLogic(ctx context.Context, svcCtx *svc.ServiceContext)
 }
 
 func (l *AddProductTrackingLogic) AddProductTracking(req *types.AddTrackingRequest) (resp *types.AddTrackingResponse, err error) {
-	// 从JWT context获取用户ID
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
 	if err != nil {
 		return nil, err
 	}
@@ -72,9 +71,9 @@ func (l *AddProductTrackingLogic) AddProductTracking(req *types.AddTrackingReque
 		return nil, errors.ErrInternalServer
 	}
 
-	// 检查用户是否已经在追踪这个产品
+	// 检查工作区是否已经在追踪这个产品
 	var existingTrack models.TrackedProduct
-	err = l.svcCtx.DB.Where("user_id = ? AND product_id = ?", userIDStr, product.ID).First(&existingTrack).Error
+	err = l.svcCtx.DB.Where("workspace_id = ? AND product_id = ?", access.WorkspaceID, product.ID).First(&existingTrack).Error
 	if err == nil {
 		return nil, errors.NewConflictError("Product is already being tracked")
 	} else if err != gorm.ErrRecordNotFound {
@@ -82,20 +81,30 @@ func (l *AddProductTrackingLogic) AddProductTracking(req *types.AddTrackingReque
 		return nil, errors.ErrInternalServer
 	}
 
+	// 校验工作区计划的追踪产品数上限
+	if err := quota.CheckTrackedProducts(l.ctx, l.svcCtx.DB, access.WorkspaceID, 1); err != nil {
+		return nil, err
+	}
+
 	// 创建追踪记录
 	trackingSettings := req.Settings
 	trackedProduct := models.TrackedProduct{
-		UserID:                userIDStr,
+		UserID:                access.UserID,
+		WorkspaceID:           access.WorkspaceID,
 		ProductID:             product.ID,
 		IsActive:              true,
 		TrackingFrequency:     "daily", // Fixed at daily per questions.md
 		PriceChangeThreshold:  trackingSettings.PriceChangeThreshold,
 		BSRChangeThreshold:    trackingSettings.BSRChangeThreshold,
+		TrackVariations:       req.TrackVariations,
 	}
 
 	if req.Alias != "" {
 		trackedProduct.Alias = &req.Alias
 	}
+	if trackingSettings.MAPPrice > 0 {
+		trackedProduct.MAPPrice = &trackingSettings.MAPPrice
+	}
 
 	// 计算下次检查时间
 	nextCheck := calculateNextCheckTime("daily") // Fixed at daily per questions.md
@@ -114,31 +123,24 @@ func (l *AddProductTrackingLogic) AddProductTracking(req *types.AddTrackingReque
 	}
 
 	// 🚀 添加产品后立即发送队列任务获取初始数据
-	taskPayload := map[string]interface{}{
-		"product_id":    product.ID,
-		"tracked_id":    trackedProduct.ID,
-		"asin":          product.ASIN,
-		"user_id":       userIDStr,
-		"requested_at":  time.Now().Format(time.RFC3339),
-		"initial_fetch": true, // 标记为初始数据获取
-	}
-
-	payloadBytes, err := json.Marshal(taskPayload)
+	// 发送初始数据获取任务，任务ID可用于查询刷新状态
+	info, err := tasks.EnqueueRefreshTask(l.ctx, l.svcCtx.AsynqClient, l.svcCtx.RedisClient, tasks.RefreshProductDataPayload{
+		ProductID:    product.ID,
+		TrackedID:    trackedProduct.ID,
+		ASIN:         product.ASIN,
+		UserID:       access.UserID,
+		RequestedAt:  time.Now().Format(time.RFC3339),
+		InitialFetch: true, // 标记为初始数据获取
+	})
 	if err != nil {
-		l.Errorf("Failed to marshal initial fetch task payload: %v", err)
+		l.Errorf("Failed to enqueue initial data fetch task: %v", err)
 	} else {
-		// 发送初始数据获取任务
-		task := asynq.NewTask("refresh_product_data", payloadBytes)
-		if info, err := l.svcCtx.AsynqClient.Enqueue(task); err != nil {
-			l.Errorf("Failed to enqueue initial data fetch task: %v", err)
-		} else {
-			l.Infof("Enqueued initial data fetch for new product %s, task ID: %s", product.ASIN, info.ID)
-		}
+		resp.RefreshTaskID = info.ID
+		l.Infof("Enqueued initial data fetch for new product %s, task ID: %s", product.ASIN, info.ID)
 	}
 
 	// 清除与该产品相关的缓存（按产品缓存）
-	productCacheKey := cache.ProductDataKey(product.ID)
-	if err := l.svcCtx.RedisClient.Del(l.ctx, productCacheKey).Err(); err != nil {
+	if err := l.svcCtx.ProductCache.Delete(l.ctx, product.ID); err != nil {
 		l.Errorf("Failed to clear product data cache for product %s: %v", product.ID, err)
 		// 不影响主流程，继续执行
 	} else {
@@ -154,7 +156,7 @@ func (l *AddProductTrackingLogic) AddProductTracking(req *types.AddTrackingReque
 		"asin", req.ASIN,
 		"alias", req.Alias,
 		"frequency", "daily", // Fixed at daily per questions.md
-		"initial_fetch_queued", err == nil)
+		"initial_fetch_queued", resp.RefreshTaskID != "")
 
 	return resp, nil
 }
@@ -171,15 +173,5 @@ func isValidASIN(asin string) bool {
 
 // calculateNextCheckTime 计算下次检查时间
 func calculateNextCheckTime(frequency string) time.Time {
-	now := time.Now()
-	switch frequency {
-	case "hourly":
-		return now.Add(time.Hour)
-	case "daily":
-		return now.Add(24 * time.Hour)
-	case "weekly":
-		return now.Add(7 * 24 * time.Hour)
-	default:
-		return now.Add(24 * time.Hour) // 默认每天
-	}
+	return tasks.NextCheckTime(frequency, time.Now())
 }
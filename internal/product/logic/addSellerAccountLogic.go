@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	"context"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type AddSellerAccountLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewAddSellerAccountLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AddSellerAccountLogic {
+	return &AddSellerAccountLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *AddSellerAccountLogic) AddSellerAccount(req *types.AddSellerAccountRequest) (resp *types.SellerAccount, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	sellerName := strings.TrimSpace(req.SellerName)
+	sellerID := strings.TrimSpace(req.SellerID)
+	if sellerName == "" {
+		return nil, errors.NewValidationError("Invalid seller account", []errors.FieldError{
+			{Field: "seller_name", Message: "seller_name is required"},
+		})
+	}
+
+	// 检查是否已声明过该卖家
+	var accounts []models.SellerAccount
+	l.svcCtx.DB.Where("workspace_id = ?", access.WorkspaceID).Find(&accounts)
+	for _, account := range accounts {
+		if account.Identity().Matches(sellerName, sellerID) {
+			return nil, errors.NewConflictError("Seller account already exists")
+		}
+	}
+
+	account := models.SellerAccount{
+		UserID:      access.UserID,
+		WorkspaceID: access.WorkspaceID,
+		SellerName:  sellerName,
+	}
+	if sellerID != "" {
+		account.SellerID = &sellerID
+	}
+
+	if err := l.svcCtx.DB.Create(&account).Error; err != nil {
+		l.Errorf("Failed to create seller account: %v", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 记录业务日志
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "add_seller_account", "seller_account", account.ID, "success",
+		"seller_name", sellerName,
+		"seller_id", sellerID)
+
+	return &toSellerAccounts([]models.SellerAccount{account})[0], nil
+}
@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+// assignTagMaxProducts 单次批量打标签的最大产品数
+const assignTagMaxProducts = 500
+
+type AssignTagLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewAssignTagLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AssignTagLogic {
+	return &AssignTagLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// AssignTag 批量为追踪产品添加或移除标签
+func (l *AssignTagLogic) AssignTag(req *types.AssignTagRequest) (resp *types.AssignTagResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, id := range req.TrackedIDs {
+		if id != "" && !containsString(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, errors.NewValidationError("No products", []errors.FieldError{
+			{Field: "tracked_ids", Message: "At least one tracked product is required"},
+		})
+	}
+	if len(ids) > assignTagMaxProducts {
+		return nil, errors.NewValidationError("Too many products", []errors.FieldError{
+			{Field: "tracked_ids", Message: fmt.Sprintf("At most %d products can be tagged at once", assignTagMaxProducts)},
+		})
+	}
+
+	tag, err := findWorkspaceTag(l.svcCtx.DB, req.TagID, access.WorkspaceID)
+	if err == errors.ErrNotFound {
+		return nil, err
+	} else if err != nil {
+		utils.LogError(l.ctx, "Failed to load tag", "tag_id", req.TagID, "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 所有追踪产品必须属于当前用户
+	var owned int64
+	if err := l.svcCtx.DB.Model(&models.TrackedProduct{}).
+		Where("id IN ? AND workspace_id = ?", ids, access.WorkspaceID).
+		Count(&owned).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to verify tracked products", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+	if int(owned) != len(ids) {
+		return nil, errors.ErrNotFound
+	}
+
+	updated := 0
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if req.Remove {
+			result := tx.Where("tag_id = ? AND tracked_product_id IN ?", tag.ID, ids).Delete(&models.TrackedProductTag{})
+			updated = int(result.RowsAffected)
+			return result.Error
+		}
+		for _, id := range ids {
+			link := models.TrackedProductTag{TrackedProductID: id, TagID: tag.ID}
+			result := tx.Where(link).FirstOrCreate(&link)
+			if result.Error != nil {
+				return result.Error
+			}
+			updated += int(result.RowsAffected)
+		}
+		return nil
+	})
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to assign tag", "tag_id", tag.ID, "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	var trackedCount int64
+	if err := l.svcCtx.DB.Model(&models.TrackedProductTag{}).Where("tag_id = ?", tag.ID).Count(&trackedCount).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to count tagged products", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 记录业务日志
+	action := "add"
+	if req.Remove {
+		action = "remove"
+	}
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "assign_tag", "tag", tag.ID, "success",
+		"user_id", access.UserID,
+		"action", action,
+		"requested", len(ids),
+		"updated", updated)
+
+	return &types.AssignTagResponse{
+		TagID:        tag.ID,
+		Updated:      updated,
+		TrackedCount: int(trackedCount),
+	}, nil
+}
@@ -0,0 +1,260 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/fx"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/timeseries"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+// compareMaxProducts 单次对比的最大产品数
+const compareMaxProducts = 20
+
+type CompareHistoryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCompareHistoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CompareHistoryLogic {
+	return &CompareHistoryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// compareMember 参与对比的产品
+type compareMember struct {
+	ProductID string
+	TrackedID string
+	ASIN      string
+	Title     string
+	Role      string // tracked, main, competitor
+}
+
+func (l *CompareHistoryLogic) CompareHistory(req *types.CompareHistoryRequest) (resp *types.CompareHistoryResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// 参数校验
+	metric := strings.ToLower(strings.TrimSpace(req.Metric))
+	if metric == "" {
+		metric = "price"
+	}
+	if !containsString(historyMetrics, metric) {
+		return nil, errors.NewValidationError("Invalid metric", []errors.FieldError{
+			{Field: "metric", Message: "Metric must be one of: " + strings.Join(historyMetrics, ", ")},
+		})
+	}
+	if (req.TrackedIDs == "") == (req.GroupID == "") {
+		return nil, errors.NewValidationError("Invalid products", []errors.FieldError{
+			{Field: "tracked_ids", Message: "Provide either tracked_ids or group_id"},
+		})
+	}
+
+	rng, err := parseHistoryRange(req.Period, req.From, req.To, req.Bucket, req.Agg, req.TZ)
+	if err != nil {
+		return nil, err
+	}
+	// 只返回计划保留期内的历史数据
+	if rng.From, err = quota.HistoryStart(l.ctx, l.svcCtx.DB, access.WorkspaceID, rng.From); err != nil {
+		return nil, err
+	}
+	if rng.Bucket == timeseries.BucketRaw {
+		// 原始数据点时间各不相同，对比时默认按天对齐
+		rng.Bucket = timeseries.BucketDay
+	}
+	if rng.Agg == timeseries.AggOHLC {
+		return nil, errors.NewValidationError("Invalid aggregation", []errors.FieldError{
+			{Field: "agg", Message: "agg must be one of last, min, max, avg"},
+		})
+	}
+	normalize, err := timeseries.ParseNormalize(req.Normalize)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid normalize", []errors.FieldError{
+			{Field: "normalize", Message: err.Error()},
+		})
+	}
+	if req.Currency != "" {
+		req.Currency = fx.NormalizeCurrency(req.Currency)
+	}
+
+	var members []compareMember
+	if req.GroupID != "" {
+		members, err = l.groupMembers(req.GroupID, access.WorkspaceID)
+	} else {
+		members, err = l.trackedMembers(req.TrackedIDs, access.WorkspaceID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 逐个产品查询并分桶，再对齐到共同的时间轴
+	bucketed := make([][]timeseries.Bucket, len(members))
+	currencies := make([]string, len(members))
+	for i, member := range members {
+		points, err := loadHistoryPoints(l.svcCtx, metric, member.ProductID, "", req.Currency, rng)
+		if err != nil {
+			utils.LogError(l.ctx, "Failed to get history for comparison", "product_id", member.ProductID, "metric", metric, "error", err)
+			return nil, errors.ErrInternalServer
+		}
+
+		values := make([]timeseries.Point, len(points))
+		for j, p := range points {
+			values[j] = timeseries.Point{At: p.At, Value: p.Value}
+		}
+		bucketed[i] = timeseries.Aggregate(values, rng.Bucket, rng.Loc)
+		if metric == "price" || metric == "buybox" {
+			currencies[i] = seriesCurrency(points, req.Currency)
+		}
+	}
+
+	starts, aligned := timeseries.Align(bucketed, rng.Agg)
+
+	resp = &types.CompareHistoryResponse{
+		Metric:     metric,
+		Period:     rng.Period,
+		From:       rng.From.In(rng.Loc).Format(time.RFC3339),
+		To:         rng.To.In(rng.Loc).Format(time.RFC3339),
+		Bucket:     rng.Bucket,
+		Agg:        rng.Agg,
+		TZ:         rng.Loc.String(),
+		Currency:   req.Currency,
+		Normalize:  normalize,
+		Timestamps: make([]string, len(starts)),
+		Series:     make([]types.CompareSeries, len(members)),
+	}
+	for i, start := range starts {
+		resp.Timestamps[i] = start.Format(time.RFC3339)
+	}
+
+	// 所有产品都有数据的第一个桶作为共同基准，保证各序列从同一时间点开始比较
+	base := timeseries.CommonBase(aligned)
+	for i, member := range members {
+		series := types.CompareSeries{
+			ProductID: member.ProductID,
+			TrackedID: member.TrackedID,
+			ASIN:      member.ASIN,
+			Title:     member.Title,
+			Role:      member.Role,
+			Currency:  currencies[i],
+			Values:    make([]interface{}, len(starts)),
+		}
+		if normalize != timeseries.NormalizeNone && base < len(aligned[i]) && aligned[i][base] != nil {
+			series.Base = *aligned[i][base]
+		}
+		for j, v := range timeseries.Normalize(aligned[i], base, normalize) {
+			if v != nil {
+				series.Values[j] = *v
+			}
+		}
+		resp.Series[i] = series
+	}
+
+	// 记录业务日志
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "compare_history", "product", access.UserID, "success",
+		"metric", metric,
+		"group_id", req.GroupID,
+		"products", len(members),
+		"bucket", rng.Bucket,
+		"normalize", normalize,
+		"buckets", len(starts))
+
+	return resp, nil
+}
+
+// trackedMembers 按请求顺序加载工作区的追踪产品
+func (l *CompareHistoryLogic) trackedMembers(trackedIDs, workspaceID string) ([]compareMember, error) {
+	ids := splitIDs(trackedIDs)
+	if len(ids) > compareMaxProducts {
+		return nil, errors.NewValidationError("Too many products", []errors.FieldError{
+			{Field: "tracked_ids", Message: fmt.Sprintf("At most %d products can be compared", compareMaxProducts)},
+		})
+	}
+
+	var trackedProducts []models.TrackedProduct
+	if err := l.svcCtx.DB.Where("id IN ? AND workspace_id = ?", ids, workspaceID).
+		Preload("Product").
+		Find(&trackedProducts).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to load tracked products for comparison", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+	if len(trackedProducts) != len(ids) {
+		return nil, errors.ErrNotFound
+	}
+
+	byID := make(map[string]models.TrackedProduct, len(trackedProducts))
+	for _, tp := range trackedProducts {
+		byID[tp.ID] = tp
+	}
+	members := make([]compareMember, 0, len(ids))
+	for _, id := range ids {
+		tp := byID[id]
+		title := getStringValue(tp.Product.Title)
+		if tp.Alias != nil && *tp.Alias != "" {
+			title = *tp.Alias
+		}
+		members = append(members, compareMember{
+			ProductID: tp.ProductID,
+			TrackedID: tp.ID,
+			ASIN:      tp.Product.ASIN,
+			Title:     title,
+			Role:      "tracked",
+		})
+	}
+	return members, nil
+}
+
+// groupMembers 加载竞品分析组的主产品和竞品，主产品排在第一位
+func (l *CompareHistoryLogic) groupMembers(groupID, workspaceID string) ([]compareMember, error) {
+	var group models.CompetitorAnalysisGroup
+	err := l.svcCtx.DB.Where("id = ? AND workspace_id = ?", groupID, workspaceID).
+		Preload("MainProduct").
+		Preload("Competitors", func(db *gorm.DB) *gorm.DB { return db.Order("added_at ASC") }).
+		Preload("Competitors.Product").
+		First(&group).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Failed to load analysis group for comparison", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	members := []compareMember{{
+		ProductID: group.MainProductID,
+		ASIN:      group.MainProduct.ASIN,
+		Title:     getStringValue(group.MainProduct.Title),
+		Role:      "main",
+	}}
+	for _, competitor := range group.Competitors {
+		members = append(members, compareMember{
+			ProductID: competitor.ProductID,
+			ASIN:      competitor.Product.ASIN,
+			Title:     getStringValue(competitor.Product.Title),
+			Role:      "competitor",
+		})
+	}
+	if len(members) > compareMaxProducts {
+		members = members[:compareMaxProducts]
+	}
+	return members, nil
+}
@@ -0,0 +1,71 @@
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateTagLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateTagLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateTagLogic {
+	return &CreateTagLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateTagLogic) CreateTag(req *types.CreateTagRequest) (resp *types.Tag, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	color := strings.TrimSpace(req.Color)
+	if err := utils.ValidateTagName(name); err != nil {
+		return nil, err
+	}
+	if err := utils.ValidateTagColor(color); err != nil {
+		return nil, err
+	}
+
+	taken, err := tagNameTaken(l.svcCtx.DB, access.WorkspaceID, name, "")
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to check tag name", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+	if taken {
+		return nil, errors.NewConflictError("Tag already exists")
+	}
+
+	tag := models.Tag{UserID: access.UserID, WorkspaceID: access.WorkspaceID, Name: name, Color: color}
+	if err := l.svcCtx.DB.Create(&tag).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to create tag", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 记录业务日志
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "create_tag", "tag", tag.ID, "success",
+		"user_id", access.UserID,
+		"name", tag.Name)
+
+	result := toTagResponse(tag, 0)
+	return &result, nil
+}
@@ -0,0 +1,52 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	"context"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteSellerAccountLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteSellerAccountLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteSellerAccountLogic {
+	return &DeleteSellerAccountLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteSellerAccountLogic) DeleteSellerAccount(req *types.DeleteSellerAccountRequest) (resp *types.DeleteSellerAccountResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	result := l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.SellerAccountID, access.WorkspaceID).Delete(&models.SellerAccount{})
+	if result.Error != nil {
+		l.Errorf("Failed to delete seller account: %v", result.Error)
+		return nil, errors.ErrInternalServer
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.ErrNotFound
+	}
+
+	// 记录业务日志
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "delete_seller_account", "seller_account", req.SellerAccountID, "success")
+
+	return &types.DeleteSellerAccountResponse{
+		Message: "Seller account deleted successfully",
+	}, nil
+}
@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"context"
+
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type DeleteTagLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteTagLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteTagLogic {
+	return &DeleteTagLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteTagLogic) DeleteTag(req *types.DeleteTagRequest) (resp *types.DeleteTagResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := findWorkspaceTag(l.svcCtx.DB, req.TagID, access.WorkspaceID)
+	if err == errors.ErrNotFound {
+		return nil, err
+	} else if err != nil {
+		utils.LogError(l.ctx, "Failed to load tag", "tag_id", req.TagID, "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 删除标签及其关联，追踪产品本身不受影响
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", tag.ID).Delete(&models.TrackedProductTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(tag).Error
+	})
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to delete tag", "tag_id", tag.ID, "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 记录业务日志
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "delete_tag", "tag", tag.ID, "success",
+		"user_id", access.UserID,
+		"name", tag.Name)
+
+	return &types.DeleteTagResponse{Message: "Tag deleted successfully"}, nil
+}
@@ -0,0 +1,78 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/export"
+	"amazonpilot/internal/pkg/tasks"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DownloadExportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDownloadExportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DownloadExportLogic {
+	return &DownloadExportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// DownloadExport 下载已完成的后台导出文件 (支持 Range 断点续传)
+func (l *DownloadExportLogic) DownloadExport(req *types.DownloadExportRequest, w http.ResponseWriter, r *http.Request) error {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return err
+	}
+
+	job, err := findExportJob(l.ctx, l.svcCtx.DB, req.JobID, access.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	expired := job.Status == "expired" || (job.ExpiresAt != nil && job.ExpiresAt.Before(time.Now()))
+	if expired {
+		return errors.NewAPIError(http.StatusGone, errors.CodeNotFound, "Export file has expired")
+	}
+	if job.Status != "completed" {
+		return errors.NewConflictError("Export is not ready yet")
+	}
+
+	file, err := os.Open(tasks.ExportFilePath(l.svcCtx.Config.EnvConfig.Export.Dir, job.ID, job.Format))
+	if os.IsNotExist(err) {
+		return errors.NewAPIError(http.StatusGone, errors.CodeNotFound, "Export file has expired")
+	} else if err != nil {
+		utils.LogError(l.ctx, "Failed to open export file", "error", err, "job_id", job.ID)
+		return errors.ErrInternalServer
+	}
+	defer file.Close()
+
+	fileName := getStringValue(job.FileName)
+	if fileName == "" {
+		fileName = job.ID + "." + job.Format
+	}
+	w.Header().Set("Content-Type", export.ContentType(job.Format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+
+	modTime := time.Now()
+	if job.CompletedAt != nil {
+		modTime = *job.CompletedAt
+	}
+	http.ServeContent(w, r, fileName, modTime, file)
+	return nil
+}
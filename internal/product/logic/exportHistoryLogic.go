@@ -0,0 +1,222 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/export"
+	"amazonpilot/internal/pkg/fx"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/tasks"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/hibiken/asynq"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// 超过以下产品数或天数时转为后台导出
+	exportSyncMaxProducts = 20
+	exportSyncMaxDays     = 92
+	// 单次导出上限
+	exportMaxProducts = 500
+	exportMaxDays     = 1830
+)
+
+type ExportHistoryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewExportHistoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ExportHistoryLogic {
+	return &ExportHistoryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ExportHistory 导出历史数据：小范围直接流式写入 w 并返回 nil，大范围创建后台任务并返回任务信息
+func (l *ExportHistoryLogic) ExportHistory(req *types.ExportHistoryRequest, w http.ResponseWriter) (resp *types.ExportHistoryResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	params, format, err := parseExportParams(req)
+	if err != nil {
+		return nil, err
+	}
+	// 只导出计划保留期内的历史数据
+	if params.From, err = quota.HistoryStart(l.ctx, l.svcCtx.DB, access.WorkspaceID, params.From); err != nil {
+		return nil, err
+	}
+
+	products, err := export.LoadProducts(l.svcCtx.DB, access.WorkspaceID, params.TrackedIDs, params.TagIDs)
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to load tracked products for export", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+	if len(params.TrackedIDs) > 0 && len(params.TagIDs) == 0 && len(products) != len(params.TrackedIDs) {
+		return nil, errors.ErrNotFound
+	}
+	if len(products) == 0 {
+		return nil, errors.NewValidationError("No products to export", []errors.FieldError{
+			{Field: "tracked_ids", Message: "No tracked products found"},
+		})
+	}
+	if len(products) > exportMaxProducts {
+		return nil, errors.NewValidationError("Too many products", []errors.FieldError{
+			{Field: "tracked_ids", Message: fmt.Sprintf("Export is limited to %d products", exportMaxProducts)},
+		})
+	}
+
+	days := int(params.To.Sub(params.From).Hours() / 24)
+	if req.Async || len(products) > exportSyncMaxProducts || days > exportSyncMaxDays {
+		return l.queueExport(access, format, params)
+	}
+
+	// 同步导出：逐行查询并写入响应，写出开始后出错只能记录日志
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.FileName(format, params.From, params.To)))
+
+	writer, err := export.NewWriter(format, w)
+	if err != nil {
+		return nil, errors.ErrInternalServer
+	}
+	start := time.Now()
+	rowCount, err := export.Export(l.svcCtx.DB, writer, export.Options{
+		Products:  products,
+		Metrics:   params.Metrics,
+		From:      params.From,
+		To:        params.To,
+		Currency:  params.Currency,
+		Converter: l.svcCtx.FX,
+	})
+	if err != nil {
+		utils.LogError(l.ctx, "History export interrupted", "error", err, "rows", rowCount)
+		return nil, nil
+	}
+
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "history_exported", "export", access.UserID, "success",
+		"format", format,
+		"products", len(products),
+		"metrics", strings.Join(params.Metrics, ","),
+		"rows", rowCount,
+		"duration_ms", time.Since(start).Milliseconds())
+
+	return nil, nil
+}
+
+// queueExport 创建后台导出任务
+func (l *ExportHistoryLogic) queueExport(access *workspace.Access, format string, params tasks.HistoryExportParams) (*types.ExportHistoryResponse, error) {
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		l.Errorf("Failed to marshal export params: %v", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	job := models.ExportJob{
+		UserID:      access.UserID,
+		WorkspaceID: access.WorkspaceID,
+		Status:      "queued",
+		Format:      format,
+		Params:      paramsJSON,
+	}
+	if err := l.svcCtx.DB.Create(&job).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to create export job", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	payload, _ := json.Marshal(tasks.HistoryExportPayload{JobID: job.ID, UserID: access.UserID})
+	info, err := l.svcCtx.AsynqClient.Enqueue(asynq.NewTask(tasks.TypeHistoryExport, payload))
+	if err != nil {
+		l.Errorf("Failed to enqueue history export task: %v", err)
+		errorMsg := "failed to enqueue export task"
+		l.svcCtx.DB.Model(&job).Updates(map[string]interface{}{"status": "failed", "error_message": errorMsg})
+		return nil, errors.ErrInternalServer
+	}
+
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "history_export_queued", "export_job", job.ID, "success",
+		"format", format,
+		"metrics", strings.Join(params.Metrics, ","),
+		"task_id", info.ID)
+
+	return &types.ExportHistoryResponse{
+		JobID:  job.ID,
+		Status: job.Status,
+		Format: format,
+	}, nil
+}
+
+// parseExportParams 校验导出请求参数，结束日期包含在导出范围内
+func parseExportParams(req *types.ExportHistoryRequest) (tasks.HistoryExportParams, string, error) {
+	var params tasks.HistoryExportParams
+
+	format, err := export.ParseFormat(req.Format)
+	if err != nil {
+		return params, "", errors.NewValidationError("Invalid format", []errors.FieldError{
+			{Field: "format", Message: err.Error()},
+		})
+	}
+
+	params.Metrics, err = export.ParseMetrics(req.Metrics)
+	if err == nil && len(params.Metrics) == 0 {
+		err = fmt.Errorf("at least one metric is required")
+	}
+	if err != nil {
+		return params, "", errors.NewValidationError("Invalid metrics", []errors.FieldError{
+			{Field: "metrics", Message: err.Error()},
+		})
+	}
+
+	startDate, endDate, err := parseDateRange(req.StartDate, req.EndDate, 30)
+	if err != nil {
+		return params, "", err
+	}
+	params.From = startDate
+	params.To = endDate.AddDate(0, 0, 1)
+	if params.To.Sub(params.From) > exportMaxDays*24*time.Hour {
+		return params, "", errors.NewValidationError("Invalid date range", []errors.FieldError{
+			{Field: "start_date", Message: fmt.Sprintf("Date range must not exceed %d days", exportMaxDays)},
+		})
+	}
+
+	if req.Currency != "" {
+		params.Currency = fx.NormalizeCurrency(req.Currency)
+	}
+
+	params.TrackedIDs = splitIDs(req.TrackedIDs)
+	params.TagIDs = splitIDs(req.TagIDs)
+	if len(params.TrackedIDs) > exportMaxProducts {
+		return params, "", errors.NewValidationError("Too many products", []errors.FieldError{
+			{Field: "tracked_ids", Message: fmt.Sprintf("Export is limited to %d products", exportMaxProducts)},
+		})
+	}
+
+	return params, format, nil
+}
+
+// splitIDs 解析逗号分隔的ID列表，去除空白和重复项并保持顺序
+func splitIDs(value string) []string {
+	ids := []string{}
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" && !containsString(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
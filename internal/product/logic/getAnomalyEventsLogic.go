@@ -2,12 +2,17 @@ package logic
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"amazonpilot/internal/product/svc"
 	"amazonpilot/internal/product/types"
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	pkgtypes "amazonpilot/pkg/types"
 
 	"github.com/zeromicro/go-zero/core/logx"
 )
@@ -27,21 +32,37 @@ func NewGetAnomalyEventsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *
 }
 
 func (l *GetAnomalyEventsLogic) GetAnomalyEvents(req *types.GetAnomalyEventsRequest) (resp *types.GetAnomalyEventsResponse, err error) {
-	// 从JWT context获取用户ID
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
 	if err != nil {
 		return nil, err
 	}
 
-	// 设置分页参数
+	cursor, err := utils.ParseCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只返回计划保留期内的事件 (不限时为零值)
+	since, err := quota.HistoryStart(l.ctx, l.svcCtx.DB, access.WorkspaceID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	// 设置分页参数，游标模式不使用 offset
 	offset := (req.Page - 1) * req.Limit
+	if cursor != nil {
+		offset = 0
+	}
 
 	// 构建查询条件 - 更新表名为 product_anomaly_events
 	query := l.svcCtx.DB.Table("product_anomaly_events ae").
-		Select("ae.*, p.title as product_title").
+		Select("ae.*, p.title as product_title, tp.id as tracked_id").
 		Joins("INNER JOIN tracked_products tp ON ae.product_id = tp.product_id").
 		Joins("INNER JOIN products p ON tp.product_id = p.id").
-		Where("tp.user_id = ?", userIDStr)
+		Where("tp.workspace_id = ?", access.WorkspaceID).
+		Where("ae.workspace_id IS NULL OR ae.workspace_id = tp.workspace_id"). // 其他工作区的私有事件不可见
+		Where("ae.created_at >= ?", since)
 
 	// 添加筛选条件
 	if req.EventType != "" {
@@ -53,27 +74,54 @@ func (l *GetAnomalyEventsLogic) GetAnomalyEvents(req *types.GetAnomalyEventsRequ
 	if req.ASIN != "" {
 		query = query.Where("ae.asin = ?", req.ASIN)
 	}
+	if tagIDs := splitIDs(req.TagIDs); len(tagIDs) > 0 {
+		query = query.Where("tp.id IN (SELECT tracked_product_id FROM tracked_product_tags WHERE tag_id IN ?)", tagIDs)
+	}
 
-	// 查询总数
+	// 查询总数，游标模式不统计 (分区表上 COUNT 代价较高)
 	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		l.Errorf("Failed to count anomaly events: %v", err)
-		return nil, errors.ErrInternalServer
+	if cursor == nil {
+		if err := query.Count(&total).Error; err != nil {
+			l.Errorf("Failed to count anomaly events: %v", err)
+			return nil, errors.ErrInternalServer
+		}
+	} else {
+		cond, args := cursor.Condition("ae.created_at", "ae.id", true)
+		query = query.Where(cond, args...)
 	}
 
 	// 查询异常事件列表
 	var anomalyEvents []struct {
 		models.AnomalyEvent
 		ProductTitle string `json:"product_title"`
+		TrackedID    string `json:"tracked_id"`
 	}
 
-	if err := query.Order("ae.created_at DESC").
+	// 多取一条用于判断是否还有下一页
+	if err := query.Order(pkgtypes.KeysetOrder("ae.created_at", "ae.id", true)).
 		Offset(offset).
-		Limit(req.Limit).
+		Limit(req.Limit + 1).
 		Scan(&anomalyEvents).Error; err != nil {
 		l.Errorf("Failed to query anomaly events: %v", err)
 		return nil, errors.ErrInternalServer
 	}
+	fetched := len(anomalyEvents)
+	if fetched > req.Limit {
+		anomalyEvents = anomalyEvents[:req.Limit]
+	}
+
+	// 查询事件对应追踪产品的标签
+	trackedIDs := []string{}
+	for _, ae := range anomalyEvents {
+		if !containsString(trackedIDs, ae.TrackedID) {
+			trackedIDs = append(trackedIDs, ae.TrackedID)
+		}
+	}
+	tagRefs, err := loadTagRefs(l.svcCtx.DB, trackedIDs)
+	if err != nil {
+		l.Errorf("Failed to query tracked product tags: %v", err)
+		return nil, errors.ErrInternalServer
+	}
 
 	// 转换为响应格式
 	events := make([]types.AnomalyEvent, 0, len(anomalyEvents))
@@ -86,6 +134,8 @@ func (l *GetAnomalyEventsLogic) GetAnomalyEvents(req *types.GetAnomalyEventsRequ
 			Severity:         ae.Severity,
 			CreatedAt:        ae.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			ProductTitle:     ae.ProductTitle,
+			TrackedID:        ae.TrackedID,
+			Tags:             tagRefs[ae.TrackedID],
 		}
 
 		// 安全处理指针字段
@@ -101,23 +151,32 @@ func (l *GetAnomalyEventsLogic) GetAnomalyEvents(req *types.GetAnomalyEventsRequ
 		if ae.Threshold != nil {
 			event.Threshold = *ae.Threshold
 		}
+		if ae.Metadata != nil {
+			json.Unmarshal(ae.Metadata, &event.Metadata)
+		}
 
 		events = append(events, event)
 	}
 
-	// 计算总页数
-	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
-
+	page := pkgtypes.NewCursorResponse(fetched, req.Limit, func() (time.Time, string) {
+		last := anomalyEvents[len(anomalyEvents)-1]
+		return last.CreatedAt, last.ID
+	})
 	resp = &types.GetAnomalyEventsResponse{
 		Events: events,
 		Pagination: types.Pagination{
-			Page:       req.Page,
 			Limit:      req.Limit,
-			Total:      int(total),
-			TotalPages: totalPages,
+			NextCursor: page.NextCursor,
+			HasMore:    page.HasMore,
 		},
 	}
+	if cursor == nil {
+		// 计算总页数
+		resp.Pagination.Page = req.Page
+		resp.Pagination.Total = int(total)
+		resp.Pagination.TotalPages = int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	}
 
-	l.Infof("Retrieved %d anomaly events for user %s", len(events), userIDStr)
+	l.Infof("Retrieved %d anomaly events for user %s", len(events), access.UserID)
 	return resp, nil
 }
\ No newline at end of file
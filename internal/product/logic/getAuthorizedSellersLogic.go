@@ -0,0 +1,76 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	"context"
+	"encoding/json"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetAuthorizedSellersLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetAuthorizedSellersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetAuthorizedSellersLogic {
+	return &GetAuthorizedSellersLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetAuthorizedSellersLogic) GetAuthorizedSellers(req *types.GetAuthorizedSellersRequest) (resp *types.AuthorizedSellersResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证用户是否有权限访问这个产品
+	var trackedProduct models.TrackedProduct
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).First(&trackedProduct).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Database error when checking product access", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	return buildAuthorizedSellersResponse(l.svcCtx.DB, &trackedProduct)
+}
+
+// buildAuthorizedSellersResponse 组装产品授权卖家与工作区自有卖家
+func buildAuthorizedSellersResponse(db *gorm.DB, trackedProduct *models.TrackedProduct) (*types.AuthorizedSellersResponse, error) {
+	var accounts []models.SellerAccount
+	if err := db.Where("workspace_id = ?", trackedProduct.WorkspaceID).Order("created_at ASC").Find(&accounts).Error; err != nil {
+		return nil, errors.ErrInternalServer
+	}
+
+	var allowList []models.SellerIdentity
+	if trackedProduct.AuthorizedSellers != nil {
+		json.Unmarshal(trackedProduct.AuthorizedSellers, &allowList)
+	}
+
+	sellers := make([]types.AuthorizedSeller, 0, len(allowList))
+	for _, seller := range allowList {
+		sellers = append(sellers, types.AuthorizedSeller{
+			SellerName: seller.SellerName,
+			SellerID:   seller.SellerID,
+		})
+	}
+
+	return &types.AuthorizedSellersResponse{
+		ProductID:  trackedProduct.ID,
+		Sellers:    sellers,
+		OwnSellers: toSellerAccounts(accounts),
+	}, nil
+}
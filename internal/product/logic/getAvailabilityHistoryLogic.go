@@ -0,0 +1,103 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/estimation"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	"context"
+	"math"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetAvailabilityHistoryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetAvailabilityHistoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetAvailabilityHistoryLogic {
+	return &GetAvailabilityHistoryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetAvailabilityHistoryLogic) GetAvailabilityHistory(req *types.GetAvailabilityRequest) (resp *types.GetAvailabilityResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证用户是否有权限访问这个产品
+	var trackedProduct models.TrackedProduct
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).First(&trackedProduct).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Database error when checking product access", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	period, startTime := utils.PeriodStartTime(req.Period)
+	// 只返回计划保留期内的历史数据
+	if startTime, err = quota.HistoryStart(l.ctx, l.svcCtx.DB, access.WorkspaceID, startTime); err != nil {
+		return nil, err
+	}
+
+	var history []models.AvailabilityHistory
+	if err := l.svcCtx.DB.Where("product_id = ? AND recorded_at >= ?", trackedProduct.ProductID, startTime).
+		Order("recorded_at ASC").
+		Find(&history).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to get availability history", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp = &types.GetAvailabilityResponse{
+		ProductID:    req.ProductID,
+		Period:       period,
+		CurrentState: estimation.AvailabilityUnknown,
+		Points:       make([]types.AvailabilityPoint, 0, len(history)),
+	}
+
+	known, available := 0, 0
+	previous := ""
+	for _, h := range history {
+		point := types.AvailabilityPoint{
+			RecordedAt:       h.RecordedAt.Format("2006-01-02T15:04:05Z07:00"),
+			State:            h.State,
+			AvailabilityText: getStringValue(h.AvailabilityText),
+		}
+		if h.StockQuantity != nil {
+			point.StockQuantity = *h.StockQuantity
+		}
+		resp.Points = append(resp.Points, point)
+
+		if h.State == estimation.AvailabilityUnknown {
+			continue
+		}
+		known++
+		if estimation.IsAvailable(h.State) {
+			available++
+		}
+		if estimation.AvailabilityTransition(previous, h.State) == "out_of_stock" {
+			resp.StockoutCount++
+		}
+		previous = h.State
+		resp.CurrentState = h.State
+	}
+
+	if known > 0 {
+		resp.InStockPercent = math.Round(float64(available)/float64(known)*10000) / 100
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,95 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetBuyBoxShareLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetBuyBoxShareLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetBuyBoxShareLogic {
+	return &GetBuyBoxShareLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetBuyBoxShareLogic) GetBuyBoxShare(req *types.GetBuyBoxShareRequest) (resp *types.GetBuyBoxShareResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证用户是否有权限访问这个产品
+	var trackedProduct models.TrackedProduct
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).First(&trackedProduct).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Database error when checking product access", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	period, startTime := utils.PeriodStartTime(req.Period)
+
+	// 每条 Buy Box 历史记录对应一次快照，按赢家汇总
+	var rows []struct {
+		WinnerSeller   string
+		WinnerSellerID *string
+		Wins           int
+		LastWonAt      time.Time
+	}
+	if err := l.svcCtx.DB.Model(&models.BuyBoxHistory{}).
+		Select("winner_seller, winner_seller_id, COUNT(*) AS wins, MAX(recorded_at) AS last_won_at").
+		Where("product_id = ? AND recorded_at >= ? AND winner_seller IS NOT NULL AND winner_seller <> ''", trackedProduct.ProductID, startTime).
+		Group("winner_seller, winner_seller_id").
+		Scan(&rows).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to aggregate buy box winners", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	total := 0
+	for _, row := range rows {
+		total += row.Wins
+	}
+
+	sellers := make([]types.BuyBoxSellerShare, 0, len(rows))
+	for _, row := range rows {
+		sellers = append(sellers, types.BuyBoxSellerShare{
+			SellerName:   row.WinnerSeller,
+			SellerID:     getStringValue(row.WinnerSellerID),
+			Wins:         row.Wins,
+			SharePercent: math.Round(float64(row.Wins)/float64(total)*10000) / 100,
+			LastWonAt:    row.LastWonAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+	sort.SliceStable(sellers, func(i, j int) bool {
+		return sellers[i].Wins > sellers[j].Wins
+	})
+
+	resp = &types.GetBuyBoxShareResponse{
+		ProductID:      req.ProductID,
+		Period:         period,
+		TotalSnapshots: total,
+		Sellers:        sellers,
+	}
+
+	return resp, nil
+}
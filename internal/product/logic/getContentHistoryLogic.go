@@ -0,0 +1,169 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/estimation"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	pkgtypes "amazonpilot/pkg/types"
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetContentHistoryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetContentHistoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetContentHistoryLogic {
+	return &GetContentHistoryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetContentHistoryLogic) GetContentHistory(req *types.GetContentHistoryRequest) (resp *types.GetContentHistoryResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证用户是否有权限访问这个产品
+	var trackedProduct models.TrackedProduct
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).
+		Preload("Product").
+		First(&trackedProduct).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Database error when checking product access", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	cursor, err := utils.ParseCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只返回计划保留期内的快照 (不限时为零值)
+	since, err := quota.HistoryStart(l.ctx, l.svcCtx.DB, access.WorkspaceID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	// 游标模式不统计总数，也不使用 offset
+	var total int64
+	offset := (req.Page - 1) * req.Limit
+	query := l.svcCtx.DB.Where("product_id = ? AND recorded_at >= ?", trackedProduct.ProductID, since)
+	if cursor == nil {
+		if err := l.svcCtx.DB.Model(&models.ListingSnapshot{}).
+			Where("product_id = ? AND recorded_at >= ?", trackedProduct.ProductID, since).
+			Count(&total).Error; err != nil {
+			l.Errorf("Failed to count content snapshots: %v", err)
+			return nil, errors.ErrInternalServer
+		}
+	} else {
+		offset = 0
+		cond, args := cursor.Condition("recorded_at", "id", true)
+		query = query.Where(cond, args...)
+	}
+
+	// 多取一条更早的快照，用于计算本页最后一个版本的差异，同时判断是否还有下一页
+	var snapshots []models.ListingSnapshot
+	if err := query.
+		Order(pkgtypes.KeysetOrder("recorded_at", "id", true)).
+		Offset(offset).
+		Limit(req.Limit + 1).
+		Find(&snapshots).Error; err != nil {
+		l.Errorf("Failed to query content snapshots: %v", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	timeline := make([]types.ContentChange, 0, req.Limit)
+	for i := 0; i < len(snapshots) && i < req.Limit; i++ {
+		change := types.ContentChange{
+			SnapshotID:    snapshots[i].ID,
+			RecordedAt:    snapshots[i].RecordedAt.Format("2006-01-02T15:04:05Z07:00"),
+			ChangedFields: []string{},
+			Changes:       []types.ContentFieldDiff{},
+		}
+
+		if i+1 >= len(snapshots) {
+			change.IsBaseline = true
+			timeline = append(timeline, change)
+			continue
+		}
+
+		previous := estimation.ListingContentFromSnapshot(&snapshots[i+1])
+		current := estimation.ListingContentFromSnapshot(&snapshots[i])
+		for _, diff := range estimation.DiffListingContent(previous, current) {
+			change.ChangedFields = append(change.ChangedFields, diff.Field)
+			change.Changes = append(change.Changes, toContentFieldDiff(diff))
+		}
+		timeline = append(timeline, change)
+	}
+
+	page := pkgtypes.NewCursorResponse(len(snapshots), req.Limit, func() (time.Time, string) {
+		last := snapshots[req.Limit-1]
+		return last.RecordedAt, last.ID
+	})
+	resp = &types.GetContentHistoryResponse{
+		ProductID: req.ProductID,
+		ASIN:      trackedProduct.Product.ASIN,
+		Timeline:  timeline,
+		Pagination: types.Pagination{
+			Limit:      req.Limit,
+			NextCursor: page.NextCursor,
+			HasMore:    page.HasMore,
+		},
+	}
+	if cursor == nil {
+		// 计算总页数
+		resp.Pagination.Page = req.Page
+		resp.Pagination.Total = int(total)
+		resp.Pagination.TotalPages = int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	}
+
+	// 记录业务日志
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "get_content_history", "product", trackedProduct.ProductID, "success",
+		"asin", trackedProduct.Product.ASIN,
+		"versions", len(timeline))
+
+	return resp, nil
+}
+
+// toContentFieldDiff 转换为前后对照的响应格式
+func toContentFieldDiff(diff estimation.ListingFieldDiff) types.ContentFieldDiff {
+	result := types.ContentFieldDiff{
+		Field:   diff.Field,
+		Added:   diff.Added,
+		Removed: diff.Removed,
+	}
+
+	switch before := diff.Old.(type) {
+	case string:
+		result.Before = before
+	case []string:
+		result.BeforeItems = before
+	}
+	switch after := diff.New.(type) {
+	case string:
+		result.After = after
+	case []string:
+		result.AfterItems = after
+	}
+
+	return result
+}
@@ -0,0 +1,97 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/tasks"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetExportJobLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetExportJobLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetExportJobLogic {
+	return &GetExportJobLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetExportJobLogic) GetExportJob(req *types.GetExportJobRequest) (resp *types.GetExportJobResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := findExportJob(l.ctx, l.svcCtx.DB, req.JobID, access.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var params tasks.HistoryExportParams
+	if err := json.Unmarshal(job.Params, &params); err != nil {
+		l.Errorf("Failed to decode export params for job %s: %v", job.ID, err)
+	}
+
+	resp = &types.GetExportJobResponse{
+		JobID:        job.ID,
+		Status:       job.Status,
+		Format:       job.Format,
+		TrackedIDs:   params.TrackedIDs,
+		Metrics:      params.Metrics,
+		RowCount:     job.RowCount,
+		FileName:     getStringValue(job.FileName),
+		FileSize:     job.FileSize,
+		ErrorMessage: getStringValue(job.ErrorMessage),
+		CreatedAt:    job.CreatedAt.Format(time.RFC3339),
+	}
+	if resp.TrackedIDs == nil {
+		resp.TrackedIDs = []string{}
+	}
+	if !params.From.IsZero() {
+		resp.StartDate = params.From.Format("2006-01-02")
+		resp.EndDate = params.To.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	if job.Status == "completed" {
+		resp.DownloadURL = "/api/product/products/exports/" + job.ID + "/download"
+	}
+	if job.StartedAt != nil {
+		resp.StartedAt = job.StartedAt.Format(time.RFC3339)
+	}
+	if job.CompletedAt != nil {
+		resp.CompletedAt = job.CompletedAt.Format(time.RFC3339)
+	}
+	if job.ExpiresAt != nil {
+		resp.ExpiresAt = job.ExpiresAt.Format(time.RFC3339)
+	}
+
+	return resp, nil
+}
+
+// findExportJob 查询工作区的导出任务
+func findExportJob(ctx context.Context, db *gorm.DB, jobID, workspaceID string) (*models.ExportJob, error) {
+	var job models.ExportJob
+	err := db.Where("id = ? AND workspace_id = ?", jobID, workspaceID).First(&job).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(ctx, "Database error when querying export job", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+	return &job, nil
+}
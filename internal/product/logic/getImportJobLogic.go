@@ -0,0 +1,78 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetImportJobLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetImportJobLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetImportJobLogic {
+	return &GetImportJobLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetImportJobLogic) GetImportJob(req *types.GetImportJobRequest) (resp *types.GetImportJobResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	var job models.ImportJob
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.JobID, access.WorkspaceID).First(&job).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Database error when querying import job", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp = &types.GetImportJobResponse{
+		JobID:          job.ID,
+		Status:         job.Status,
+		Format:         job.Format,
+		Total:          job.TotalRows,
+		Processed:      job.ProcessedRows,
+		Created:        job.CreatedRows,
+		AlreadyTracked: job.ExistingRows,
+		Failed:         job.FailedRows,
+		ErrorMessage:   getStringValue(job.ErrorMessage),
+		CreatedAt:      job.CreatedAt.Format(time.RFC3339),
+	}
+	if job.TotalRows > 0 {
+		resp.Progress = job.ProcessedRows * 100 / job.TotalRows
+	}
+	if job.StartedAt != nil {
+		resp.StartedAt = job.StartedAt.Format(time.RFC3339)
+	}
+	if job.CompletedAt != nil {
+		resp.CompletedAt = job.CompletedAt.Format(time.RFC3339)
+	}
+
+	if len(job.Results) > 0 {
+		if err := json.Unmarshal(job.Results, &resp.Results); err != nil {
+			l.Errorf("Failed to decode import results for job %s: %v", job.ID, err)
+		}
+	}
+
+	return resp, nil
+}
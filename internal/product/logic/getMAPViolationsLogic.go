@@ -0,0 +1,216 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetMAPViolationsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetMAPViolationsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetMAPViolationsLogic {
+	return &GetMAPViolationsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetMAPViolationsLogic) GetMAPViolations(req *types.GetMAPViolationsRequest) (resp *types.GetMAPViolationsResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, endDate, err := parseDateRange(req.StartDate, req.EndDate, 30)
+	if err != nil {
+		return nil, err
+	}
+	// 只返回计划保留期内的违规证据
+	if startDate, err = quota.HistoryStart(l.ctx, l.svcCtx.DB, access.WorkspaceID, startDate); err != nil {
+		return nil, err
+	}
+	if req.Format != "" && req.Format != "json" && req.Format != "csv" {
+		return nil, errors.NewValidationError("Invalid format", []errors.FieldError{
+			{Field: "format", Message: "format must be json or csv"},
+		})
+	}
+
+	// 验证用户是否有权限访问这个产品
+	var trackedProduct models.TrackedProduct
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).
+		Preload("Product").
+		First(&trackedProduct).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Database error when checking product access", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// MAP 按追踪记录配置，只统计当前工作区该追踪记录产生的违规事件
+	var events []models.AnomalyEvent
+	if err := l.svcCtx.DB.Where("product_id = ? AND workspace_id = ? AND event_type = ? AND created_at >= ? AND created_at < ? AND metadata->>'tracked_id' = ?",
+		trackedProduct.ProductID, access.WorkspaceID, "map_violation", startDate, endDate.AddDate(0, 0, 1), trackedProduct.ID).
+		Order("created_at ASC").
+		Find(&events).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to query MAP violations", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp = &types.GetMAPViolationsResponse{
+		ProductID:       trackedProduct.ID,
+		ASIN:            trackedProduct.Product.ASIN,
+		StartDate:       startDate.Format("2006-01-02"),
+		EndDate:         endDate.Format("2006-01-02"),
+		TotalViolations: len(events),
+		Sellers:         []types.MAPViolationSeller{},
+	}
+	if trackedProduct.MAPPrice != nil {
+		resp.MAPPrice = *trackedProduct.MAPPrice
+	}
+
+	// 按卖家汇总违规证据
+	sellers := map[string]*types.MAPViolationSeller{}
+	order := []string{}
+	for _, event := range events {
+		var metadata struct {
+			SellerKey      string  `json:"seller_key"`
+			SellerName     string  `json:"seller_name"`
+			SellerID       *string `json:"seller_id"`
+			Price          float64 `json:"price"`
+			Currency       string  `json:"currency"`
+			IsBuyBoxWinner bool    `json:"is_buy_box_winner"`
+			ObservedAt     string  `json:"observed_at"`
+		}
+		if err := json.Unmarshal(event.Metadata, &metadata); err != nil {
+			continue
+		}
+
+		seller, ok := sellers[metadata.SellerKey]
+		if !ok {
+			seller = &types.MAPViolationSeller{
+				SellerName:  metadata.SellerName,
+				SellerID:    getStringValue(metadata.SellerID),
+				LowestPrice: metadata.Price,
+				FirstSeenAt: metadata.ObservedAt,
+				Evidence:    []types.MAPViolation{},
+			}
+			sellers[metadata.SellerKey] = seller
+			order = append(order, metadata.SellerKey)
+		}
+
+		discount := 0.0
+		if event.ChangePercentage != nil {
+			discount = *event.ChangePercentage
+		}
+
+		seller.Violations++
+		seller.LastSeenAt = metadata.ObservedAt
+		if metadata.Price < seller.LowestPrice {
+			seller.LowestPrice = metadata.Price
+		}
+		if discount > seller.MaxDiscountPercent {
+			seller.MaxDiscountPercent = discount
+		}
+		seller.Evidence = append(seller.Evidence, types.MAPViolation{
+			ObservedAt:      metadata.ObservedAt,
+			Price:           metadata.Price,
+			Currency:        metadata.Currency,
+			DiscountPercent: discount,
+			IsBuyBoxWinner:  metadata.IsBuyBoxWinner,
+		})
+	}
+
+	for _, key := range order {
+		resp.Sellers = append(resp.Sellers, *sellers[key])
+	}
+	sort.SliceStable(resp.Sellers, func(i, j int) bool {
+		return resp.Sellers[i].Violations > resp.Sellers[j].Violations
+	})
+
+	return resp, nil
+}
+
+// MAPViolationsCSV 将违规报告导出为CSV，每条证据一行
+func MAPViolationsCSV(resp *types.GetMAPViolationsResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	writer.Write([]string{"asin", "seller_name", "seller_id", "observed_at", "price", "currency", "map_price", "discount_percent", "buy_box_winner"})
+	for _, seller := range resp.Sellers {
+		for _, evidence := range seller.Evidence {
+			writer.Write([]string{
+				resp.ASIN,
+				seller.SellerName,
+				seller.SellerID,
+				evidence.ObservedAt,
+				strconv.FormatFloat(evidence.Price, 'f', 2, 64),
+				evidence.Currency,
+				strconv.FormatFloat(resp.MAPPrice, 'f', 2, 64),
+				strconv.FormatFloat(evidence.DiscountPercent, 'f', 2, 64),
+				strconv.FormatBool(evidence.IsBuyBoxWinner),
+			})
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, errors.ErrInternalServer
+	}
+	return buf.Bytes(), nil
+}
+
+// parseDateRange 解析 YYYY-MM-DD 日期范围，未指定时默认最近 defaultDays 天
+func parseDateRange(start, end string, defaultDays int) (time.Time, time.Time, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	endDate := today
+	if end != "" {
+		parsed, err := time.Parse("2006-01-02", end)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.NewValidationError("Invalid date range", []errors.FieldError{
+				{Field: "end_date", Message: "end_date must be in YYYY-MM-DD format"},
+			})
+		}
+		endDate = parsed
+	}
+
+	startDate := endDate.AddDate(0, 0, -defaultDays)
+	if start != "" {
+		parsed, err := time.Parse("2006-01-02", start)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.NewValidationError("Invalid date range", []errors.FieldError{
+				{Field: "start_date", Message: "start_date must be in YYYY-MM-DD format"},
+			})
+		}
+		startDate = parsed
+	}
+
+	if startDate.After(endDate) {
+		return time.Time{}, time.Time{}, errors.NewValidationError("Invalid date range", []errors.FieldError{
+			{Field: "start_date", Message: "start_date must not be after end_date"},
+		})
+	}
+
+	return startDate, endDate, nil
+}
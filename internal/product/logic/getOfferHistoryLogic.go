@@ -0,0 +1,104 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetOfferHistoryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetOfferHistoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetOfferHistoryLogic {
+	return &GetOfferHistoryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetOfferHistoryLogic) GetOfferHistory(req *types.GetOfferHistoryRequest) (resp *types.GetOfferHistoryResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证用户是否有权限访问这个产品
+	var trackedProduct models.TrackedProduct
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).First(&trackedProduct).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Database error when checking product access", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	period, startTime := utils.PeriodStartTime(req.Period)
+	// 只返回计划保留期内的历史数据
+	if startTime, err = quota.HistoryStart(l.ctx, l.svcCtx.DB, access.WorkspaceID, startTime); err != nil {
+		return nil, err
+	}
+
+	var offers []models.OfferHistory
+	if err := l.svcCtx.DB.Where("product_id = ? AND recorded_at >= ?", trackedProduct.ProductID, startTime).
+		Order("recorded_at ASC").
+		Find(&offers).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to get offer history", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 同一次刷新的报价共享 recorded_at，按快照汇总
+	snapshots := []types.OfferSnapshotSummary{}
+	var current *types.OfferSnapshotSummary
+	var currentAt time.Time
+	sellers := map[string]bool{}
+	for _, offer := range offers {
+		if current == nil || !offer.RecordedAt.Equal(currentAt) {
+			snapshots = append(snapshots, types.OfferSnapshotSummary{
+				RecordedAt: offer.RecordedAt.Format("2006-01-02T15:04:05Z07:00"),
+				Currency:   offer.Currency,
+			})
+			current = &snapshots[len(snapshots)-1]
+			currentAt = offer.RecordedAt
+			sellers = map[string]bool{}
+		}
+
+		key := sellerKey(offer.SellerName, offer.SellerID)
+		if !sellers[key] {
+			sellers[key] = true
+			current.SellerCount++
+		}
+		if offer.IsFBA {
+			current.FBACount++
+		}
+		if landed := offer.LandedPrice(); current.LowestPrice == 0 || landed < current.LowestPrice {
+			current.LowestPrice = landed
+			current.LowestSeller = offer.SellerName
+		}
+		if offer.IsBuyBoxWinner {
+			current.BuyBoxSeller = offer.SellerName
+			current.BuyBoxPrice = offer.Price
+		}
+	}
+
+	resp = &types.GetOfferHistoryResponse{
+		ProductID: req.ProductID,
+		Period:    period,
+		Snapshots: snapshots,
+	}
+
+	return resp, nil
+}
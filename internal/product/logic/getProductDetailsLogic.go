@@ -1,15 +1,16 @@
 package logic
 
 import (
+	"amazonpilot/internal/pkg/cache"
 	"amazonpilot/internal/pkg/constants"
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/logger"
 	"amazonpilot/internal/pkg/models"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
 	"amazonpilot/internal/product/svc"
 	"amazonpilot/internal/product/types"
 	"context"
-	"encoding/json"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
@@ -30,15 +31,15 @@ func NewGetProductDetailsLogic(ctx context.Context, svcCtx *svc.ServiceContext)
 }
 
 func (l *GetProductDetailsLogic) GetProductDetails(req *types.GetProductRequest) (resp *types.GetProductResponse, err error) {
-	// 从JWT context获取用户ID
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
 	if err != nil {
 		return nil, err
 	}
 
 	// 验证用户是否有权限访问这个产品
 	var trackedProduct models.TrackedProduct
-	err = l.svcCtx.DB.Where("id = ? AND user_id = ?", req.ProductID, userIDStr).First(&trackedProduct).Error
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).First(&trackedProduct).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, errors.ErrNotFound
 	} else if err != nil {
@@ -46,46 +47,50 @@ func (l *GetProductDetailsLogic) GetProductDetails(req *types.GetProductRequest)
 		return nil, errors.ErrInternalServer
 	}
 
-	// 获取产品详细信息
-	var product models.Product
-	err = l.svcCtx.DB.Where("id = ?", trackedProduct.ProductID).First(&product).Error
+	// 获取产品详细信息和最新数据，优先使用按产品缓存
+	product, err := l.svcCtx.ProductCache.GetOrLoad(l.ctx, trackedProduct.ProductID, func(ctx context.Context) (cache.ProductData, error) {
+		data, err := loadProductData(l.svcCtx.DB.WithContext(ctx), []string{trackedProduct.ProductID})
+		if err != nil {
+			return cache.ProductData{}, err
+		}
+		product, ok := data[trackedProduct.ProductID]
+		if !ok {
+			return cache.ProductData{}, gorm.ErrRecordNotFound
+		}
+		return product, nil
+	})
 	if err != nil {
 		utils.LogError(l.ctx, "Database error when fetching product", "error", err)
 		return nil, errors.ErrInternalServer
 	}
 
-	// 解析JSON字段
-	var images []string
-	var bulletPoints []string
-
-	if product.Images != nil {
-		json.Unmarshal(product.Images, &images)
-	}
-	if product.BulletPoints != nil {
-		json.Unmarshal(product.BulletPoints, &bulletPoints)
-	}
-
 	// 获取追踪历史统计
 	var priceChanges, bsrChanges, ratingChanges int64
-	l.svcCtx.DB.Model(&models.PriceHistory{}).Where("product_id = ?", product.ID).Count(&priceChanges)
-	l.svcCtx.DB.Model(&models.RankingHistory{}).Where("product_id = ?", product.ID).Count(&bsrChanges)
+	l.svcCtx.DB.Model(&models.PriceHistory{}).Where("product_id = ?", product.ProductID).Count(&priceChanges)
+	l.svcCtx.DB.Model(&models.RankingHistory{}).Where("product_id = ?", product.ProductID).Count(&bsrChanges)
 	// ratingChanges暂时设为0，实际应该查询rating变化历史
 
 	// 构建响应
 	resp = &types.GetProductResponse{
-		ID:           product.ID,
-		ASIN:         product.ASIN,
-		Title:        getStringValue(product.Title),
-		Description:  getStringValue(product.Description),
-		Brand:        getStringValue(product.Brand),
-		Category:     getStringValue(product.Category),
-		CurrentPrice: 0.0, // TODO: 从最新价格历史获取
-		Currency:     "USD",
-		BSR:          0,   // TODO: 从最新排名历史获取
-		Rating:       0.0, // TODO: 从最新排名历史获取
-		ReviewCount:  0,   // TODO: 从最新排名历史获取
-		Images:       images,
-		BulletPoints: bulletPoints,
+		ID:                   product.ProductID,
+		ASIN:                 product.ASIN,
+		Title:                product.Title,
+		Description:          product.Description,
+		Brand:                product.Brand,
+		Category:             product.Category,
+		CurrentPrice:         product.CurrentPrice,
+		Currency:             product.Currency,
+		BSR:                  product.BSR,
+		Rating:               product.Rating,
+		ReviewCount:          product.ReviewCount,
+		PriceChange24h:       product.PriceChange24h,
+		BuyBoxPrice:          product.BuyBoxPrice,
+		BSRChange24h:         product.BSRChange24h,
+		ReviewCountChange24h: product.ReviewCountChange24h,
+		Availability:         product.Availability,
+		LastUpdated:          product.LastUpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Images:               product.Images,
+		BulletPoints:         product.BulletPoints,
 		TrackingHistory: types.TrackingHistorySummary{
 			PriceChanges:  int(priceChanges),
 			BSRChanges:    int(bsrChanges),
@@ -93,9 +98,13 @@ func (l *GetProductDetailsLogic) GetProductDetails(req *types.GetProductRequest)
 		},
 		Alerts: []types.Alert{}, // TODO: 实现告警逻辑
 	}
+	if resp.Currency == "" {
+		// 尚未刷新过的产品没有价格数据
+		resp.Currency = "USD"
+	}
 
 	// 记录业务日志
-	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "get_product_details", "product", product.ID, "success",
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "get_product_details", "product", product.ProductID, "success",
 		"asin", product.ASIN,
 		"tracking_id", trackedProduct.ID)
 
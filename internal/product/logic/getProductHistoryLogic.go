@@ -4,11 +4,17 @@ import (
 	"amazonpilot/internal/pkg/constants"
 	"amazonpilot/internal/pkg/logger"
 	"context"
+	"strings"
 	"time"
 
 	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/estimation"
+	"amazonpilot/internal/pkg/fx"
 	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/timeseries"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
 	"amazonpilot/internal/product/svc"
 	"amazonpilot/internal/product/types"
 
@@ -16,6 +22,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// historyMetrics 历史接口支持的指标
+var historyMetrics = []string{"price", "bsr", "rating", "review_count", "buybox", "est_sales"}
+
 type GetProductHistoryLogic struct {
 	logx.Logger
 	ctx    context.Context
@@ -30,16 +39,35 @@ func NewGetProductHistoryLogic(ctx context.Context, svcCtx *svc.ServiceContext)
 	}
 }
 
+// historyPoint 单个原始数据点，价格类指标已按展示货币换算
+type historyPoint struct {
+	At               time.Time
+	Value            float64
+	Currency         string
+	OriginalValue    float64
+	OriginalCurrency string
+}
+
+// historyRange 查询时间范围和分桶参数
+type historyRange struct {
+	Period string
+	From   time.Time
+	To     time.Time
+	Bucket string
+	Agg    string
+	Loc    *time.Location
+}
+
 func (l *GetProductHistoryLogic) GetProductHistory(req *types.GetHistoryRequest) (resp *types.GetHistoryResponse, err error) {
-	// 从JWT context获取用户ID
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
 	if err != nil {
 		return nil, err
 	}
 
 	// 验证用户是否有权限访问这个产品
 	var trackedProduct models.TrackedProduct
-	err = l.svcCtx.DB.Where("id = ? AND user_id = ?", req.ProductID, userIDStr).First(&trackedProduct).Error
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).First(&trackedProduct).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, errors.ErrNotFound
 	} else if err != nil {
@@ -52,133 +80,379 @@ func (l *GetProductHistoryLogic) GetProductHistory(req *types.GetHistoryRequest)
 	if metric == "" {
 		metric = "price"
 	}
-	period := req.Period
-	if period == "" {
-		period = "30d"
+	if req.Currency != "" {
+		req.Currency = fx.NormalizeCurrency(req.Currency)
 	}
 
-	// 计算时间范围
-	var startTime time.Time
-	switch period {
-	case "7d":
-		startTime = time.Now().AddDate(0, 0, -7)
-	case "30d":
-		startTime = time.Now().AddDate(0, 0, -30)
-	case "90d":
-		startTime = time.Now().AddDate(0, 0, -90)
-	default:
-		startTime = time.Now().AddDate(0, 0, -30)
+	rng, err := parseHistoryRange(req.Period, req.From, req.To, req.Bucket, req.Agg, req.TZ)
+	if err != nil {
+		return nil, err
+	}
+	// 只返回计划保留期内的历史数据
+	if rng.From, err = quota.HistoryStart(l.ctx, l.svcCtx.DB, access.WorkspaceID, rng.From); err != nil {
+		return nil, err
 	}
 
-	var historyData []types.HistoryData
+	// metrics 参数优先，一次返回多个指标
+	metrics := []string{metric}
+	if req.Metrics != "" {
+		metrics = nil
+		for _, m := range strings.Split(req.Metrics, ",") {
+			if m = strings.ToLower(strings.TrimSpace(m)); m != "" && !containsString(metrics, m) {
+				metrics = append(metrics, m)
+			}
+		}
+	}
+	for _, m := range metrics {
+		if !containsString(historyMetrics, m) {
+			return nil, errors.NewValidationError("Invalid metric", []errors.FieldError{
+				{Field: "metric", Message: "Metric must be one of: " + strings.Join(historyMetrics, ", ")},
+			})
+		}
+	}
+	if len(metrics) == 0 {
+		return nil, errors.NewValidationError("Invalid metric", []errors.FieldError{
+			{Field: "metrics", Message: "At least one metric is required"},
+		})
+	}
 
-	// 根据指标类型查询不同的历史数据
-	switch metric {
-	case "price":
-		var priceHistory []models.PriceHistory
-		err = l.svcCtx.DB.Where("product_id = ? AND recorded_at >= ?", trackedProduct.ProductID, startTime).
-			Order("recorded_at ASC").Find(&priceHistory).Error
+	var salesEstimate *types.SalesEstimate
+	var categories []string
+	series := make([]types.HistorySeries, 0, len(metrics))
+	dataPoints := 0
+
+	for _, m := range metrics {
+		points, err := loadHistoryPoints(l.svcCtx, m, trackedProduct.ProductID, req.Category, req.Currency, rng)
 		if err != nil {
-			utils.LogError(l.ctx, "Failed to get price history", "error", err)
+			utils.LogError(l.ctx, "Failed to get product history", "metric", m, "error", err)
 			return nil, errors.ErrInternalServer
 		}
 
-		historyData = make([]types.HistoryData, len(priceHistory))
-		for i, ph := range priceHistory {
-			historyData[i] = types.HistoryData{
-				Date:     ph.RecordedAt.Format("2006-01-02"),
-				Value:    ph.Price,
-				Currency: ph.Currency,
+		s := types.HistorySeries{
+			Metric: m,
+			Data:   buildHistoryData(points, rng),
+		}
+		if m == "price" || m == "buybox" {
+			s.Currency = seriesCurrency(points, req.Currency)
+		}
+
+		switch m {
+		case "bsr":
+			s.Category = req.Category
+
+			// 可选的类目（根类目和小类目）
+			if categories == nil {
+				err = l.svcCtx.DB.Model(&models.CategoryRank{}).
+					Where("product_id = ? AND recorded_at >= ? AND recorded_at < ?", trackedProduct.ProductID, rng.From, rng.To).
+					Distinct("category").Order("category").Pluck("category", &categories).Error
+				if err != nil {
+					utils.LogError(l.ctx, "Failed to get BSR categories", "error", err)
+					return nil, errors.ErrInternalServer
+				}
+			}
+			fallthrough
+		case "est_sales":
+			if salesEstimate == nil {
+				salesEstimate, err = l.salesEstimate(trackedProduct.ProductID, rng.From, rng.To, req.Currency)
+				if err != nil {
+					utils.LogError(l.ctx, "Failed to estimate sales", "error", err)
+					return nil, errors.ErrInternalServer
+				}
 			}
 		}
 
+		dataPoints += len(s.Data)
+		series = append(series, s)
+	}
+
+	resp = &types.GetHistoryResponse{
+		ProductID:     req.ProductID,
+		Metric:        series[0].Metric,
+		Period:        rng.Period,
+		Currency:      req.Currency,
+		Category:      req.Category,
+		Categories:    categories,
+		Data:          series[0].Data,
+		From:          rng.From.In(rng.Loc).Format(time.RFC3339),
+		To:            rng.To.In(rng.Loc).Format(time.RFC3339),
+		Bucket:        rng.Bucket,
+		Agg:           rng.Agg,
+		TZ:            rng.Loc.String(),
+		SalesEstimate: salesEstimate,
+	}
+	if req.Metrics != "" {
+		resp.Series = series
+	}
+
+	// 记录业务日志
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "get_product_history", "product", req.ProductID, "success",
+		"metrics", strings.Join(metrics, ","),
+		"period", rng.Period,
+		"bucket", rng.Bucket,
+		"agg", rng.Agg,
+		"tz", rng.Loc.String(),
+		"currency", req.Currency,
+		"category", req.Category,
+		"data_points", dataPoints)
+
+	return resp, nil
+}
+
+// loadHistoryPoints 查询单个指标在 [From, To) 内的原始数据点（按时间升序）
+// category 仅用于 bsr，displayCurrency 用于价格类指标的货币换算
+func loadHistoryPoints(svcCtx *svc.ServiceContext, metric, productID, category, displayCurrency string, rng historyRange) ([]historyPoint, error) {
+	db := svcCtx.DB
+	var points []historyPoint
+
+	switch metric {
+	case "price":
+		var priceHistory []models.PriceHistory
+		if err := db.Where("product_id = ? AND recorded_at >= ? AND recorded_at < ?", productID, rng.From, rng.To).
+			Order("recorded_at ASC").Find(&priceHistory).Error; err != nil {
+			return nil, err
+		}
+		for _, ph := range priceHistory {
+			points = append(points, pricePoint(svcCtx.FX, ph.RecordedAt, ph.Price, ph.Currency, displayCurrency))
+		}
+
 	case "bsr":
+		if category != "" {
+			var categoryRanks []models.CategoryRank
+			if err := db.Where("product_id = ? AND LOWER(category) = LOWER(?) AND recorded_at >= ? AND recorded_at < ?", productID, category, rng.From, rng.To).
+				Order("recorded_at ASC").Find(&categoryRanks).Error; err != nil {
+				return nil, err
+			}
+			for _, cr := range categoryRanks {
+				points = append(points, historyPoint{At: cr.RecordedAt, Value: float64(cr.Rank)})
+			}
+			break
+		}
+
 		var rankingHistory []models.RankingHistory
-		err = l.svcCtx.DB.Where("product_id = ? AND recorded_at >= ? AND bsr_rank IS NOT NULL", trackedProduct.ProductID, startTime).
-			Order("recorded_at ASC").Find(&rankingHistory).Error
-		if err != nil {
-			utils.LogError(l.ctx, "Failed to get BSR history", "error", err)
-			return nil, errors.ErrInternalServer
+		if err := db.Where("product_id = ? AND recorded_at >= ? AND recorded_at < ? AND bsr_rank IS NOT NULL", productID, rng.From, rng.To).
+			Order("recorded_at ASC").Find(&rankingHistory).Error; err != nil {
+			return nil, err
+		}
+		for _, rh := range rankingHistory {
+			points = append(points, historyPoint{At: rh.RecordedAt, Value: float64(*rh.BSRRank)})
 		}
 
-		historyData = make([]types.HistoryData, len(rankingHistory))
-		for i, rh := range rankingHistory {
-			historyData[i] = types.HistoryData{
-				Date:  rh.RecordedAt.Format("2006-01-02"),
-				Value: float64(*rh.BSRRank),
-			}
+	case "est_sales":
+		var rankingHistory []models.RankingHistory
+		if err := db.Where("product_id = ? AND recorded_at >= ? AND recorded_at < ? AND bsr_rank IS NOT NULL", productID, rng.From, rng.To).
+			Order("recorded_at ASC").Find(&rankingHistory).Error; err != nil {
+			return nil, err
+		}
+		// 每个排名点的预估日销量 (units/day)
+		for _, point := range estimation.BuildSalesPoints(rankingHistory, nil, svcCtx.Sales) {
+			points = append(points, historyPoint{At: point.At, Value: point.UnitsPerDay})
 		}
 
 	case "rating":
 		var rankingHistory []models.RankingHistory
-		err = l.svcCtx.DB.Where("product_id = ? AND recorded_at >= ? AND rating IS NOT NULL", trackedProduct.ProductID, startTime).
-			Order("recorded_at ASC").Find(&rankingHistory).Error
-		if err != nil {
-			utils.LogError(l.ctx, "Failed to get rating history", "error", err)
-			return nil, errors.ErrInternalServer
+		if err := db.Where("product_id = ? AND recorded_at >= ? AND recorded_at < ? AND rating IS NOT NULL", productID, rng.From, rng.To).
+			Order("recorded_at ASC").Find(&rankingHistory).Error; err != nil {
+			return nil, err
 		}
-
-		historyData = make([]types.HistoryData, len(rankingHistory))
-		for i, rh := range rankingHistory {
-			historyData[i] = types.HistoryData{
-				Date:  rh.RecordedAt.Format("2006-01-02"),
-				Value: *rh.Rating,
-			}
+		for _, rh := range rankingHistory {
+			points = append(points, historyPoint{At: rh.RecordedAt, Value: *rh.Rating})
 		}
 
 	case "review_count":
 		var rankingHistory []models.RankingHistory
-		err = l.svcCtx.DB.Where("product_id = ? AND recorded_at >= ?", trackedProduct.ProductID, startTime).
-			Order("recorded_at ASC").Find(&rankingHistory).Error
-		if err != nil {
-			utils.LogError(l.ctx, "Failed to get review count history", "error", err)
-			return nil, errors.ErrInternalServer
+		if err := db.Where("product_id = ? AND recorded_at >= ? AND recorded_at < ?", productID, rng.From, rng.To).
+			Order("recorded_at ASC").Find(&rankingHistory).Error; err != nil {
+			return nil, err
 		}
-
-		historyData = make([]types.HistoryData, len(rankingHistory))
-		for i, rh := range rankingHistory {
-			historyData[i] = types.HistoryData{
-				Date:  rh.RecordedAt.Format("2006-01-02"),
-				Value: float64(rh.ReviewCount),
-			}
+		for _, rh := range rankingHistory {
+			points = append(points, historyPoint{At: rh.RecordedAt, Value: float64(rh.ReviewCount)})
 		}
 
 	case "buybox":
 		var buyboxHistory []models.BuyBoxHistory
-		err = l.svcCtx.DB.Where("product_id = ? AND recorded_at >= ? AND winner_price IS NOT NULL", trackedProduct.ProductID, startTime).
-			Order("recorded_at ASC").Find(&buyboxHistory).Error
-		if err != nil {
-			utils.LogError(l.ctx, "Failed to get Buy Box history", "error", err)
-			return nil, errors.ErrInternalServer
+		if err := db.Where("product_id = ? AND recorded_at >= ? AND recorded_at < ? AND winner_price IS NOT NULL", productID, rng.From, rng.To).
+			Order("recorded_at ASC").Find(&buyboxHistory).Error; err != nil {
+			return nil, err
 		}
-
-		historyData = make([]types.HistoryData, len(buyboxHistory))
-		for i, bh := range buyboxHistory {
-			historyData[i] = types.HistoryData{
-				Date:     bh.RecordedAt.Format("2006-01-02"),
-				Value:    *bh.WinnerPrice,
-				Currency: bh.Currency,
-			}
+		for _, bh := range buyboxHistory {
+			points = append(points, pricePoint(svcCtx.FX, bh.RecordedAt, *bh.WinnerPrice, bh.Currency, displayCurrency))
 		}
+	}
 
-	default:
-		return nil, errors.NewValidationError("Invalid metric", []errors.FieldError{
-			{Field: "metric", Message: "Metric must be one of: price, bsr, rating, review_count, buybox"},
+	return points, nil
+}
+
+// pricePoint 构建价格类数据点，指定展示货币时按当天汇率换算并保留原始值
+func pricePoint(converter *fx.Converter, recordedAt time.Time, value float64, currency, displayCurrency string) historyPoint {
+	point := historyPoint{
+		At:       recordedAt,
+		Value:    value,
+		Currency: currency,
+	}
+	if displayCurrency == "" {
+		return point
+	}
+
+	money := converter.ConvertOrKeep(value, currency, displayCurrency, recordedAt)
+	point.Value = money.Amount
+	point.Currency = money.Currency
+	if money.Converted() {
+		point.OriginalValue = money.OriginalAmount
+		point.OriginalCurrency = money.OriginalCurrency
+	}
+	return point
+}
+
+// salesEstimate 汇总期间内的预估销量和收入，指定展示货币时按最新估算点当天汇率换算收入
+func (l *GetProductHistoryLogic) salesEstimate(productID string, startTime, endTime time.Time, displayCurrency string) (*types.SalesEstimate, error) {
+	if now := time.Now(); endTime.After(now) {
+		endTime = now
+	}
+	estimate, err := estimation.EstimateSales(l.svcCtx.DB, l.svcCtx.Sales, productID, startTime, endTime)
+	if err != nil || estimate == nil {
+		return nil, err
+	}
+
+	result := &types.SalesEstimate{
+		EstUnitsPerDay:    estimate.AvgUnitsPerDay,
+		EstTotalUnits:     estimate.TotalUnits,
+		EstRevenue:        estimate.Revenue,
+		EstMonthlyRevenue: estimate.MonthlyRevenue,
+		Currency:          estimate.Currency,
+		LatestUnitsPerDay: estimate.LatestUnitsPerDay,
+		Days:              estimate.Days,
+	}
+	if displayCurrency != "" && estimate.Currency != "" {
+		revenue := l.svcCtx.FX.ConvertOrKeep(estimate.Revenue, estimate.Currency, displayCurrency, estimate.RecordedAt)
+		monthly := l.svcCtx.FX.ConvertOrKeep(estimate.MonthlyRevenue, estimate.Currency, displayCurrency, estimate.RecordedAt)
+		result.EstRevenue = revenue.Amount
+		result.EstMonthlyRevenue = monthly.Amount
+		result.Currency = monthly.Currency
+	}
+	return result, nil
+}
+
+// parseHistoryRange 解析时间范围、分桶和时区；from/to 优先于 period
+func parseHistoryRange(period, from, to, bucket, agg, tz string) (historyRange, error) {
+	var rng historyRange
+	var err error
+
+	if rng.Loc, err = timeseries.LoadLocation(tz); err != nil {
+		return rng, errors.NewValidationError("Invalid time zone", []errors.FieldError{
+			{Field: "tz", Message: err.Error()},
+		})
+	}
+	if rng.Bucket, err = timeseries.ParseBucket(bucket); err != nil {
+		return rng, errors.NewValidationError("Invalid bucket", []errors.FieldError{
+			{Field: "bucket", Message: err.Error()},
+		})
+	}
+	if rng.Agg, err = timeseries.ParseAgg(agg); err != nil {
+		return rng, errors.NewValidationError("Invalid aggregation", []errors.FieldError{
+			{Field: "agg", Message: err.Error()},
 		})
 	}
 
-	resp = &types.GetHistoryResponse{
-		ProductID: req.ProductID,
-		Metric:    metric,
-		Period:    period,
-		Data:      historyData,
+	now := time.Now()
+	rng.To = now
+	if from == "" && to == "" {
+		rng.Period, rng.From = utils.PeriodStartTime(period)
+		return rng, nil
 	}
 
-	// 记录业务日志
-	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "get_product_history", "product", req.ProductID, "success",
-		"metric", metric,
-		"period", period,
-		"data_points", len(historyData))
+	rng.Period = "custom"
+	if to != "" {
+		if rng.To, err = parseHistoryTime(to, rng.Loc, true); err != nil {
+			return rng, errors.NewValidationError("Invalid date range", []errors.FieldError{
+				{Field: "to", Message: "to must be RFC3339 or YYYY-MM-DD"},
+			})
+		}
+	}
+	rng.From = rng.To.AddDate(0, 0, -30)
+	if from != "" {
+		if rng.From, err = parseHistoryTime(from, rng.Loc, false); err != nil {
+			return rng, errors.NewValidationError("Invalid date range", []errors.FieldError{
+				{Field: "from", Message: "from must be RFC3339 or YYYY-MM-DD"},
+			})
+		}
+	}
+	if !rng.From.Before(rng.To) {
+		return rng, errors.NewValidationError("Invalid date range", []errors.FieldError{
+			{Field: "from", Message: "from must be before to"},
+		})
+	}
+	return rng, nil
+}
 
-	return resp, nil
+// parseHistoryTime 解析 RFC3339 时间或请求时区中的日期；endOfDay 时日期表示当天结束（次日零点）
+func parseHistoryTime(value string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// buildHistoryData 将原始数据点按桶聚合为接口数据，不分桶时原样返回（保留换算前的原始值）
+func buildHistoryData(points []historyPoint, rng historyRange) []types.HistoryData {
+	data := make([]types.HistoryData, 0, len(points))
+	if rng.Bucket == timeseries.BucketRaw {
+		for _, p := range points {
+			at := p.At.In(rng.Loc)
+			data = append(data, types.HistoryData{
+				Date:             at.Format("2006-01-02"),
+				Timestamp:        at.Format(time.RFC3339),
+				Value:            p.Value,
+				Currency:         p.Currency,
+				OriginalValue:    p.OriginalValue,
+				OriginalCurrency: p.OriginalCurrency,
+			})
+		}
+		return data
+	}
+
+	values := make([]timeseries.Point, len(points))
+	for i, p := range points {
+		values[i] = timeseries.Point{At: p.At, Value: p.Value}
+	}
+
+	consumed := 0
+	for _, b := range timeseries.Aggregate(values, rng.Bucket, rng.Loc) {
+		consumed += b.Count
+		item := types.HistoryData{
+			Date:      b.Start.Format("2006-01-02"),
+			Timestamp: b.Start.Format(time.RFC3339),
+			Value:     b.Value(rng.Agg),
+			Currency:  points[consumed-1].Currency, // 桶内最后一个点的币种
+			Count:     b.Count,
+		}
+		if rng.Agg == timeseries.AggOHLC {
+			item.Open, item.High, item.Low, item.Close = b.Open, b.High, b.Low, b.Close
+		}
+		data = append(data, item)
+	}
+	return data
+}
+
+// seriesCurrency 价格类序列的币种：指定展示货币时为展示货币，否则为最新数据点的币种
+func seriesCurrency(points []historyPoint, displayCurrency string) string {
+	if len(points) == 0 {
+		return displayCurrency
+	}
+	return points[len(points)-1].Currency
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,106 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	"context"
+	"sort"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetProductOffersLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetProductOffersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetProductOffersLogic {
+	return &GetProductOffersLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetProductOffersLogic) GetProductOffers(req *types.GetOffersRequest) (resp *types.GetOffersResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证用户是否有权限访问这个产品
+	var trackedProduct models.TrackedProduct
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).First(&trackedProduct).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Database error when checking product access", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp = &types.GetOffersResponse{
+		ProductID: req.ProductID,
+		Offers:    []types.ProductOffer{},
+	}
+
+	// 最新一次报价快照
+	var latest models.OfferHistory
+	err = l.svcCtx.DB.Where("product_id = ?", trackedProduct.ProductID).
+		Order("recorded_at DESC").
+		First(&latest).Error
+	if err == gorm.ErrRecordNotFound {
+		return resp, nil
+	} else if err != nil {
+		utils.LogError(l.ctx, "Failed to get latest offer snapshot", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	var offers []models.OfferHistory
+	if err := l.svcCtx.DB.Where("product_id = ? AND recorded_at = ?", trackedProduct.ProductID, latest.RecordedAt).
+		Find(&offers).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to get offers", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 按含运费价格从低到高排序
+	sort.SliceStable(offers, func(i, j int) bool {
+		return offers[i].LandedPrice() < offers[j].LandedPrice()
+	})
+
+	sellers := make(map[string]bool, len(offers))
+	for _, offer := range offers {
+		resp.Offers = append(resp.Offers, types.ProductOffer{
+			SellerName:     offer.SellerName,
+			SellerID:       getStringValue(offer.SellerID),
+			Price:          offer.Price,
+			ShippingPrice:  offer.ShippingPrice,
+			LandedPrice:    offer.LandedPrice(),
+			Currency:       offer.Currency,
+			Condition:      offer.Condition,
+			IsFBA:          offer.IsFBA,
+			IsPrime:        offer.IsPrime,
+			IsBuyBoxWinner: offer.IsBuyBoxWinner,
+		})
+		sellers[sellerKey(offer.SellerName, offer.SellerID)] = true
+	}
+
+	resp.RecordedAt = latest.RecordedAt.Format("2006-01-02T15:04:05Z07:00")
+	resp.SellerCount = len(sellers)
+
+	return resp, nil
+}
+
+// sellerKey 卖家唯一标识，优先使用卖家ID
+func sellerKey(name string, id *string) string {
+	if id != nil && *id != "" {
+		return *id
+	}
+	return name
+}
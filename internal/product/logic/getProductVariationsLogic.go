@@ -0,0 +1,168 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/fx"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	"context"
+	"encoding/json"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetProductVariationsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetProductVariationsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetProductVariationsLogic {
+	return &GetProductVariationsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetProductVariationsLogic) GetProductVariations(req *types.GetVariationsRequest) (resp *types.GetVariationsResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证用户是否有权限访问这个产品
+	var trackedProduct models.TrackedProduct
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).
+		Preload("Product").
+		First(&trackedProduct).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Database error when checking product access", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 查询同一父ASIN下的所有变体，没有父ASIN时家族只包含自身
+	family := []models.Product{trackedProduct.Product}
+	parentASIN := getStringValue(trackedProduct.Product.ParentASIN)
+	if parentASIN != "" {
+		family = nil
+		if err := l.svcCtx.DB.Where("parent_asin = ? OR asin = ?", parentASIN, parentASIN).
+			Order("asin ASC").
+			Find(&family).Error; err != nil {
+			utils.LogError(l.ctx, "Failed to query product variations", "error", err)
+			return nil, errors.ErrInternalServer
+		}
+	}
+
+	productIDs := make([]string, 0, len(family))
+	for _, product := range family {
+		productIDs = append(productIDs, product.ID)
+	}
+
+	// 当前用户对家族内产品的追踪记录
+	var trackedFamily []models.TrackedProduct
+	l.svcCtx.DB.Where("workspace_id = ? AND product_id IN ?", access.WorkspaceID, productIDs).Find(&trackedFamily)
+	trackedIDs := make(map[string]string, len(trackedFamily))
+	for _, tp := range trackedFamily {
+		trackedIDs[tp.ProductID] = tp.ID
+	}
+
+	currency := l.svcCtx.Config.EnvConfig.FX.DefaultCurrency
+	resp = &types.GetVariationsResponse{
+		ProductID:  req.ProductID,
+		ParentASIN: parentASIN,
+		Variations: make([]types.VariationProduct, 0, len(family)),
+		PriceRange: types.VariationPriceRange{Currency: currency},
+	}
+
+	for _, product := range family {
+		variation := types.VariationProduct{
+			ProductID: product.ID,
+			ASIN:      product.ASIN,
+			Title:     getStringValue(product.Title),
+			Currency:  currency,
+			TrackedID: trackedIDs[product.ID],
+		}
+		if product.VariationAttributes != nil {
+			json.Unmarshal(product.VariationAttributes, &variation.Attributes)
+		}
+
+		// 最新价格，统一换算为展示货币
+		var latestPrice models.PriceHistory
+		if err := l.svcCtx.DB.Where("product_id = ?", product.ID).
+			Order("recorded_at DESC").
+			First(&latestPrice).Error; err == nil {
+			price := l.svcCtx.FX.ConvertOrKeep(latestPrice.Price, latestPrice.Currency, currency, latestPrice.RecordedAt)
+			variation.Price = price.Amount
+			variation.Currency = price.Currency
+			if price.Currency == fx.NormalizeCurrency(currency) {
+				extendPriceRange(&resp.PriceRange, price.Amount)
+			}
+		}
+
+		// 最新排名与评分
+		var latestRanking models.RankingHistory
+		if err := l.svcCtx.DB.Where("product_id = ?", product.ID).
+			Order("recorded_at DESC").
+			First(&latestRanking).Error; err == nil {
+			if latestRanking.BSRRank != nil {
+				variation.BSR = *latestRanking.BSRRank
+				extendBSRRange(&resp.BSRRange, variation.BSR)
+			}
+			if latestRanking.Rating != nil {
+				variation.Rating = *latestRanking.Rating
+			}
+			variation.ReviewCount = latestRanking.ReviewCount
+		}
+
+		// 子变体共享父ASIN的评论，取家族中最大的评论数作为共享值
+		if variation.ReviewCount > resp.ReviewCount {
+			resp.ReviewCount = variation.ReviewCount
+			resp.Rating = variation.Rating
+		}
+
+		resp.Variations = append(resp.Variations, variation)
+	}
+
+	// 记录业务日志
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "get_product_variations", "product", trackedProduct.ProductID, "success",
+		"parent_asin", parentASIN,
+		"variation_count", len(resp.Variations))
+
+	return resp, nil
+}
+
+// extendPriceRange 将价格并入家族价格区间
+func extendPriceRange(r *types.VariationPriceRange, price float64) {
+	if price <= 0 {
+		return
+	}
+	if r.Min == 0 || price < r.Min {
+		r.Min = price
+	}
+	if price > r.Max {
+		r.Max = price
+	}
+}
+
+// extendBSRRange 将排名并入家族BSR区间（数值越小排名越好）
+func extendBSRRange(r *types.VariationBSRRange, bsr int) {
+	if bsr <= 0 {
+		return
+	}
+	if r.Best == 0 || bsr < r.Best {
+		r.Best = bsr
+	}
+	if bsr > r.Worst {
+		r.Worst = bsr
+	}
+}
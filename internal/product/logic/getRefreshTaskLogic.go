@@ -0,0 +1,113 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"time"
+
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/tasks"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/hibiken/asynq"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetRefreshTaskLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetRefreshTaskLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetRefreshTaskLogic {
+	return &GetRefreshTaskLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetRefreshTask 通过 asynq Inspector 查询刷新任务状态、重试次数和最近错误
+func (l *GetRefreshTaskLogic) GetRefreshTask(req *types.GetRefreshTaskRequest) (resp *types.RefreshTask, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := l.svcCtx.AsynqInspector.GetTaskInfo(tasks.RefreshTaskQueue, req.TaskID)
+	if stderrors.Is(err, asynq.ErrTaskNotFound) || stderrors.Is(err, asynq.ErrQueueNotFound) {
+		// 任务不存在或已超过保留时间
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Failed to get refresh task info", "error", err, "task_id", req.TaskID)
+		return nil, errors.ErrInternalServer
+	}
+
+	task, payload, err := newRefreshTask(info)
+	if err != nil || info.Type != tasks.TypeRefreshProductData {
+		return nil, errors.ErrNotFound
+	}
+
+	// 只能查询工作区追踪产品的刷新任务
+	var count int64
+	if err := l.svcCtx.DB.Model(&models.TrackedProduct{}).
+		Where("id = ? AND workspace_id = ?", payload.TrackedID, access.WorkspaceID).
+		Count(&count).Error; err != nil {
+		utils.LogError(l.ctx, "Database error when checking refresh task access", "error", err, "task_id", req.TaskID)
+		return nil, errors.ErrInternalServer
+	}
+	if count == 0 {
+		return nil, errors.ErrNotFound
+	}
+
+	return &task, nil
+}
+
+// newRefreshTask 将 asynq 任务信息转换为刷新任务状态，completed 状态附带任务结果
+func newRefreshTask(info *asynq.TaskInfo) (types.RefreshTask, tasks.RefreshProductDataPayload, error) {
+	var payload tasks.RefreshProductDataPayload
+	if err := json.Unmarshal(info.Payload, &payload); err != nil {
+		return types.RefreshTask{}, payload, err
+	}
+
+	task := types.RefreshTask{
+		TaskID:      info.ID,
+		TrackedID:   payload.TrackedID,
+		ASIN:        payload.ASIN,
+		State:       info.State.String(),
+		Retried:     info.Retried,
+		MaxRetry:    info.MaxRetry,
+		LastError:   info.LastErr,
+		RequestedAt: payload.RequestedAt,
+	}
+	if !info.LastFailedAt.IsZero() {
+		task.LastFailedAt = info.LastFailedAt.Format(time.RFC3339)
+	}
+	if !info.NextProcessAt.IsZero() {
+		task.NextProcessAt = info.NextProcessAt.Format(time.RFC3339)
+	}
+	if !info.CompletedAt.IsZero() {
+		task.CompletedAt = info.CompletedAt.Format(time.RFC3339)
+	}
+
+	var result tasks.RefreshProductDataResult
+	if info.State == asynq.TaskStateCompleted && json.Unmarshal(info.Result, &result) == nil {
+		task.Result = &types.RefreshTaskResult{
+			Price:        result.Price,
+			Currency:     result.Currency,
+			BSR:          result.BSR,
+			Rating:       result.Rating,
+			ReviewCount:  result.ReviewCount,
+			Availability: result.Availability,
+			Offers:       result.Offers,
+			UpdatedAt:    result.UpdatedAt.Format(time.RFC3339),
+		}
+	}
+	return task, payload, nil
+}
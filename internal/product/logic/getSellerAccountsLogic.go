@@ -0,0 +1,58 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	"context"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetSellerAccountsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetSellerAccountsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetSellerAccountsLogic {
+	return &GetSellerAccountsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetSellerAccountsLogic) GetSellerAccounts() (resp *types.GetSellerAccountsResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []models.SellerAccount
+	if err := l.svcCtx.DB.Where("workspace_id = ?", access.WorkspaceID).Order("created_at ASC").Find(&accounts).Error; err != nil {
+		l.Errorf("Failed to query seller accounts: %v", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	return &types.GetSellerAccountsResponse{
+		Sellers: toSellerAccounts(accounts),
+	}, nil
+}
+
+// toSellerAccounts 转换为响应格式
+func toSellerAccounts(accounts []models.SellerAccount) []types.SellerAccount {
+	sellers := make([]types.SellerAccount, 0, len(accounts))
+	for _, account := range accounts {
+		sellers = append(sellers, types.SellerAccount{
+			ID:         account.ID,
+			SellerName: account.SellerName,
+			SellerID:   getStringValue(account.SellerID),
+			CreatedAt:  account.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+	return sellers
+}
@@ -0,0 +1,141 @@
+package logic
+
+import (
+	"context"
+	"math"
+
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTagSummaryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetTagSummaryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTagSummaryLogic {
+	return &GetTagSummaryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetTagSummary 按标签汇总追踪产品数、周期内异常事件数和平均价格变动
+func (l *GetTagSummaryLogic) GetTagSummary(req *types.GetTagSummaryRequest) (resp *types.GetTagSummaryResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	period, startTime := utils.PeriodStartTime(req.Period)
+
+	var tags []models.Tag
+	if err := l.svcCtx.DB.Where("workspace_id = ?", access.WorkspaceID).Order("LOWER(name) ASC").Find(&tags).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to query tags", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 标签与追踪产品的关联
+	var members []struct {
+		TagID     string
+		ProductID string
+		IsActive  bool
+	}
+	if err := l.svcCtx.DB.Table("tracked_product_tags").
+		Select("tracked_product_tags.tag_id, tracked_products.product_id, tracked_products.is_active").
+		Joins("JOIN tracked_products ON tracked_products.id = tracked_product_tags.tracked_product_id").
+		Where("tracked_products.workspace_id = ?", access.WorkspaceID).
+		Scan(&members).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to query tagged products", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	productIDs := []string{}
+	for _, member := range members {
+		if !containsString(productIDs, member.ProductID) {
+			productIDs = append(productIDs, member.ProductID)
+		}
+	}
+
+	// 每个产品周期内第一个和最后一个价格
+	var prices []struct {
+		ProductID  string
+		FirstPrice float64
+		LastPrice  float64
+	}
+	// 每个产品周期内的异常事件数
+	var anomalies []struct {
+		ProductID string
+		Count     int
+	}
+	if len(productIDs) > 0 {
+		if err := l.svcCtx.DB.Model(&models.PriceHistory{}).
+			Select("product_id, (ARRAY_AGG(price ORDER BY recorded_at ASC))[1] AS first_price, (ARRAY_AGG(price ORDER BY recorded_at DESC))[1] AS last_price").
+			Where("product_id IN ? AND recorded_at >= ?", productIDs, startTime).
+			Group("product_id").
+			Scan(&prices).Error; err != nil {
+			utils.LogError(l.ctx, "Failed to query price changes for tags", "error", err)
+			return nil, errors.ErrInternalServer
+		}
+		if err := l.svcCtx.DB.Model(&models.AnomalyEvent{}).
+			Select("product_id, COUNT(*) AS count").
+			Where("product_id IN ? AND created_at >= ?", productIDs, startTime).
+			Where("workspace_id IS NULL OR workspace_id = ?", access.WorkspaceID).
+			Group("product_id").
+			Scan(&anomalies).Error; err != nil {
+			utils.LogError(l.ctx, "Failed to query anomaly events for tags", "error", err)
+			return nil, errors.ErrInternalServer
+		}
+	}
+
+	priceChanges := make(map[string]float64, len(prices))
+	for _, p := range prices {
+		if p.FirstPrice > 0 {
+			priceChanges[p.ProductID] = (p.LastPrice - p.FirstPrice) / p.FirstPrice * 100
+		}
+	}
+	anomalyCounts := make(map[string]int, len(anomalies))
+	for _, a := range anomalies {
+		anomalyCounts[a.ProductID] = a.Count
+	}
+
+	summaries := make(map[string]*types.TagSummary, len(tags))
+	changeSums := make(map[string]float64, len(tags))
+	resp = &types.GetTagSummaryResponse{Period: period, Tags: make([]types.TagSummary, len(tags))}
+	for i, tag := range tags {
+		resp.Tags[i] = types.TagSummary{TagID: tag.ID, Name: tag.Name, Color: tag.Color}
+		summaries[tag.ID] = &resp.Tags[i]
+	}
+	for _, member := range members {
+		summary, ok := summaries[member.TagID]
+		if !ok {
+			continue
+		}
+		summary.TrackedCount++
+		if member.IsActive {
+			summary.ActiveCount++
+			summary.ActiveAnomalies += anomalyCounts[member.ProductID]
+		}
+		if change, ok := priceChanges[member.ProductID]; ok {
+			summary.PricedCount++
+			changeSums[member.TagID] += change
+		}
+	}
+	for i := range resp.Tags {
+		if summary := &resp.Tags[i]; summary.PricedCount > 0 {
+			summary.AvgPriceChange = math.Round(changeSums[summary.TagID]/float64(summary.PricedCount)*100) / 100
+		}
+	}
+
+	l.Infof("Retrieved summary of %d tags for user %s", len(resp.Tags), access.UserID)
+	return resp, nil
+}
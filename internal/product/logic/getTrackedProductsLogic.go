@@ -5,13 +5,18 @@ import (
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/models"
 	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
 	"amazonpilot/internal/product/svc"
 	"amazonpilot/internal/product/types"
+	pkgtypes "amazonpilot/pkg/types"
 	"context"
-	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
 )
 
 type GetTrackedProductsLogic struct {
@@ -29,175 +34,311 @@ func NewGetTrackedProductsLogic(ctx context.Context, svcCtx *svc.ServiceContext)
 }
 
 func (l *GetTrackedProductsLogic) GetTrackedProducts(req *types.GetTrackedRequest) (resp *types.GetTrackedResponse, err error) {
-	// 从JWT context获取用户ID
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
 	if err != nil {
 		return nil, err
 	}
 
-	// 设置分页参数
+	cursor, err := utils.ParseCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 设置分页参数，游标模式不使用 offset
 	offset := (req.Page - 1) * req.Limit
+	if cursor != nil {
+		offset = 0
+	}
 
-	// 查询用户追踪的产品总数
+	// 构建筛选和排序条件
+	list, err := buildTrackedListQuery(l.svcCtx.DB, access.WorkspaceID, req, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 查询符合条件的追踪产品总数，游标模式不统计
 	var total int64
-	if err := l.svcCtx.DB.Table("tracked_products").
-		Where("user_id = ?", userIDStr).
-		Count(&total).Error; err != nil {
-		l.Errorf("Failed to count tracked products: %v", err)
+	if cursor == nil {
+		if err := list.query.Count(&total).Error; err != nil {
+			l.Errorf("Failed to count tracked products: %v", err)
+			return nil, errors.ErrInternalServer
+		}
+	}
+
+	// 先按排序分页取出当前页的追踪ID，再加载完整记录
+	// 按创建时间排序时多取一条，用于判断是否还有下一页并生成游标
+	fetch := req.Limit
+	if list.keyset {
+		fetch = req.Limit + 1
+	}
+	var pageIDs []string
+	if err := list.query.Order(list.order).
+		Offset(offset).
+		Limit(fetch).
+		Pluck("tp.id", &pageIDs).Error; err != nil {
+		l.Errorf("Failed to query tracked product ids: %v", err)
 		return nil, errors.ErrInternalServer
 	}
+	fetched := len(pageIDs)
+	if fetched > req.Limit {
+		pageIDs = pageIDs[:req.Limit]
+	}
 
-	// 查询用户追踪的产品列表，包含最新价格和排名数据
+	// 查询当前页的追踪记录
 	var trackedProducts []models.TrackedProduct
+	if len(pageIDs) > 0 {
+		if err := l.svcCtx.DB.Where("id IN ?", pageIDs).Find(&trackedProducts).Error; err != nil {
+			l.Errorf("Failed to query tracked products: %v", err)
+			return nil, errors.ErrInternalServer
+		}
+	}
+	position := make(map[string]int, len(pageIDs))
+	for i, id := range pageIDs {
+		position[id] = i
+	}
+	sort.Slice(trackedProducts, func(i, j int) bool {
+		return position[trackedProducts[i].ID] < position[trackedProducts[j].ID]
+	})
 
-	if err := l.svcCtx.DB.Where("user_id = ?", userIDStr).
-		Preload("Product").
-		Offset(offset).
-		Limit(req.Limit).
-		Order("created_at DESC").
-		Find(&trackedProducts).Error; err != nil {
-		l.Errorf("Failed to query tracked products: %v", err)
+	l.Infof("Query result: found %d tracked records for user %s", len(trackedProducts), access.UserID)
+
+	// 产品内容和最新数据按产品缓存，未命中的产品一次性从产品表和最新快照加载
+	productIDs := make([]string, len(trackedProducts))
+	for i, tp := range trackedProducts {
+		productIDs[i] = tp.ProductID
+	}
+	productData, err := l.svcCtx.ProductCache.GetOrLoadMany(l.ctx, productIDs, func(ctx context.Context, ids []string) (map[string]cache.ProductData, error) {
+		return loadProductData(l.svcCtx.DB.WithContext(ctx), ids)
+	})
+	if err != nil {
+		l.Errorf("Failed to load product data: %v", err)
 		return nil, errors.ErrInternalServer
 	}
 
-	l.Infof("Query result: found %d tracked records for user %s", len(trackedProducts), userIDStr)
+	// 标签属于用户而不是产品，不写入按产品缓存
+	trackedIDs := make([]string, len(trackedProducts))
+	for i, tp := range trackedProducts {
+		trackedIDs[i] = tp.ID
+	}
+	tagRefs, err := loadTagRefs(l.svcCtx.DB, trackedIDs)
+	if err != nil {
+		l.Errorf("Failed to query tracked product tags: %v", err)
+		return nil, errors.ErrInternalServer
+	}
 
-	// 转换为响应格式，使用按产品缓存
+	// 合并产品数据和用户的追踪信息
 	products := make([]types.TrackedProduct, 0, len(trackedProducts))
 	for _, tp := range trackedProducts {
 		status := "inactive"
 		if tp.IsActive {
 			status = "active"
 		}
-
-		productIDStr := tp.ProductID
-
-		// 尝试从缓存获取产品完整数据
-		productCacheKey := cache.ProductDataKey(productIDStr)
-		cachedProductData, err := l.svcCtx.RedisClient.Get(l.ctx, productCacheKey).Result()
-
-		var product types.TrackedProduct
-
-		if err == nil && cachedProductData != "" && tp.Product.Title != nil {
-			// 缓存命中，直接使用缓存数据
-			if unmarshalErr := json.Unmarshal([]byte(cachedProductData), &product); unmarshalErr == nil {
-				// 更新追踪相关字段
-				product.ID = tp.ID
-				if tp.Alias != nil {
-					product.Alias = *tp.Alias
-				}
-				product.Status = status
-				products = append(products, product)
-				continue
-			}
-		}
-
-		// 缓存未命中，从数据库获取数据
-		var latestPrice models.PriceHistory
-		l.svcCtx.DB.Where("product_id = ?", tp.ProductID).
-			Order("recorded_at DESC").
-			First(&latestPrice)
-
-		// 获取最新排名数据
-		var latestRanking models.RankingHistory
-		l.svcCtx.DB.Where("product_id = ?", tp.ProductID).
-			Order("recorded_at DESC").
-			First(&latestRanking)
-
-		// 安全获取指针字段的值
-		title := ""
-		if tp.Product.Title != nil {
-			title = *tp.Product.Title
-		}
-
 		alias := ""
 		if tp.Alias != nil {
 			alias = *tp.Alias
 		}
 
-		// 安全获取产品的其他字段
-		brand := ""
-		if tp.Product.Brand != nil {
-			brand = *tp.Product.Brand
-		}
+		data := productData[tp.ProductID]
+		products = append(products, types.TrackedProduct{
+			ID:             tp.ID,
+			ProductID:      tp.ProductID, // 添加product_id字段用于竞品分析
+			ASIN:           data.ASIN,
+			ParentASIN:     data.ParentASIN,
+			Title:          data.Title,
+			Brand:          data.Brand,
+			Category:       data.Category,
+			Alias:          alias,
+			CurrentPrice:   data.CurrentPrice,
+			PriceChange24h: data.PriceChange24h,
+			Currency:       data.Currency,
+			BSR:            data.BSR,
+			BSRCategory:    data.BSRCategory,
+			Rating:         data.Rating,
+			ReviewCount:    data.ReviewCount,
+			BuyBoxPrice:    data.BuyBoxPrice,
+			LastUpdated:    data.LastUpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			Status:         status,
+			Tags:           tagRefs[tp.ID],
+			Images:         data.Images,
+			Description:    data.Description,
+			BulletPoints:   data.BulletPoints,
+		})
+	}
 
-		category := ""
-		if tp.Product.Category != nil {
-			category = *tp.Product.Category
-		}
+	resp = &types.GetTrackedResponse{
+		Tracked:    products,
+		Pagination: types.Pagination{Limit: req.Limit},
+	}
+	if cursor == nil {
+		// 计算总页数
+		resp.Pagination.Page = req.Page
+		resp.Pagination.Total = int(total)
+		resp.Pagination.TotalPages = int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	}
+	if list.keyset {
+		page := pkgtypes.NewCursorResponse(fetched, req.Limit, func() (time.Time, string) {
+			last := trackedProducts[len(trackedProducts)-1]
+			return last.CreatedAt, last.ID
+		})
+		resp.Pagination.NextCursor = page.NextCursor
+		resp.Pagination.HasMore = page.HasMore
+	}
 
-		description := ""
-		if tp.Product.Description != nil {
-			description = *tp.Product.Description
-		}
+	l.Infof("Retrieved %d tracked products for user %s", len(products), access.UserID)
+	return resp, nil
+}
 
-		// 解析 images 和 bullet_points JSON 字段
-		var images []string
-		if tp.Product.Images != nil {
-			json.Unmarshal(tp.Product.Images, &images)
-		}
+// trackedLatestSnapshotJoin 追踪列表中按最新数据筛选和排序所需的关联查询
+const trackedLatestSnapshotJoin = "LEFT JOIN product_latest_snapshot ls ON ls.product_id = tp.product_id"
 
-		var bulletPoints []string
-		if tp.Product.BulletPoints != nil {
-			json.Unmarshal(tp.Product.BulletPoints, &bulletPoints)
-		}
+// trackedSortColumns 支持的排序字段
+var trackedSortColumns = map[string]string{
+	"created_at":       "tp.created_at",
+	"current_price":    "ls.price",
+	"price_change_24h": "ls.price_change_24h",
+	"bsr":              "ls.bsr_rank",
+	"rating":           "ls.rating",
+	"review_count":     "ls.review_count",
+	"last_updated":     "p.last_updated_at",
+}
 
-		product = types.TrackedProduct{
-			ID:           tp.ID,
-			ProductID:    tp.ProductID, // 添加product_id字段用于竞品分析
-			ASIN:         tp.Product.ASIN,
-			Title:        title,
-			Brand:        brand,
-			Category:     category,
-			Alias:        alias,
-			CurrentPrice: latestPrice.Price,
-			Currency:     latestPrice.Currency,
-			BSR:          0, // 默认值，从排名历史获取
-			Rating:       0, // 默认值，从排名历史获取
-			ReviewCount:  latestRanking.ReviewCount,
-			BuyBoxPrice:  latestPrice.Price, // 使用当前价格作为BuyBox价格
-			LastUpdated:  tp.Product.LastUpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			Status:       status,
-			Images:       images,
-			Description:  description,
-			BulletPoints: bulletPoints,
-		}
+// trackedListQuery 追踪列表的筛选查询 (可重复使用) 和排序子句
+type trackedListQuery struct {
+	query  *gorm.DB
+	order  string
+	keyset bool // 按创建时间排序，支持游标分页
+}
 
-		// 安全设置BSR和Rating
-		if latestRanking.BSRRank != nil {
-			product.BSR = *latestRanking.BSRRank
-		}
-		if latestRanking.Rating != nil {
-			product.Rating = *latestRanking.Rating
-		}
-		if latestPrice.BuyBoxPrice != nil {
-			product.BuyBoxPrice = *latestPrice.BuyBoxPrice
+// buildTrackedListQuery 按请求构建追踪列表的筛选查询和排序子句
+// 只有筛选或排序用到最新价格、排名时才关联最新快照；游标分页只支持按创建时间排序
+func buildTrackedListQuery(db *gorm.DB, workspaceID string, req *types.GetTrackedRequest, cursor *pkgtypes.Cursor) (*trackedListQuery, error) {
+	sortBy := strings.ToLower(strings.TrimSpace(req.SortBy))
+	if sortBy == "" {
+		sortBy = "created_at"
+	}
+	column, ok := trackedSortColumns[sortBy]
+	if !ok {
+		return nil, errors.NewValidationError("Invalid sort field", []errors.FieldError{
+			{Field: "sort_by", Message: "sort_by must be one of: created_at, current_price, price_change_24h, bsr, rating, review_count, last_updated"},
+		})
+	}
+	direction := strings.ToUpper(strings.TrimSpace(req.SortOrder))
+	if direction == "" {
+		direction = "DESC"
+	}
+	if direction != "ASC" && direction != "DESC" {
+		return nil, errors.NewValidationError("Invalid sort order", []errors.FieldError{
+			{Field: "sort_order", Message: "sort_order must be asc or desc"},
+		})
+	}
+	if err := validateRange("price", req.MinPrice, req.MaxPrice, 0); err != nil {
+		return nil, err
+	}
+	if err := validateRange("rating", req.MinRating, req.MaxRating, 5); err != nil {
+		return nil, err
+	}
+
+	query := db.Table("tracked_products tp").
+		Joins("JOIN products p ON p.id = tp.product_id").
+		Where("tp.workspace_id = ?", workspaceID)
+
+	switch strings.ToLower(strings.TrimSpace(req.Status)) {
+	case "":
+	case "active":
+		query = query.Where("tp.is_active = ?", true)
+	case "inactive", "paused":
+		query = query.Where("tp.is_active = ?", false)
+	default:
+		return nil, errors.NewValidationError("Invalid status", []errors.FieldError{
+			{Field: "status", Message: "status must be active or inactive"},
+		})
+	}
+	if category := strings.TrimSpace(req.Category); category != "" {
+		query = query.Where("p.category = ?", category)
+	}
+	if brand := strings.TrimSpace(req.Brand); brand != "" {
+		query = query.Where("p.brand = ?", brand)
+	}
+	if tagIDs := splitIDs(req.TagIDs); len(tagIDs) > 0 {
+		query = query.Where("tp.id IN (SELECT tracked_product_id FROM tracked_product_tags WHERE tag_id IN ?)", tagIDs)
+	}
+	if search := strings.TrimSpace(req.Search); search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		query = query.Where("(p.title ILIKE ? OR p.brand ILIKE ? OR p.asin ILIKE ? OR tp.alias ILIKE ?)", pattern, pattern, pattern, pattern)
+	}
+
+	sortsBySnapshot := sortBy != "created_at" && sortBy != "last_updated"
+	if req.MinPrice > 0 || req.MaxPrice > 0 || req.MinRating > 0 || req.MaxRating > 0 || sortsBySnapshot {
+		query = query.Joins(trackedLatestSnapshotJoin)
+	}
+	if req.MinPrice > 0 {
+		query = query.Where("ls.price >= ?", req.MinPrice)
+	}
+	if req.MaxPrice > 0 {
+		query = query.Where("ls.price <= ?", req.MaxPrice)
+	}
+	if req.MinRating > 0 {
+		query = query.Where("ls.rating >= ?", req.MinRating)
+	}
+	if req.MaxRating > 0 {
+		query = query.Where("ls.rating <= ?", req.MaxRating)
+	}
+
+	list := &trackedListQuery{keyset: sortBy == "created_at"}
+	if list.keyset {
+		desc := direction == "DESC"
+		list.order = pkgtypes.KeysetOrder("tp.created_at", "tp.id", desc)
+		if cursor != nil {
+			cond, args := cursor.Condition("tp.created_at", "tp.id", desc)
+			query = query.Where(cond, args...)
 		}
-		// 将产品数据缓存到Redis (按产品缓存，TTL: 30分钟)
-		// 首次加入 ASIN 的时候，title 是没有获取到的，不用缓存。
-		if product.Title != "" {
-			if productData, marshalErr := json.Marshal(product); marshalErr == nil {
-				l.svcCtx.RedisClient.Set(l.ctx, productCacheKey, productData, 30*time.Minute).Err()
-				l.Infof("Cached product data for product %s, TTL: 30 minutes", productIDStr)
-			}
+	} else {
+		if cursor != nil {
+			return nil, errors.NewValidationError("Invalid cursor", []errors.FieldError{
+				{Field: "cursor", Message: "Cursor pagination is only supported when sorting by created_at"},
+			})
 		}
+		// 没有数据的产品排在最后，按追踪ID保证分页稳定
+		list.order = fmt.Sprintf("%s %s NULLS LAST, tp.id ASC", column, direction)
+	}
+	list.query = query.Session(&gorm.Session{})
+	return list, nil
+}
 
-		products = append(products, product)
+// validateRange 校验范围筛选参数，0 表示不限；max 为 0 时不限制上界
+func validateRange(field string, minValue, maxValue, limit float64) error {
+	if minValue < 0 || maxValue < 0 || (limit > 0 && (minValue > limit || maxValue > limit)) {
+		message := fmt.Sprintf("%s range cannot be negative", field)
+		if limit > 0 {
+			message = fmt.Sprintf("%s range must be between 0 and %g", field, limit)
+		}
+		return errors.NewValidationError("Invalid range", []errors.FieldError{
+			{Field: "min_" + field, Message: message},
+		})
+	}
+	if maxValue > 0 && minValue > maxValue {
+		return errors.NewValidationError("Invalid range", []errors.FieldError{
+			{Field: "min_" + field, Message: fmt.Sprintf("min_%s cannot be greater than max_%s", field, field)},
+		})
 	}
+	return nil
+}
 
-	// 计算总页数
-	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
 
-	resp = &types.GetTrackedResponse{
-		Tracked: products,
-		Pagination: types.Pagination{
-			Page:       req.Page,
-			Limit:      req.Limit,
-			Total:      int(total),
-			TotalPages: totalPages,
-		},
+// loadProductData 加载产品及其最新快照，生成按产品缓存的数据
+func loadProductData(db *gorm.DB, productIDs []string) (map[string]cache.ProductData, error) {
+	var products []models.Product
+	if err := db.Where("id IN ?", productIDs).Preload("LatestSnapshot").Find(&products).Error; err != nil {
+		return nil, err
 	}
-
-	l.Infof("Retrieved %d tracked products for user %s", len(products), userIDStr)
-	return resp, nil
+	data := make(map[string]cache.ProductData, len(products))
+	for i := range products {
+		data[products[i].ID] = cache.NewProductData(&products[i])
+	}
+	return data, nil
 }
@@ -0,0 +1,62 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetUsageLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetUsageLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetUsageLogic {
+	return &GetUsageLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetUsage 返回工作区当前的额度消耗和所有者计划的上限
+func (l *GetUsageLogic) GetUsage() (resp *types.GetUsageResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	entitlements, err := quota.ForWorkspace(l.ctx, l.svcCtx.DB, access.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := quota.LoadUsage(l.ctx, l.svcCtx.DB, access.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.GetUsageResponse{
+		WorkspaceID:          access.WorkspaceID,
+		Plan:                 entitlements.Plan,
+		TrackedProducts:      usageMetric(usage.TrackedProducts, entitlements.MaxTrackedProducts),
+		CompetitorGroups:     usageMetric(usage.CompetitorGroups, entitlements.MaxCompetitorGroups),
+		ReportsThisMonth:     usageMetric(usage.ReportsThisMonth, entitlements.ReportsPerMonth),
+		ReportsResetAt:       quota.MonthStart(time.Now()).AddDate(0, 1, 0).Format(time.RFC3339),
+		MinRefreshFrequency:  entitlements.MinRefreshFrequency,
+		HistoryRetentionDays: entitlements.HistoryRetentionDays,
+		RateLimitPerMinute:   entitlements.RateLimitPerMinute,
+	}, nil
+}
+
+// usageMetric 转换单项额度
+func usageMetric(used int64, limit int) types.UsageMetric {
+	return types.UsageMetric{Used: used, Limit: limit, Remaining: quota.Remaining(limit, used)}
+}
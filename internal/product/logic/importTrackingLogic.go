@@ -0,0 +1,205 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/tasks"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/hibiken/asynq"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ImportTrackingLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewImportTrackingLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ImportTrackingLogic {
+	return &ImportTrackingLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ImportTrackingLogic) ImportTracking(req *types.ImportTrackingRequest) (resp *types.ImportTrackingResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, format, err := parseImportRows(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.NewValidationError("No rows to import", []errors.FieldError{
+			{Field: "data", Message: "Import data must contain at least one row"},
+		})
+	}
+	if len(rows) > tasks.ImportMaxRows {
+		return nil, errors.NewValidationError("Too many rows", []errors.FieldError{
+			{Field: "data", Message: fmt.Sprintf("Import is limited to %d rows", tasks.ImportMaxRows)},
+		})
+	}
+
+	// 已达到计划的追踪产品数上限时直接拒绝，否则超出剩余额度的行记为失败
+	if err := quota.CheckTrackedProducts(l.ctx, l.svcCtx.DB, access.WorkspaceID, 1); err != nil {
+		return nil, err
+	}
+	capacity, err := quota.RemainingTrackedProducts(l.ctx, l.svcCtx.DB, access.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 小批量同步处理，直接返回逐行结果
+	if len(rows) <= tasks.ImportSyncLimit && !req.Async {
+		results, payloads := tasks.ImportTrackedProducts(l.svcCtx.DB, access.UserID, access.WorkspaceID, capacity, rows, nil)
+		enqueued, err := tasks.EnqueueInitialFetches(l.ctx, l.svcCtx.AsynqClient, l.svcCtx.RedisClient, payloads)
+		if err != nil {
+			l.Errorf("Failed to enqueue some initial fetches: %v", err)
+		}
+
+		created, existing, failed := tasks.CountImportResults(results)
+		logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "bulk_import_completed", "import", access.UserID, "success",
+			"format", format,
+			"rows", len(rows),
+			"created", created,
+			"already_tracked", existing,
+			"failed", failed,
+			"fetches_enqueued", enqueued)
+
+		return &types.ImportTrackingResponse{
+			Status:         "completed",
+			Total:          len(rows),
+			Created:        created,
+			AlreadyTracked: existing,
+			Failed:         failed,
+			Results:        toImportRowResults(results),
+		}, nil
+	}
+
+	// 大批量创建异步任务，由 worker 分块处理
+	rowsJSON, err := json.Marshal(rows)
+	if err != nil {
+		l.Errorf("Failed to marshal import rows: %v", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	job := models.ImportJob{
+		UserID:      access.UserID,
+		WorkspaceID: access.WorkspaceID,
+		Status:      "queued",
+		Format:      format,
+		TotalRows:   len(rows),
+		Rows:        rowsJSON,
+	}
+	if err := l.svcCtx.DB.Create(&job).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to create import job", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	payload, _ := json.Marshal(tasks.BulkImportPayload{JobID: job.ID, UserID: access.UserID})
+	info, err := l.svcCtx.AsynqClient.Enqueue(asynq.NewTask(tasks.TypeBulkImport, payload))
+	if err != nil {
+		l.Errorf("Failed to enqueue bulk import task: %v", err)
+		errorMsg := "failed to enqueue import task"
+		l.svcCtx.DB.Model(&job).Updates(map[string]interface{}{"status": "failed", "error_message": errorMsg})
+		return nil, errors.ErrInternalServer
+	}
+
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "bulk_import_queued", "import_job", job.ID, "success",
+		"format", format,
+		"rows", len(rows),
+		"task_id", info.ID)
+
+	return &types.ImportTrackingResponse{
+		JobID:   job.ID,
+		Status:  "queued",
+		Total:   len(rows),
+		Results: []types.ImportRowResult{},
+	}, nil
+}
+
+// parseImportRows 根据格式解析导入数据，返回待导入行和实际格式
+func parseImportRows(req *types.ImportTrackingRequest) ([]tasks.ImportRow, string, error) {
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+	if format == "" {
+		format = "json"
+		if req.Data != "" && len(req.Items) == 0 {
+			format = "csv"
+		}
+	}
+
+	var (
+		rows []tasks.ImportRow
+		err  error
+	)
+	switch format {
+	case "csv":
+		rows, err = tasks.ParseImportCSV(strings.NewReader(req.Data))
+	case "json":
+		if req.Data != "" {
+			rows, err = tasks.ParseImportJSON([]byte(req.Data))
+			break
+		}
+		rows = make([]tasks.ImportRow, 0, len(req.Items))
+		for i, item := range req.Items {
+			row := tasks.ImportRow{
+				Line:  i + 1,
+				ASIN:  item.ASIN,
+				Alias: item.Alias,
+				Tags:  item.Tags,
+			}
+			if item.PriceChangeThreshold > 0 {
+				threshold := item.PriceChangeThreshold
+				row.PriceChangeThreshold = &threshold
+			}
+			if item.BSRChangeThreshold > 0 {
+				threshold := item.BSRChangeThreshold
+				row.BSRChangeThreshold = &threshold
+			}
+			rows = append(rows, row)
+		}
+	default:
+		return nil, "", errors.NewValidationError("Invalid format", []errors.FieldError{
+			{Field: "format", Message: "Format must be csv or json"},
+		})
+	}
+	if err != nil {
+		return nil, "", errors.NewValidationError("Invalid import data", []errors.FieldError{
+			{Field: "data", Message: err.Error()},
+		})
+	}
+
+	return rows, format, nil
+}
+
+// toImportRowResults 转换逐行导入结果
+func toImportRowResults(results []tasks.ImportRowResult) []types.ImportRowResult {
+	items := make([]types.ImportRowResult, 0, len(results))
+	for _, result := range results {
+		items = append(items, types.ImportRowResult{
+			Line:      result.Line,
+			ASIN:      result.ASIN,
+			Status:    result.Status,
+			TrackedID: result.TrackedID,
+			Error:     result.Error,
+		})
+	}
+	return items
+}
@@ -0,0 +1,118 @@
+package logic
+
+import (
+	"context"
+	stderrors "errors"
+	"sort"
+	"time"
+
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/tasks"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/hibiken/asynq"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const maxRefreshTasksLimit = 50
+
+type ListRefreshTasksLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListRefreshTasksLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListRefreshTasksLogic {
+	return &ListRefreshTasksLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListRefreshTasks 返回追踪产品最近的刷新记录 (asynq 保留的已完成和已归档任务)，按结束时间倒序
+func (l *ListRefreshTasksLogic) ListRefreshTasks(req *types.ListRefreshTasksRequest) (resp *types.ListRefreshTasksResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Limit < 1 || req.Limit > maxRefreshTasksLimit {
+		return nil, errors.NewValidationError("Invalid limit", []errors.FieldError{
+			{Field: "limit", Message: "Limit must be between 1 and 50"},
+		})
+	}
+
+	var trackedProduct models.TrackedProduct
+	if err := l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).First(&trackedProduct).Error; err != nil {
+		return nil, errors.ErrNotFound
+	}
+
+	// 队列由所有用户共享，只查询入队时记入该追踪产品索引的任务
+	taskIDs, err := tasks.RecentRefreshTaskIDs(l.ctx, l.svcCtx.RedisClient, trackedProduct.ID)
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to load refresh task index", "error", err, "tracked_id", trackedProduct.ID)
+		return nil, errors.ErrInternalServer
+	}
+	entries, err := l.lookup(taskIDs, trackedProduct.ID)
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to get refresh task info", "error", err, "tracked_id", trackedProduct.ID)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 已完成任务按完成时间、归档 (最终失败) 任务按最后失败时间合并排序
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].finishedAt.After(entries[j].finishedAt)
+	})
+	if len(entries) > req.Limit {
+		entries = entries[:req.Limit]
+	}
+
+	resp = &types.ListRefreshTasksResponse{Tasks: make([]types.RefreshTask, len(entries))}
+	for i, entry := range entries {
+		resp.Tasks[i] = entry.task
+	}
+	return resp, nil
+}
+
+type refreshTaskEntry struct {
+	task       types.RefreshTask
+	finishedAt time.Time
+}
+
+// lookup 查询索引中的任务，只返回已结束 (已完成或已归档) 的刷新任务；超过保留时间的任务已被 asynq 删除，直接跳过
+func (l *ListRefreshTasksLogic) lookup(taskIDs []string, trackedID string) ([]refreshTaskEntry, error) {
+	var entries []refreshTaskEntry
+	for _, id := range taskIDs {
+		info, err := l.svcCtx.AsynqInspector.GetTaskInfo(tasks.RefreshTaskQueue, id)
+		if stderrors.Is(err, asynq.ErrTaskNotFound) || stderrors.Is(err, asynq.ErrQueueNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if info.Type != tasks.TypeRefreshProductData {
+			continue
+		}
+
+		var finishedAt time.Time
+		switch info.State {
+		case asynq.TaskStateCompleted:
+			finishedAt = info.CompletedAt
+		case asynq.TaskStateArchived:
+			finishedAt = info.LastFailedAt
+		default:
+			continue // 尚未结束
+		}
+		task, payload, err := newRefreshTask(info)
+		if err != nil || payload.TrackedID != trackedID {
+			continue
+		}
+		entries = append(entries, refreshTaskEntry{task: task, finishedAt: finishedAt})
+	}
+	return entries, nil
+}
@@ -0,0 +1,140 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type ListTagsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListTagsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListTagsLogic {
+	return &ListTagsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListTagsLogic) ListTags() (resp *types.ListTagsResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []models.Tag
+	if err := l.svcCtx.DB.Where("workspace_id = ?", access.WorkspaceID).Order("LOWER(name) ASC").Find(&tags).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to query tags", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	counts, err := tagTrackedCounts(l.svcCtx.DB, access.WorkspaceID)
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to count tagged products", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp = &types.ListTagsResponse{Tags: make([]types.Tag, 0, len(tags))}
+	for _, tag := range tags {
+		resp.Tags = append(resp.Tags, toTagResponse(tag, counts[tag.ID]))
+	}
+	return resp, nil
+}
+
+// findWorkspaceTag 查询属于工作区的标签
+func findWorkspaceTag(db *gorm.DB, tagID, workspaceID string) (*models.Tag, error) {
+	var tag models.Tag
+	err := db.Where("id = ? AND workspace_id = ?", tagID, workspaceID).First(&tag).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// tagNameTaken 检查工作区是否已有同名标签（不区分大小写），excludeID 为当前标签
+func tagNameTaken(db *gorm.DB, workspaceID, name, excludeID string) (bool, error) {
+	query := db.Model(&models.Tag{}).Where("workspace_id = ? AND LOWER(name) = LOWER(?)", workspaceID, name)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// tagTrackedCounts 统计工作区每个标签关联的追踪产品数
+func tagTrackedCounts(db *gorm.DB, workspaceID string) (map[string]int, error) {
+	var rows []struct {
+		TagID string
+		Count int
+	}
+	if err := db.Table("tracked_product_tags").
+		Select("tracked_product_tags.tag_id, COUNT(*) AS count").
+		Joins("JOIN tags ON tags.id = tracked_product_tags.tag_id").
+		Where("tags.workspace_id = ?", workspaceID).
+		Group("tracked_product_tags.tag_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.TagID] = row.Count
+	}
+	return counts, nil
+}
+
+// loadTagRefs 批量查询追踪产品的标签，按追踪ID分组，标签按名称排序
+func loadTagRefs(db *gorm.DB, trackedIDs []string) (map[string][]types.TagRef, error) {
+	refs := map[string][]types.TagRef{}
+	if len(trackedIDs) == 0 {
+		return refs, nil
+	}
+
+	var rows []struct {
+		TrackedProductID string
+		ID               string
+		Name             string
+		Color            string
+	}
+	if err := db.Table("tracked_product_tags").
+		Select("tracked_product_tags.tracked_product_id, tags.id, tags.name, COALESCE(tags.color, '') AS color").
+		Joins("JOIN tags ON tags.id = tracked_product_tags.tag_id").
+		Where("tracked_product_tags.tracked_product_id IN ?", trackedIDs).
+		Order("LOWER(tags.name) ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		refs[row.TrackedProductID] = append(refs[row.TrackedProductID], types.TagRef{ID: row.ID, Name: row.Name, Color: row.Color})
+	}
+	return refs, nil
+}
+
+func toTagResponse(tag models.Tag, trackedCount int) types.Tag {
+	return types.Tag{
+		ID:           tag.ID,
+		Name:         tag.Name,
+		Color:        tag.Color,
+		TrackedCount: trackedCount,
+		CreatedAt:    tag.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    tag.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -4,17 +4,15 @@ import (
 	"amazonpilot/internal/pkg/constants"
 	"amazonpilot/internal/pkg/logger"
 	"context"
-	"encoding/json"
 	"time"
 
-	"amazonpilot/internal/pkg/cache"
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/models"
-	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/tasks"
+	"amazonpilot/internal/pkg/workspace"
 	"amazonpilot/internal/product/svc"
 	"amazonpilot/internal/product/types"
 
-	"github.com/hibiken/asynq"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
@@ -33,39 +31,30 @@ func NewRefreshProductDataLogic(ctx context.Context, svcCtx *svc.ServiceContext)
 }
 
 func (l *RefreshProductDataLogic) RefreshProductData(req *types.RefreshProductDataRequest) (resp *types.RefreshProductDataResponse, err error) {
-	// 获取用户ID验证权限
-	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
 	if err != nil {
 		return nil, err
 	}
 
-	// 验证用户是否追踪这个产品
+	// 验证工作区是否追踪这个产品
 	var trackedProduct models.TrackedProduct
-	if err := l.svcCtx.DB.Where("id = ? AND user_id = ?", req.ProductID, userIDStr).
+	if err := l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).
 		Preload("Product").
 		First(&trackedProduct).Error; err != nil {
 		l.Errorf("Failed to find tracked product: %v", err)
 		return nil, errors.ErrNotFound
 	}
 
-	// 创建异步任务payload
-	taskPayload := map[string]interface{}{
-		"product_id":   trackedProduct.ProductID,
-		"tracked_id":   trackedProduct.ID,
-		"asin":         trackedProduct.Product.ASIN,
-		"user_id":      userIDStr,
-		"requested_at": time.Now().Format(time.RFC3339),
-	}
-
-	payloadBytes, err := json.Marshal(taskPayload)
-	if err != nil {
-		l.Errorf("Failed to marshal task payload: %v", err)
-		return nil, errors.ErrInternalServer
-	}
-
-	// 发送异步任务到Redis队列
-	task := asynq.NewTask("refresh_product_data", payloadBytes)
-	info, err := l.svcCtx.AsynqClient.Enqueue(task)
+	// 创建异步任务，完成后任务状态和结果在 asynq 中保留，供状态查询
+	// 发送异步任务到Redis队列，任务ID记入追踪产品的最近刷新索引
+	info, err := tasks.EnqueueRefreshTask(l.ctx, l.svcCtx.AsynqClient, l.svcCtx.RedisClient, tasks.RefreshProductDataPayload{
+		ProductID:   trackedProduct.ProductID,
+		TrackedID:   trackedProduct.ID,
+		ASIN:        trackedProduct.Product.ASIN,
+		UserID:      access.UserID,
+		RequestedAt: time.Now().Format(time.RFC3339),
+	})
 	if err != nil {
 		l.Errorf("Failed to enqueue refresh task: %v", err)
 		return nil, errors.ErrInternalServer
@@ -73,12 +62,11 @@ func (l *RefreshProductDataLogic) RefreshProductData(req *types.RefreshProductDa
 
 	l.Infof("Enqueued refresh task for ASIN %s, task ID: %s", trackedProduct.Product.ASIN, info.ID)
 
-	// 根据设计文档清理相关缓存，确保下次查询获取最新数据
-	go l.invalidateProductCache(trackedProduct.Product.ASIN, trackedProduct.ProductID, userIDStr)
-
 	resp = &types.RefreshProductDataResponse{
 		Success: true,
 		Message: "Product data refresh task has been queued successfully. Data will be updated in background.",
+		TaskID:  info.ID,
+		Status:  info.State.String(),
 	}
 
 	// 记录业务操作
@@ -88,14 +76,3 @@ func (l *RefreshProductDataLogic) RefreshProductData(req *types.RefreshProductDa
 
 	return resp, nil
 }
-
-// invalidateProductCache 根据设计文档清理产品相关缓存（使用统一缓存key）
-func (l *RefreshProductDataLogic) invalidateProductCache(asin, productID, userID string) {
-	ctx := context.Background()
-
-	// 使用统一的缓存key清理产品数据缓存
-	productDataKey := cache.ProductDataKey(productID)
-	l.svcCtx.RedisClient.Del(ctx, productDataKey)
-
-	l.Infof("Invalidated product cache for product %s (ASIN: %s)", productID, asin)
-}
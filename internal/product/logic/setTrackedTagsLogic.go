@@ -0,0 +1,109 @@
+package logic
+
+import (
+	"context"
+
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type SetTrackedTagsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSetTrackedTagsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SetTrackedTagsLogic {
+	return &SetTrackedTagsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SetTrackedTags 将追踪产品的标签替换为请求中的标签
+func (l *SetTrackedTagsLogic) SetTrackedTags(req *types.SetTrackedTagsRequest) (resp *types.SetTrackedTagsResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	var trackedProduct models.TrackedProduct
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.TrackedID, access.WorkspaceID).First(&trackedProduct).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Failed to load tracked product", "tracked_id", req.TrackedID, "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	tagIDs := []string{}
+	for _, id := range req.TagIDs {
+		if id != "" && !containsString(tagIDs, id) {
+			tagIDs = append(tagIDs, id)
+		}
+	}
+
+	// 所有标签必须属于当前用户
+	if len(tagIDs) > 0 {
+		var owned int64
+		if err := l.svcCtx.DB.Model(&models.Tag{}).
+			Where("id IN ? AND workspace_id = ?", tagIDs, access.WorkspaceID).
+			Count(&owned).Error; err != nil {
+			utils.LogError(l.ctx, "Failed to verify tags", "error", err)
+			return nil, errors.ErrInternalServer
+		}
+		if int(owned) != len(tagIDs) {
+			return nil, errors.NewValidationError("Invalid tags", []errors.FieldError{
+				{Field: "tag_ids", Message: "One or more tags do not exist"},
+			})
+		}
+	}
+
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tracked_product_id = ?", trackedProduct.ID).Delete(&models.TrackedProductTag{}).Error; err != nil {
+			return err
+		}
+		for _, tagID := range tagIDs {
+			link := models.TrackedProductTag{TrackedProductID: trackedProduct.ID, TagID: tagID}
+			if err := tx.Create(&link).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to set tracked product tags", "tracked_id", trackedProduct.ID, "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	refs, err := loadTagRefs(l.svcCtx.DB, []string{trackedProduct.ID})
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to load tracked product tags", "tracked_id", trackedProduct.ID, "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 记录业务日志
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "set_tracked_tags", "tracked_product", trackedProduct.ID, "success",
+		"user_id", access.UserID,
+		"tags", len(tagIDs))
+
+	resp = &types.SetTrackedTagsResponse{
+		TrackedID: trackedProduct.ID,
+		Tags:      refs[trackedProduct.ID],
+	}
+	if resp.Tags == nil {
+		resp.Tags = []types.TagRef{}
+	}
+	return resp, nil
+}
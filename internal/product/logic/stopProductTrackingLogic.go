@@ -5,9 +5,9 @@ import (
 
 	"amazonpilot/internal/product/svc"
 	"amazonpilot/internal/product/types"
-	"amazonpilot/internal/pkg/cache"
 	"amazonpilot/internal/pkg/errors"
 	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/workspace"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
@@ -28,20 +28,15 @@ func NewStopProductTrackingLogic(ctx context.Context, svcCtx *svc.ServiceContext
 }
 
 func (l *StopProductTrackingLogic) StopProductTracking(req *types.StopTrackingRequest) (resp *types.StopTrackingResponse, err error) {
-	// 从JWT context获取用户ID
-	userID := l.ctx.Value("user_id")
-	if userID == nil {
-		return nil, errors.ErrUnauthorized
-	}
-	
-	userIDStr, ok := userID.(string)
-	if !ok {
-		return nil, errors.ErrUnauthorized
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
+	if err != nil {
+		return nil, err
 	}
 
 	// 查找追踪记录
 	var trackedProduct models.TrackedProduct
-	err = l.svcCtx.DB.Where("id = ? AND user_id = ?", req.ProductID, userIDStr).First(&trackedProduct).Error
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).First(&trackedProduct).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, errors.ErrNotFound
 	} else if err != nil {
@@ -55,19 +50,10 @@ func (l *StopProductTrackingLogic) StopProductTracking(req *types.StopTrackingRe
 		return nil, errors.ErrInternalServer
 	}
 
-	// 清除与该产品相关的缓存（按产品缓存）
-	productCacheKey := cache.ProductDataKey(trackedProduct.ProductID)
-	if err := l.svcCtx.RedisClient.Del(l.ctx, productCacheKey).Err(); err != nil {
-		l.Errorf("Failed to clear product data cache for product %s: %v", trackedProduct.ProductID, err)
-		// 不影响主流程，继续执行
-	} else {
-		l.Infof("Cleared product data cache for product %s", trackedProduct.ProductID)
-	}
-
 	resp = &types.StopTrackingResponse{
 		Message: "Product tracking stopped successfully",
 	}
 
-	l.Infof("User %s stopped tracking product %s", userIDStr, req.ProductID)
+	l.Infof("User %s stopped tracking product %s", access.UserID, req.ProductID)
 	return resp, nil
 }
@@ -0,0 +1,92 @@
+package logic
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/events"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/product/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 心跳间隔，需小于网关和负载均衡器的空闲连接超时
+const eventStreamHeartbeat = 25 * time.Second
+
+type StreamEventsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewStreamEventsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *StreamEventsLogic {
+	return &StreamEventsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// StreamEvents 以 Server-Sent Events 推送当前用户的刷新、异常和报告事件，直到客户端断开
+func (l *StreamEventsLogic) StreamEvents(w http.ResponseWriter) error {
+	// 从JWT context获取用户ID
+	userIDStr, err := utils.GetUserIDFromContext(l.ctx)
+	if err != nil {
+		return err
+	}
+
+	sub, err := l.svcCtx.EventBus.Subscribe(l.ctx, userIDStr)
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to subscribe user events", "error", err, "user_id", userIDStr)
+		return errors.ErrInternalServer
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	w.WriteHeader(http.StatusOK)
+	if err := events.WriteHeartbeat(w); err != nil {
+		return nil
+	}
+	if err := rc.Flush(); err != nil {
+		utils.LogError(l.ctx, "Event stream does not support flushing", "error", err, "user_id", userIDStr)
+		return nil
+	}
+
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "stream_events_opened", "user", userIDStr, "success")
+	start := time.Now()
+	defer func() {
+		logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "stream_events_closed", "user", userIDStr, "success",
+			"duration_seconds", int(time.Since(start).Seconds()),
+		)
+	}()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	// 响应头已写出，之后的写入失败说明客户端已断开，直接结束
+	for {
+		select {
+		case <-l.ctx.Done():
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+			if err := events.WriteSSE(w, event); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if err := events.WriteHeartbeat(w); err != nil {
+				return nil
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+	}
+}
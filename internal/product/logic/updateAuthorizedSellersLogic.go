@@ -0,0 +1,85 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type UpdateAuthorizedSellersLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateAuthorizedSellersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateAuthorizedSellersLogic {
+	return &UpdateAuthorizedSellersLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateAuthorizedSellersLogic) UpdateAuthorizedSellers(req *types.UpdateAuthorizedSellersRequest) (resp *types.AuthorizedSellersResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证用户是否有权限访问这个产品
+	var trackedProduct models.TrackedProduct
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).First(&trackedProduct).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Database error when checking product access", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 校验并整理白名单（整体替换）
+	allowList := make([]models.SellerIdentity, 0, len(req.Sellers))
+	var fieldErrors []errors.FieldError
+	for i, seller := range req.Sellers {
+		identity := models.SellerIdentity{
+			SellerName: strings.TrimSpace(seller.SellerName),
+			SellerID:   strings.TrimSpace(seller.SellerID),
+		}
+		if identity.SellerName == "" && identity.SellerID == "" {
+			fieldErrors = append(fieldErrors, errors.FieldError{
+				Field:   fmt.Sprintf("sellers[%d]", i),
+				Message: "seller_name or seller_id is required",
+			})
+			continue
+		}
+		allowList = append(allowList, identity)
+	}
+	if len(fieldErrors) > 0 {
+		return nil, errors.NewValidationError("Invalid authorized sellers", fieldErrors)
+	}
+
+	allowListJSON, _ := json.Marshal(allowList)
+	if err := l.svcCtx.DB.Model(&trackedProduct).Update("authorized_sellers", allowListJSON).Error; err != nil {
+		l.Errorf("Failed to update authorized sellers: %v", err)
+		return nil, errors.ErrInternalServer
+	}
+	trackedProduct.AuthorizedSellers = allowListJSON
+
+	// 记录业务日志
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "update_authorized_sellers", "tracked_product", trackedProduct.ID, "success",
+		"sellers_count", len(allowList))
+
+	return buildAuthorizedSellersResponse(l.svcCtx.DB, &trackedProduct)
+}
@@ -0,0 +1,77 @@
+package logic
+
+import (
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+	"context"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type UpdateMAPPriceLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateMAPPriceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateMAPPriceLogic {
+	return &UpdateMAPPriceLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateMAPPriceLogic) UpdateMAPPrice(req *types.UpdateMAPPriceRequest) (resp *types.UpdateMAPPriceResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MAPPrice < 0 {
+		return nil, errors.NewValidationError("Invalid MAP price", []errors.FieldError{
+			{Field: "map_price", Message: "map_price must be greater than or equal to 0"},
+		})
+	}
+
+	// 验证用户是否有权限访问这个产品
+	var trackedProduct models.TrackedProduct
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).First(&trackedProduct).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Database error when checking product access", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 0 表示关闭 MAP 监控
+	var mapPrice interface{}
+	message := "MAP monitoring disabled"
+	if req.MAPPrice > 0 {
+		mapPrice = req.MAPPrice
+		message = "MAP price updated successfully"
+	}
+
+	if err := l.svcCtx.DB.Model(&trackedProduct).Update("map_price", mapPrice).Error; err != nil {
+		l.Errorf("Failed to update MAP price: %v", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 记录业务日志
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "update_map_price", "tracked_product", trackedProduct.ID, "success",
+		"map_price", req.MAPPrice)
+
+	return &types.UpdateMAPPriceResponse{
+		ProductID: trackedProduct.ID,
+		MAPPrice:  req.MAPPrice,
+		Message:   message,
+	}, nil
+}
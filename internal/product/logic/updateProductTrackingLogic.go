@@ -0,0 +1,157 @@
+package logic
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/models"
+	"amazonpilot/internal/pkg/quota"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+// trackingFrequencies 支持的追踪频率
+var trackingFrequencies = []string{"hourly", "daily", "weekly"}
+
+type UpdateProductTrackingLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateProductTrackingLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateProductTrackingLogic {
+	return &UpdateProductTrackingLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateProductTrackingLogic) UpdateProductTracking(req *types.UpdateTrackingRequest) (resp *types.UpdateTrackingResponse, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 查找追踪记录
+	var trackedProduct models.TrackedProduct
+	err = l.svcCtx.DB.Where("id = ? AND workspace_id = ?", req.ProductID, access.WorkspaceID).
+		Preload("Product").
+		First(&trackedProduct).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	} else if err != nil {
+		utils.LogError(l.ctx, "Failed to load tracked product", "tracked_id", req.ProductID, "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 只更新请求中出现的字段
+	updates := map[string]interface{}{}
+	if req.Alias != nil {
+		alias := strings.TrimSpace(*req.Alias)
+		if err := utils.ValidateProductAlias(alias); err != nil {
+			return nil, err
+		}
+		if alias == "" {
+			updates["alias"] = nil
+		} else {
+			updates["alias"] = alias
+		}
+	}
+	if req.PriceChangeThreshold != nil {
+		if err := utils.ValidateThreshold(*req.PriceChangeThreshold, "price_change_threshold"); err != nil {
+			return nil, err
+		}
+		updates["price_change_threshold"] = *req.PriceChangeThreshold
+	}
+	if req.BSRChangeThreshold != nil {
+		if err := utils.ValidateThreshold(*req.BSRChangeThreshold, "bsr_change_threshold"); err != nil {
+			return nil, err
+		}
+		updates["bsr_change_threshold"] = *req.BSRChangeThreshold
+	}
+
+	frequency := trackedProduct.TrackingFrequency
+	if req.TrackingFrequency != nil {
+		frequency = strings.ToLower(strings.TrimSpace(*req.TrackingFrequency))
+		if !containsString(trackingFrequencies, frequency) {
+			return nil, errors.NewValidationError("Invalid tracking frequency", []errors.FieldError{
+				{Field: "tracking_frequency", Message: "Tracking frequency must be one of: " + strings.Join(trackingFrequencies, ", ")},
+			})
+		}
+		// 提高追踪频率需要计划允许
+		if frequency != trackedProduct.TrackingFrequency {
+			if err := quota.CheckFrequency(l.ctx, l.svcCtx.DB, access.WorkspaceID, frequency); err != nil {
+				return nil, err
+			}
+		}
+		updates["tracking_frequency"] = frequency
+	}
+
+	isActive := trackedProduct.IsActive
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+		updates["is_active"] = isActive
+	}
+
+	if len(updates) == 0 {
+		return nil, errors.NewValidationError("No fields to update", []errors.FieldError{
+			{Field: "body", Message: "Provide at least one of alias, price_change_threshold, bsr_change_threshold, tracking_frequency, is_active"},
+		})
+	}
+
+	// 暂停时清空下次检查时间；恢复或频率变化时按新频率重新计算
+	switch {
+	case !isActive:
+		updates["next_check_at"] = nil
+	case !trackedProduct.IsActive || frequency != trackedProduct.TrackingFrequency || trackedProduct.NextCheckAt == nil:
+		updates["next_check_at"] = calculateNextCheckTime(frequency)
+	}
+
+	if err = l.svcCtx.DB.Model(&trackedProduct).Updates(updates).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to update tracking settings", "tracked_id", trackedProduct.ID, "error", err)
+		return nil, errors.ErrInternalServer
+	}
+	if err = l.svcCtx.DB.First(&trackedProduct, "id = ?", trackedProduct.ID).Error; err != nil {
+		utils.LogError(l.ctx, "Failed to reload tracked product", "tracked_id", trackedProduct.ID, "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	resp = &types.UpdateTrackingResponse{
+		TrackedID:            trackedProduct.ID,
+		ProductID:            trackedProduct.ProductID,
+		ASIN:                 trackedProduct.Product.ASIN,
+		Alias:                getStringValue(trackedProduct.Alias),
+		IsActive:             trackedProduct.IsActive,
+		TrackingFrequency:    trackedProduct.TrackingFrequency,
+		PriceChangeThreshold: trackedProduct.PriceChangeThreshold,
+		BSRChangeThreshold:   trackedProduct.BSRChangeThreshold,
+		UpdatedAt:            trackedProduct.UpdatedAt.Format(time.RFC3339),
+	}
+	if trackedProduct.NextCheckAt != nil {
+		resp.NextCheckAt = trackedProduct.NextCheckAt.Format(time.RFC3339)
+	}
+
+	// 记录业务日志
+	fields := make([]string, 0, len(updates))
+	for field := range updates {
+		fields = append(fields, field)
+	}
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "update_tracking", "tracked_product", trackedProduct.ID, "success",
+		"user_id", access.UserID,
+		"fields", strings.Join(fields, ","),
+		"is_active", trackedProduct.IsActive,
+		"tracking_frequency", trackedProduct.TrackingFrequency)
+
+	return resp, nil
+}
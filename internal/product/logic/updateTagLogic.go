@@ -0,0 +1,91 @@
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"amazonpilot/internal/pkg/constants"
+	"amazonpilot/internal/pkg/errors"
+	"amazonpilot/internal/pkg/logger"
+	"amazonpilot/internal/pkg/utils"
+	"amazonpilot/internal/pkg/workspace"
+	"amazonpilot/internal/product/svc"
+	"amazonpilot/internal/product/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateTagLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateTagLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateTagLogic {
+	return &UpdateTagLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateTagLogic) UpdateTag(req *types.UpdateTagRequest) (resp *types.Tag, err error) {
+	// 校验当前用户在工作区中的角色
+	access, err := workspace.Authorize(l.ctx, l.svcCtx.DB, workspace.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := findWorkspaceTag(l.svcCtx.DB, req.TagID, access.WorkspaceID)
+	if err == errors.ErrNotFound {
+		return nil, err
+	} else if err != nil {
+		utils.LogError(l.ctx, "Failed to load tag", "tag_id", req.TagID, "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := utils.ValidateTagName(name); err != nil {
+			return nil, err
+		}
+		taken, err := tagNameTaken(l.svcCtx.DB, access.WorkspaceID, name, tag.ID)
+		if err != nil {
+			utils.LogError(l.ctx, "Failed to check tag name", "error", err)
+			return nil, errors.ErrInternalServer
+		}
+		if taken {
+			return nil, errors.NewConflictError("Tag already exists")
+		}
+		updates["name"] = name
+	}
+	if req.Color != nil {
+		color := strings.TrimSpace(*req.Color)
+		if err := utils.ValidateTagColor(color); err != nil {
+			return nil, err
+		}
+		updates["color"] = color
+	}
+
+	if len(updates) > 0 {
+		if err := l.svcCtx.DB.Model(tag).Updates(updates).Error; err != nil {
+			utils.LogError(l.ctx, "Failed to update tag", "tag_id", tag.ID, "error", err)
+			return nil, errors.ErrInternalServer
+		}
+	}
+
+	counts, err := tagTrackedCounts(l.svcCtx.DB, access.WorkspaceID)
+	if err != nil {
+		utils.LogError(l.ctx, "Failed to count tagged products", "error", err)
+		return nil, errors.ErrInternalServer
+	}
+
+	// 记录业务日志
+	logger.GlobalLogger(constants.ServiceProduct).LogBusinessOperation(l.ctx, "update_tag", "tag", tag.ID, "success",
+		"user_id", access.UserID,
+		"name", tag.Name)
+
+	result := toTagResponse(*tag, counts[tag.ID])
+	return &result, nil
+}
@@ -1,11 +1,17 @@
 package svc
 
 import (
+	"context"
+
 	"amazonpilot/internal/product/config"
 	"amazonpilot/internal/product/middleware"
 	"amazonpilot/internal/pkg/apify"
 	"amazonpilot/internal/pkg/auth"
+	"amazonpilot/internal/pkg/cache"
 	"amazonpilot/internal/pkg/database"
+	"amazonpilot/internal/pkg/estimation"
+	"amazonpilot/internal/pkg/events"
+	"amazonpilot/internal/pkg/fx"
 
 	"github.com/hibiken/asynq"
 	"github.com/redis/go-redis/v9"
@@ -17,9 +23,14 @@ type ServiceContext struct {
 	Config               config.Config
 	DB                   *gorm.DB
 	RedisClient          *redis.Client
+	ProductCache         *cache.Store[cache.ProductData]
+	EventBus             *events.Bus
 	AsynqClient          *asynq.Client
+	AsynqInspector       *asynq.Inspector
 	ApifyClient          *apify.Client
 	JWTAuth              *auth.JWTAuth
+	FX                   *fx.Converter
+	Sales                *estimation.Model
 	RateLimitMiddleware  rest.Middleware
 }
 
@@ -48,10 +59,14 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	})
 
 	// 初始化Asynq客户端
-	asynqClient := asynq.NewClient(asynq.RedisClientOpt{
+	asynqOpt := asynq.RedisClientOpt{
 		Addr: envCfg.Redis.Addr,
 		DB:   envCfg.Redis.DB,
-	})
+	}
+	asynqClient := asynq.NewClient(asynqOpt)
+
+	// 初始化Asynq Inspector (查询刷新任务状态)
+	asynqInspector := asynq.NewInspector(asynqOpt)
 
 	// 初始化Apify客户端
 	apifyClient := apify.NewClient(envCfg.APIKeys.ApifyToken)
@@ -59,6 +74,19 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	// 初始化JWT认证
 	jwtAuth := auth.NewJWTAuth(envCfg.JWT.Secret, envCfg.JWT.AccessExpire)
 
+	// 加载汇率数据
+	fxConverter, err := fx.LoadConverter(envCfg.FX.RatesFile)
+	if err != nil {
+		panic("Failed to load fx rates: " + err.Error())
+	}
+	fx.StartProviderSync(context.Background(), fxConverter, envCfg.FX.ProviderURL)
+
+	// 加载 BSR -> 销量估算曲线
+	salesModel, err := estimation.LoadModel(envCfg.Estimation.CurvesFile)
+	if err != nil {
+		panic("Failed to load sales curves: " + err.Error())
+	}
+
 	// 初始化中间件
 	rateLimitMiddleware := middleware.NewRateLimitMiddleware()
 
@@ -66,9 +94,14 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		Config:              c,
 		DB:                  db,
 		RedisClient:         redisClient,
+		ProductCache:        cache.NewProductStore(redisClient),
+		EventBus:            events.NewBus(redisClient),
 		AsynqClient:         asynqClient,
+		AsynqInspector:      asynqInspector,
 		ApifyClient:         apifyClient,
 		JWTAuth:             jwtAuth,
+		FX:                  fxConverter,
+		Sales:               salesModel,
 		RateLimitMiddleware: rateLimitMiddleware.Handle,
 	}
 }
\ No newline at end of file
@@ -4,29 +4,42 @@
 package types
 
 type AddTrackingRequest struct {
-	ASIN     string           `json:"asin"`
-	Alias    string           `json:"alias,optional"`
-	Category string           `json:"category,optional"`
-	Settings TrackingSettings `json:"tracking_settings,optional"`
+	ASIN            string           `json:"asin"`
+	Alias           string           `json:"alias,optional"`
+	Category        string           `json:"category,optional"`
+	Settings        TrackingSettings `json:"tracking_settings,optional"`
+	TrackVariations bool             `json:"track_variations,optional"` // 自动追踪子变体
 }
 
 type TrackingSettings struct {
 	PriceChangeThreshold float64 `json:"price_change_threshold,default=10"`
 	BSRChangeThreshold   float64 `json:"bsr_change_threshold,default=30"`
+	MAPPrice             float64 `json:"map_price,optional"` // 最低广告价 (MAP)，0 表示不监控
 }
 
 type AddTrackingResponse struct {
-	ProductID  string `json:"product_id"`
-	ASIN       string `json:"asin"`
-	Status     string `json:"status"`
-	NextUpdate string `json:"next_update"`
+	ProductID     string `json:"product_id"`
+	ASIN          string `json:"asin"`
+	Status        string `json:"status"`
+	NextUpdate    string `json:"next_update"`
+	RefreshTaskID string `json:"refresh_task_id,omitempty"` // 初始数据获取任务ID，可查询刷新状态
 }
 
 type GetTrackedRequest struct {
-	Page     int    `form:"page,default=1"`
-	Limit    int    `form:"limit,default=20"`
-	Category string `form:"category,optional"`
-	Status   string `form:"status,optional"`
+	Page      int     `form:"page,default=1"`
+	Limit     int     `form:"limit,default=20"`
+	Cursor    string  `form:"cursor,optional"`    // 上一页返回的 next_cursor，提供时按游标分页 (不统计 total)
+	Category  string  `form:"category,optional"`  // 精确匹配产品类目
+	Brand     string  `form:"brand,optional"`     // 精确匹配品牌
+	Status    string  `form:"status,optional"`    // active, inactive (暂停)
+	TagIDs    string  `form:"tag_ids,optional"`   // 逗号分隔的标签ID，返回带有其中任一标签的产品
+	Search    string  `form:"search,optional"`    // 按标题、品牌、ASIN、别名模糊搜索
+	MinPrice  float64 `form:"min_price,optional"` // 当前价格范围，0 表示不限
+	MaxPrice  float64 `form:"max_price,optional"`
+	MinRating float64 `form:"min_rating,optional"` // 当前评分范围，0 表示不限
+	MaxRating float64 `form:"max_rating,optional"`
+	SortBy    string  `form:"sort_by,optional"`    // created_at (默认), current_price, price_change_24h, bsr, rating, review_count, last_updated
+	SortOrder string  `form:"sort_order,optional"` // asc, desc (默认)
 }
 
 type GetTrackedResponse struct {
@@ -38,11 +51,13 @@ type TrackedProduct struct {
 	ID               string           `json:"id"`         // tracked_product.id
 	ProductID        string           `json:"product_id"` // product.id (用于竞品分析)
 	ASIN             string           `json:"asin"`
+	ParentASIN       string           `json:"parent_asin,omitempty"`
 	Title            string           `json:"title,omitempty"`
 	Brand            string           `json:"brand,omitempty"`
 	Category         string           `json:"category,omitempty"`
 	Alias            string           `json:"alias,omitempty"`
 	CurrentPrice     float64          `json:"current_price"`
+	PriceChange24h   float64          `json:"price_change_24h,omitempty"` // 相对24小时前价格的变化百分比
 	Currency         string           `json:"currency"`
 	BSR              int              `json:"bsr,omitempty"`
 	BSRCategory      string           `json:"bsr_category,omitempty"`
@@ -51,6 +66,7 @@ type TrackedProduct struct {
 	BuyBoxPrice      float64          `json:"buy_box_price,omitempty"`
 	LastUpdated      string           `json:"last_updated"`
 	Status           string           `json:"status"`
+	Tags             []TagRef         `json:"tags,omitempty"`
 	Images           []string         `json:"images,omitempty"`
 	Description      string           `json:"description,omitempty"`
 	BulletPoints     []string         `json:"bullet_points,omitempty"`
@@ -69,10 +85,12 @@ type HistorySummary struct {
 }
 
 type Pagination struct {
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	Total      int `json:"total"`
-	TotalPages int `json:"total_pages"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor,omitempty"` // 游标模式下的下一页游标，为空表示没有更多数据
+	HasMore    bool   `json:"has_more,omitempty"`
 }
 
 type GetProductRequest struct {
@@ -80,21 +98,27 @@ type GetProductRequest struct {
 }
 
 type GetProductResponse struct {
-	ID              string                 `json:"id"`
-	ASIN            string                 `json:"asin"`
-	Title           string                 `json:"title,omitempty"`
-	Description     string                 `json:"description,omitempty"`
-	Brand           string                 `json:"brand,omitempty"`
-	Category        string                 `json:"category,omitempty"`
-	CurrentPrice    float64                `json:"current_price"`
-	Currency        string                 `json:"currency"`
-	BSR             int                    `json:"bsr,omitempty"`
-	Rating          float64                `json:"rating,omitempty"`
-	ReviewCount     int                    `json:"review_count"`
-	Images          []string               `json:"images,omitempty"`
-	BulletPoints    []string               `json:"bullet_points,omitempty"`
-	TrackingHistory TrackingHistorySummary `json:"tracking_history"`
-	Alerts          []Alert                `json:"alerts,omitempty"`
+	ID                   string                 `json:"id"`
+	ASIN                 string                 `json:"asin"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Brand                string                 `json:"brand,omitempty"`
+	Category             string                 `json:"category,omitempty"`
+	CurrentPrice         float64                `json:"current_price"`
+	Currency             string                 `json:"currency"`
+	BSR                  int                    `json:"bsr,omitempty"`
+	Rating               float64                `json:"rating,omitempty"`
+	ReviewCount          int                    `json:"review_count"`
+	PriceChange24h       float64                `json:"price_change_24h,omitempty"` // 相对24小时前价格的变化百分比
+	BuyBoxPrice          float64                `json:"buy_box_price,omitempty"`
+	BSRChange24h         int                    `json:"bsr_change_24h,omitempty"` // 正数表示排名下降
+	ReviewCountChange24h int                    `json:"review_count_change_24h,omitempty"`
+	Availability         string                 `json:"availability,omitempty"` // in_stock, low_stock, out_of_stock, back_order, unavailable, unknown
+	LastUpdated          string                 `json:"last_updated,omitempty"`
+	Images               []string               `json:"images,omitempty"`
+	BulletPoints         []string               `json:"bullet_points,omitempty"`
+	TrackingHistory      TrackingHistorySummary `json:"tracking_history"`
+	Alerts               []Alert                `json:"alerts,omitempty"`
 }
 
 type TrackingHistorySummary struct {
@@ -111,21 +135,551 @@ type Alert struct {
 
 type GetHistoryRequest struct {
 	ProductID string `path:"product_id"`
-	Metric    string `form:"metric,optional"`
+	Metric    string `form:"metric,optional"` // price, bsr, rating, review_count, buybox, est_sales
 	Period    string `form:"period,optional"`
+	Category  string `form:"category,optional"` // metric=bsr 时可选的类目（根类目或小类目），默认根类目
+	Currency  string `form:"currency,optional"` // 展示货币，默认使用原始币种
+	From      string `form:"from,optional"`     // RFC3339 或 YYYY-MM-DD，指定后忽略 period
+	To        string `form:"to,optional"`       // RFC3339 或 YYYY-MM-DD (含当天)，默认当前时间
+	Bucket    string `form:"bucket,optional"`   // raw (默认), hour, day, week, month
+	Agg       string `form:"agg,optional"`      // last (默认), min, max, avg, ohlc
+	TZ        string `form:"tz,optional"`       // IANA 时区，用于分桶和日期，默认 UTC
+	Metrics   string `form:"metrics,optional"`  // 逗号分隔的多个指标，结果在 series 中返回
 }
 
 type GetHistoryResponse struct {
-	ProductID string        `json:"product_id"`
-	Metric    string        `json:"metric"`
-	Period    string        `json:"period"`
-	Data      []HistoryData `json:"data"`
+	ProductID     string          `json:"product_id"`
+	Metric        string          `json:"metric"`
+	Period        string          `json:"period"`
+	Currency      string          `json:"currency,omitempty"`
+	Category      string          `json:"category,omitempty"`
+	Categories    []string        `json:"categories,omitempty"` // metric=bsr 时可选的类目列表
+	Data          []HistoryData   `json:"data"`
+	From          string          `json:"from"`
+	To            string          `json:"to"`
+	Bucket        string          `json:"bucket"`
+	Agg           string          `json:"agg"`
+	TZ            string          `json:"tz"`
+	Series        []HistorySeries `json:"series,omitempty"`         // 请求 metrics 时每个指标一个序列
+	SalesEstimate *SalesEstimate  `json:"sales_estimate,omitempty"` // metric 为 bsr 或 est_sales 时返回
 }
 
 type HistoryData struct {
-	Date     string  `json:"date"`
-	Value    float64 `json:"value"`
-	Currency string  `json:"currency,omitempty"`
+	Date             string  `json:"date"`
+	Timestamp        string  `json:"timestamp"` // 数据点或桶开始时间 (RFC3339，请求时区)
+	Value            float64 `json:"value"`
+	Currency         string  `json:"currency,omitempty"`
+	OriginalValue    float64 `json:"original_value,omitempty"`
+	OriginalCurrency string  `json:"original_currency,omitempty"`
+	Open             float64 `json:"open,omitempty"`  // agg=ohlc
+	High             float64 `json:"high,omitempty"`  // agg=ohlc
+	Low              float64 `json:"low,omitempty"`   // agg=ohlc
+	Close            float64 `json:"close,omitempty"` // agg=ohlc
+	Count            int     `json:"count,omitempty"` // 桶内原始数据点数
+}
+
+type HistorySeries struct {
+	Metric   string        `json:"metric"`
+	Currency string        `json:"currency,omitempty"`
+	Category string        `json:"category,omitempty"`
+	Data     []HistoryData `json:"data"`
+}
+
+type SalesEstimate struct {
+	EstUnitsPerDay    float64 `json:"est_units_per_day"` // 期间平均日销量
+	EstTotalUnits     float64 `json:"est_total_units"`
+	EstRevenue        float64 `json:"est_revenue"`
+	EstMonthlyRevenue float64 `json:"est_monthly_revenue"`
+	Currency          string  `json:"currency"`
+	LatestUnitsPerDay float64 `json:"latest_units_per_day"`
+	Days              float64 `json:"days"`
+}
+
+type CompareHistoryRequest struct {
+	TrackedIDs string `form:"tracked_ids,optional"` // 逗号分隔的追踪ID (与 group_id 二选一)
+	GroupID    string `form:"group_id,optional"`    // 竞品分析组ID，包含主产品和全部竞品
+	Metric     string `form:"metric,optional"`      // price (默认), bsr, rating, review_count, buybox, est_sales
+	Period     string `form:"period,optional"`
+	From       string `form:"from,optional"`
+	To         string `form:"to,optional"`
+	Bucket     string `form:"bucket,optional"` // hour, day (默认), week, month
+	Agg        string `form:"agg,optional"`    // last (默认), min, max, avg
+	TZ         string `form:"tz,optional"`
+	Currency   string `form:"currency,optional"`  // 价格类指标统一换算的货币
+	Normalize  string `form:"normalize,optional"` // none (默认), index (起点=100), pct_change (相对起点的百分比变化)
+}
+
+type CompareHistoryResponse struct {
+	Metric     string          `json:"metric"`
+	Period     string          `json:"period"`
+	From       string          `json:"from"`
+	To         string          `json:"to"`
+	Bucket     string          `json:"bucket"`
+	Agg        string          `json:"agg"`
+	TZ         string          `json:"tz"`
+	Currency   string          `json:"currency,omitempty"`
+	Normalize  string          `json:"normalize"`
+	Timestamps []string        `json:"timestamps"` // 所有序列共同的桶开始时间
+	Series     []CompareSeries `json:"series"`
+}
+
+type CompareSeries struct {
+	ProductID string        `json:"product_id"`
+	TrackedID string        `json:"tracked_id,omitempty"`
+	ASIN      string        `json:"asin"`
+	Title     string        `json:"title,omitempty"`
+	Role      string        `json:"role"` // tracked, main, competitor
+	Currency  string        `json:"currency,omitempty"`
+	Base      float64       `json:"base,omitempty"` // 归一化基准值（所有产品都有数据的共同起点的原始值）
+	Values    []interface{} `json:"values"`         // 与 timestamps 对齐，第一个数据点之前为 null，缺失的桶沿用上一个值
+}
+
+type GetVariationsRequest struct {
+	ProductID string `path:"product_id"`
+}
+
+type GetVariationsResponse struct {
+	ProductID   string              `json:"product_id"`
+	ParentASIN  string              `json:"parent_asin,omitempty"`
+	Variations  []VariationProduct  `json:"variations"`
+	PriceRange  VariationPriceRange `json:"price_range"`
+	BSRRange    VariationBSRRange   `json:"bsr_range"`
+	Rating      float64             `json:"rating,omitempty"` // 家族共享评分
+	ReviewCount int                 `json:"review_count"`     // 家族共享评论数
+}
+
+type VariationProduct struct {
+	ProductID   string            `json:"product_id"`
+	ASIN        string            `json:"asin"`
+	Title       string            `json:"title,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	Price       float64           `json:"price"`
+	Currency    string            `json:"currency"`
+	BSR         int               `json:"bsr,omitempty"`
+	Rating      float64           `json:"rating,omitempty"`
+	ReviewCount int               `json:"review_count"`
+	TrackedID   string            `json:"tracked_id,omitempty"` // 当前用户的追踪ID，未追踪时为空
+}
+
+type VariationPriceRange struct {
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Currency string  `json:"currency"`
+}
+
+type VariationBSRRange struct {
+	Best  int `json:"best"`
+	Worst int `json:"worst"`
+}
+
+type GetContentHistoryRequest struct {
+	ProductID string `path:"product_id"`
+	Page      int    `form:"page,default=1"`
+	Limit     int    `form:"limit,default=20"`
+	Cursor    string `form:"cursor,optional"` // 上一页返回的 next_cursor，提供时按游标分页 (不统计 total)
+}
+
+type GetContentHistoryResponse struct {
+	ProductID  string          `json:"product_id"`
+	ASIN       string          `json:"asin"`
+	Timeline   []ContentChange `json:"timeline"`
+	Pagination Pagination      `json:"pagination"`
+}
+
+type ContentChange struct {
+	SnapshotID    string             `json:"snapshot_id"`
+	RecordedAt    string             `json:"recorded_at"`
+	IsBaseline    bool               `json:"is_baseline"` // 最早的版本，没有可比较的前一版本
+	ChangedFields []string           `json:"changed_fields"`
+	Changes       []ContentFieldDiff `json:"changes"`
+}
+
+type ContentFieldDiff struct {
+	Field       string   `json:"field"` // title, description, bullet_points, images
+	Before      string   `json:"before,omitempty"`
+	After       string   `json:"after,omitempty"`
+	BeforeItems []string `json:"before_items,omitempty"`
+	AfterItems  []string `json:"after_items,omitempty"`
+	Added       []string `json:"added,omitempty"`
+	Removed     []string `json:"removed,omitempty"`
+}
+
+type GetOffersRequest struct {
+	ProductID string `path:"product_id"`
+}
+
+type GetOffersResponse struct {
+	ProductID   string         `json:"product_id"`
+	RecordedAt  string         `json:"recorded_at,omitempty"` // 最新报价快照时间
+	SellerCount int            `json:"seller_count"`
+	Offers      []ProductOffer `json:"offers"`
+}
+
+type ProductOffer struct {
+	SellerName     string  `json:"seller_name"`
+	SellerID       string  `json:"seller_id,omitempty"`
+	Price          float64 `json:"price"`
+	ShippingPrice  float64 `json:"shipping_price"`
+	LandedPrice    float64 `json:"landed_price"` // 含运费价格
+	Currency       string  `json:"currency"`
+	Condition      string  `json:"condition"`
+	IsFBA          bool    `json:"is_fba"`
+	IsPrime        bool    `json:"is_prime"`
+	IsBuyBoxWinner bool    `json:"is_buy_box_winner"`
+}
+
+type GetOfferHistoryRequest struct {
+	ProductID string `path:"product_id"`
+	Period    string `form:"period,optional"` // 7d, 30d, 90d
+}
+
+type GetOfferHistoryResponse struct {
+	ProductID string                 `json:"product_id"`
+	Period    string                 `json:"period"`
+	Snapshots []OfferSnapshotSummary `json:"snapshots"`
+}
+
+type OfferSnapshotSummary struct {
+	RecordedAt   string  `json:"recorded_at"`
+	SellerCount  int     `json:"seller_count"`
+	FBACount     int     `json:"fba_count"`
+	LowestPrice  float64 `json:"lowest_price"` // 最低含运费价格
+	LowestSeller string  `json:"lowest_seller"`
+	BuyBoxSeller string  `json:"buy_box_seller,omitempty"`
+	BuyBoxPrice  float64 `json:"buy_box_price,omitempty"`
+	Currency     string  `json:"currency"`
+}
+
+type GetBuyBoxShareRequest struct {
+	ProductID string `path:"product_id"`
+	Period    string `form:"period,optional"` // 7d, 30d, 90d
+}
+
+type GetBuyBoxShareResponse struct {
+	ProductID      string              `json:"product_id"`
+	Period         string              `json:"period"`
+	TotalSnapshots int                 `json:"total_snapshots"`
+	Sellers        []BuyBoxSellerShare `json:"sellers"`
+}
+
+type BuyBoxSellerShare struct {
+	SellerName   string  `json:"seller_name"`
+	SellerID     string  `json:"seller_id,omitempty"`
+	Wins         int     `json:"wins"`
+	SharePercent float64 `json:"share_percent"`
+	LastWonAt    string  `json:"last_won_at"`
+}
+
+type SellerAccount struct {
+	ID         string `json:"id"`
+	SellerName string `json:"seller_name"`
+	SellerID   string `json:"seller_id,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type GetSellerAccountsResponse struct {
+	Sellers []SellerAccount `json:"sellers"`
+}
+
+type AddSellerAccountRequest struct {
+	SellerName string `json:"seller_name"`
+	SellerID   string `json:"seller_id,optional"`
+}
+
+type DeleteSellerAccountRequest struct {
+	SellerAccountID string `path:"seller_account_id"`
+}
+
+type DeleteSellerAccountResponse struct {
+	Message string `json:"message"`
+}
+
+type AuthorizedSeller struct {
+	SellerName string `json:"seller_name"`
+	SellerID   string `json:"seller_id,optional"`
+}
+
+type GetAuthorizedSellersRequest struct {
+	ProductID string `path:"product_id"`
+}
+
+type UpdateAuthorizedSellersRequest struct {
+	ProductID string             `path:"product_id"`
+	Sellers   []AuthorizedSeller `json:"sellers"`
+}
+
+type AuthorizedSellersResponse struct {
+	ProductID  string             `json:"product_id"`
+	Sellers    []AuthorizedSeller `json:"sellers"`
+	OwnSellers []SellerAccount    `json:"own_sellers"` // 用户自有卖家，同样视为授权
+}
+
+type UpdateMAPPriceRequest struct {
+	ProductID string  `path:"product_id"`
+	MAPPrice  float64 `json:"map_price"` // 0 表示关闭 MAP 监控
+}
+
+type UpdateMAPPriceResponse struct {
+	ProductID string  `json:"product_id"`
+	MAPPrice  float64 `json:"map_price"`
+	Message   string  `json:"message"`
+}
+
+type GetMAPViolationsRequest struct {
+	ProductID string `path:"product_id"`
+	StartDate string `form:"start_date,optional"` // YYYY-MM-DD，默认30天前
+	EndDate   string `form:"end_date,optional"`   // YYYY-MM-DD，默认今天
+	Format    string `form:"format,optional"`     // json (默认) 或 csv
+}
+
+type GetMAPViolationsResponse struct {
+	ProductID       string               `json:"product_id"`
+	ASIN            string               `json:"asin"`
+	MAPPrice        float64              `json:"map_price"`
+	StartDate       string               `json:"start_date"`
+	EndDate         string               `json:"end_date"`
+	TotalViolations int                  `json:"total_violations"`
+	Sellers         []MAPViolationSeller `json:"sellers"`
+}
+
+type MAPViolationSeller struct {
+	SellerName         string         `json:"seller_name"`
+	SellerID           string         `json:"seller_id,omitempty"`
+	Violations         int            `json:"violations"`
+	LowestPrice        float64        `json:"lowest_price"`
+	MaxDiscountPercent float64        `json:"max_discount_percent"` // 低于 MAP 的最大幅度
+	FirstSeenAt        string         `json:"first_seen_at"`
+	LastSeenAt         string         `json:"last_seen_at"`
+	Evidence           []MAPViolation `json:"evidence"`
+}
+
+type MAPViolation struct {
+	ObservedAt      string  `json:"observed_at"`
+	Price           float64 `json:"price"`
+	Currency        string  `json:"currency"`
+	DiscountPercent float64 `json:"discount_percent"`
+	IsBuyBoxWinner  bool    `json:"is_buy_box_winner"`
+}
+
+type GetAvailabilityRequest struct {
+	ProductID string `path:"product_id"`
+	Period    string `form:"period,optional"` // 7d, 30d, 90d
+}
+
+type GetAvailabilityResponse struct {
+	ProductID      string              `json:"product_id"`
+	Period         string              `json:"period"`
+	CurrentState   string              `json:"current_state"`
+	InStockPercent float64             `json:"in_stock_percent"` // 可购买状态的记录占比
+	StockoutCount  int                 `json:"stockout_count"`   // 期间断货次数
+	Points         []AvailabilityPoint `json:"points"`
+}
+
+type AvailabilityPoint struct {
+	RecordedAt       string `json:"recorded_at"`
+	State            string `json:"state"` // in_stock, low_stock, out_of_stock, back_order, unavailable, unknown
+	StockQuantity    int    `json:"stock_quantity,omitempty"`
+	AvailabilityText string `json:"availability_text,omitempty"`
+}
+
+type ImportTrackingRequest struct {
+	Format string               `json:"format,optional"` // csv, json；上传文件时按 Content-Type / 扩展名判断
+	Data   string               `json:"data,optional"`   // CSV 文本 (format=csv)
+	Items  []ImportTrackingItem `json:"items,optional"`  // JSON 行 (format=json)
+	Async  bool                 `json:"async,optional"`  // 强制异步处理；超过100行时总是异步
+}
+
+type ImportTrackingItem struct {
+	ASIN                 string   `json:"asin"`
+	Alias                string   `json:"alias,optional"`
+	PriceChangeThreshold float64  `json:"price_change_threshold,optional"`
+	BSRChangeThreshold   float64  `json:"bsr_change_threshold,optional"`
+	Tags                 []string `json:"tags,optional"`
+}
+
+type ImportTrackingResponse struct {
+	JobID          string            `json:"job_id,omitempty"` // 异步处理时返回
+	Status         string            `json:"status"`           // completed, queued
+	Total          int               `json:"total"`
+	Created        int               `json:"created"`
+	AlreadyTracked int               `json:"already_tracked"`
+	Failed         int               `json:"failed"`
+	Results        []ImportRowResult `json:"results"`
+}
+
+type ImportRowResult struct {
+	Line      int    `json:"line"`
+	ASIN      string `json:"asin"`
+	Status    string `json:"status"` // created, already_tracked, invalid, failed
+	TrackedID string `json:"tracked_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type GetImportJobRequest struct {
+	JobID string `path:"job_id"`
+}
+
+type GetImportJobResponse struct {
+	JobID          string            `json:"job_id"`
+	Status         string            `json:"status"` // queued, processing, completed, failed
+	Format         string            `json:"format"`
+	Total          int               `json:"total"`
+	Processed      int               `json:"processed"`
+	Progress       int               `json:"progress"` // 0-100
+	Created        int               `json:"created"`
+	AlreadyTracked int               `json:"already_tracked"`
+	Failed         int               `json:"failed"`
+	Results        []ImportRowResult `json:"results,omitempty"` // 完成后返回
+	ErrorMessage   string            `json:"error_message,omitempty"`
+	CreatedAt      string            `json:"created_at"`
+	StartedAt      string            `json:"started_at,omitempty"`
+	CompletedAt    string            `json:"completed_at,omitempty"`
+}
+
+type ExportHistoryRequest struct {
+	TrackedIDs string `form:"tracked_ids,optional"` // 逗号分隔的追踪ID，为空时导出全部追踪中的产品
+	TagIDs     string `form:"tag_ids,optional"`     // 逗号分隔的标签ID，只导出带有其中任一标签的产品
+	Metrics    string `form:"metrics,optional"`     // 逗号分隔: price, bsr, rating, review_count, buybox；默认全部
+	StartDate  string `form:"start_date,optional"`  // YYYY-MM-DD，默认 end_date 前30天
+	EndDate    string `form:"end_date,optional"`    // YYYY-MM-DD，默认今天 (含)
+	Format     string `form:"format,optional"`      // csv (默认), ndjson, xlsx
+	Async      bool   `form:"async,optional"`       // 后台生成文件；产品或天数较多时总是后台生成
+	Currency   string `form:"currency,optional"`    // 价格类指标 (price, buybox) 按记录时间的汇率换算的货币，默认使用原始币种
+}
+
+type ExportHistoryResponse struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status"` // queued
+	Format string `json:"format"`
+}
+
+type GetExportJobRequest struct {
+	JobID string `path:"job_id"`
+}
+
+type GetExportJobResponse struct {
+	JobID        string   `json:"job_id"`
+	Status       string   `json:"status"` // queued, processing, completed, failed, expired
+	Format       string   `json:"format"`
+	TrackedIDs   []string `json:"tracked_ids"`
+	Metrics      []string `json:"metrics"`
+	StartDate    string   `json:"start_date"`
+	EndDate      string   `json:"end_date"`
+	RowCount     int      `json:"row_count"`
+	FileName     string   `json:"file_name,omitempty"`
+	FileSize     int64    `json:"file_size"`
+	DownloadURL  string   `json:"download_url,omitempty"` // 完成后可下载
+	ErrorMessage string   `json:"error_message,omitempty"`
+	CreatedAt    string   `json:"created_at"`
+	StartedAt    string   `json:"started_at,omitempty"`
+	CompletedAt  string   `json:"completed_at,omitempty"`
+	ExpiresAt    string   `json:"expires_at,omitempty"`
+}
+
+type DownloadExportRequest struct {
+	JobID string `path:"job_id"`
+}
+
+type UpdateTrackingRequest struct {
+	ProductID            string   `path:"product_id"`
+	Alias                *string  `json:"alias,optional"` // 空字符串清除别名
+	PriceChangeThreshold *float64 `json:"price_change_threshold,optional"`
+	BSRChangeThreshold   *float64 `json:"bsr_change_threshold,optional"`
+	TrackingFrequency    *string  `json:"tracking_frequency,optional"` // hourly, daily, weekly
+	IsActive             *bool    `json:"is_active,optional"`          // false 暂停追踪, true 恢复追踪
+}
+
+type UpdateTrackingResponse struct {
+	TrackedID            string  `json:"tracked_id"`
+	ProductID            string  `json:"product_id"`
+	ASIN                 string  `json:"asin"`
+	Alias                string  `json:"alias,omitempty"`
+	IsActive             bool    `json:"is_active"`
+	TrackingFrequency    string  `json:"tracking_frequency"`
+	PriceChangeThreshold float64 `json:"price_change_threshold"`
+	BSRChangeThreshold   float64 `json:"bsr_change_threshold"`
+	NextCheckAt          string  `json:"next_check_at,omitempty"` // 暂停时为空
+	UpdatedAt            string  `json:"updated_at"`
+}
+
+type Tag struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Color        string `json:"color,omitempty"` // #RRGGBB
+	TrackedCount int    `json:"tracked_count"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type TagRef struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+type ListTagsResponse struct {
+	Tags []Tag `json:"tags"`
+}
+
+type CreateTagRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color,optional"` // #RGB 或 #RRGGBB
+}
+
+type UpdateTagRequest struct {
+	TagID string  `path:"tag_id"`
+	Name  *string `json:"name,optional"`
+	Color *string `json:"color,optional"` // 空字符串清除颜色
+}
+
+type DeleteTagRequest struct {
+	TagID string `path:"tag_id"`
+}
+
+type DeleteTagResponse struct {
+	Message string `json:"message"`
+}
+
+type AssignTagRequest struct {
+	TagID      string   `path:"tag_id"`
+	TrackedIDs []string `json:"tracked_ids"`
+	Remove     bool     `json:"remove,optional"` // true 时从这些产品移除标签
+}
+
+type AssignTagResponse struct {
+	TagID        string `json:"tag_id"`
+	Updated      int    `json:"updated"`
+	TrackedCount int    `json:"tracked_count"`
+}
+
+type SetTrackedTagsRequest struct {
+	TrackedID string   `path:"tracked_id"`
+	TagIDs    []string `json:"tag_ids"` // 替换为这些标签，空数组清除全部标签
+}
+
+type SetTrackedTagsResponse struct {
+	TrackedID string   `json:"tracked_id"`
+	Tags      []TagRef `json:"tags"`
+}
+
+type GetTagSummaryRequest struct {
+	Period string `form:"period,default=7d"` // 7d, 30d, 90d
+}
+
+type GetTagSummaryResponse struct {
+	Period string       `json:"period"`
+	Tags   []TagSummary `json:"tags"`
+}
+
+type TagSummary struct {
+	TagID           string  `json:"tag_id"`
+	Name            string  `json:"name"`
+	Color           string  `json:"color,omitempty"`
+	TrackedCount    int     `json:"tracked_count"`
+	ActiveCount     int     `json:"active_count"`     // 追踪中 (未暂停) 的产品数
+	ActiveAnomalies int     `json:"active_anomalies"` // 周期内追踪中产品的异常事件数
+	AvgPriceChange  float64 `json:"avg_price_change"` // 周期内价格变动百分比的平均值
+	PricedCount     int     `json:"priced_count"`     // 周期内有价格数据的产品数
 }
 
 type StopTrackingRequest struct {
@@ -144,26 +698,57 @@ type RefreshProductDataResponse struct {
 	Success     bool           `json:"success"`
 	Message     string         `json:"message"`
 	ProductData TrackedProduct `json:"product_data,omitempty"`
+	TaskID      string         `json:"task_id"` // 可通过 /products/refresh-tasks/:task_id 查询状态
+	Status      string         `json:"status"`
 }
 
-type AddMockPriceHistoryRequest struct {
-	TrackedID string  `path:"tracked_id"`
-	Price     float64 `json:"price"`
-	Currency  string  `json:"currency,optional"`
+type RefreshTaskResult struct {
+	Price        float64 `json:"price"`
+	Currency     string  `json:"currency"`
+	BSR          int     `json:"bsr,omitempty"`
+	Rating       float64 `json:"rating,omitempty"`
+	ReviewCount  int     `json:"review_count"`
+	Availability string  `json:"availability"`
+	Offers       int     `json:"offers"`
+	UpdatedAt    string  `json:"updated_at"`
 }
 
-type AddMockPriceHistoryResponse struct {
-	Success   bool   `json:"success"`
-	Message   string `json:"message"`
-	HistoryID string `json:"history_id"`
+type RefreshTask struct {
+	TaskID        string             `json:"task_id"`
+	TrackedID     string             `json:"tracked_id"`
+	ASIN          string             `json:"asin"`
+	State         string             `json:"state"` // pending, scheduled, active, retry, archived, completed
+	Retried       int                `json:"retried"`
+	MaxRetry      int                `json:"max_retry"`
+	LastError     string             `json:"last_error,omitempty"`
+	LastFailedAt  string             `json:"last_failed_at,omitempty"`
+	NextProcessAt string             `json:"next_process_at,omitempty"`
+	RequestedAt   string             `json:"requested_at,omitempty"`
+	CompletedAt   string             `json:"completed_at,omitempty"`
+	Result        *RefreshTaskResult `json:"result,omitempty"` // 仅 completed 状态
+}
+
+type GetRefreshTaskRequest struct {
+	TaskID string `path:"task_id"`
+}
+
+type ListRefreshTasksRequest struct {
+	ProductID string `path:"product_id"`
+	Limit     int    `form:"limit,default=10"`
+}
+
+type ListRefreshTasksResponse struct {
+	Tasks []RefreshTask `json:"tasks"`
 }
 
 type GetAnomalyEventsRequest struct {
 	Page      int    `form:"page,default=1"`
 	Limit     int    `form:"limit,default=20"`
-	EventType string `form:"event_type,optional"` // price_change, bsr_change, rating_change, review_count_change, buybox_change
+	Cursor    string `form:"cursor,optional"`     // 上一页返回的 next_cursor，提供时按游标分页 (不统计 total)
+	EventType string `form:"event_type,optional"` // price_change, bsr_change, subcategory_bsr_change, rating_change, review_count_change, buybox_change, listing_changed, hijacker_detected, map_violation, out_of_stock, back_in_stock
 	Severity  string `form:"severity,optional"`   // info, warning, critical
 	ASIN      string `form:"asin,optional"`
+	TagIDs    string `form:"tag_ids,optional"` // 逗号分隔的标签ID，只返回带有其中任一标签的产品的事件
 }
 
 type GetAnomalyEventsResponse struct {
@@ -172,17 +757,38 @@ type GetAnomalyEventsResponse struct {
 }
 
 type AnomalyEvent struct {
-	ID               string  `json:"id"`
-	ProductID        string  `json:"product_id"`
-	ASIN             string  `json:"asin"`
-	EventType        string  `json:"event_type"`
-	OldValue         float64 `json:"old_value,omitempty"`
-	NewValue         float64 `json:"new_value,omitempty"`
-	ChangePercentage float64 `json:"change_percentage,omitempty"`
-	Threshold        float64 `json:"threshold,omitempty"`
-	Severity         string  `json:"severity"`
-	CreatedAt        string  `json:"created_at"`
-	ProductTitle     string  `json:"product_title,omitempty"`
+	ID               string                 `json:"id"`
+	ProductID        string                 `json:"product_id"`
+	ASIN             string                 `json:"asin"`
+	EventType        string                 `json:"event_type"`
+	OldValue         float64                `json:"old_value,omitempty"`
+	NewValue         float64                `json:"new_value,omitempty"`
+	ChangePercentage float64                `json:"change_percentage,omitempty"`
+	Threshold        float64                `json:"threshold,omitempty"`
+	Severity         string                 `json:"severity"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"` // 事件附加信息，例如 listing_changed 的字段级差异
+	CreatedAt        string                 `json:"created_at"`
+	ProductTitle     string                 `json:"product_title,omitempty"`
+	TrackedID        string                 `json:"tracked_id,omitempty"`
+	Tags             []TagRef               `json:"tags,omitempty"`
+}
+
+type UsageMetric struct {
+	Used      int64 `json:"used"`
+	Limit     int   `json:"limit"`     // 0 表示不限
+	Remaining int   `json:"remaining"` // 不限时为 -1
+}
+
+type GetUsageResponse struct {
+	WorkspaceID          string      `json:"workspace_id"`
+	Plan                 string      `json:"plan"` // 工作区所有者的计划
+	TrackedProducts      UsageMetric `json:"tracked_products"`
+	CompetitorGroups     UsageMetric `json:"competitor_groups"`
+	ReportsThisMonth     UsageMetric `json:"reports_this_month"` // 竞品报告和优化分析，按自然月 (UTC) 计
+	ReportsResetAt       string      `json:"reports_reset_at"`
+	MinRefreshFrequency  string      `json:"min_refresh_frequency"`  // 允许的最高追踪频率
+	HistoryRetentionDays int         `json:"history_retention_days"` // 0 表示不限
+	RateLimitPerMinute   int         `json:"rate_limit_per_minute"`
 }
 
 type PingResponse struct {
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidCursor 游标无法解析
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor 键集分页游标，指向上一页最后一条记录的排序时间和ID
+// 对客户端不透明，编码为 base64url(JSON)
+type Cursor struct {
+	Time time.Time `json:"t"`
+	ID   string    `json:"id"`
+}
+
+// CursorResponse 游标分页结果 (由 NewCursorResponse 生成，各服务复制到自己的 Pagination)，NextCursor 为空表示没有更多数据
+type CursorResponse struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Limit      int    `json:"limit"`
+}
+
+// EncodeCursor 编码游标
+func EncodeCursor(t time.Time, id string) string {
+	data, _ := json.Marshal(Cursor{Time: t.UTC(), ID: id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor 解码游标，空字符串返回 nil
+func DecodeCursor(value string) (*Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" || cursor.Time.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// Condition 返回键集分页条件及参数，用于 db.Where(cond, args...)
+// 降序时取 (timeColumn, idColumn) 小于游标的记录，升序时取大于游标的记录
+func (c *Cursor) Condition(timeColumn, idColumn string, desc bool) (string, []interface{}) {
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	return fmt.Sprintf("(%s, %s) %s (?, ?)", timeColumn, idColumn, op), []interface{}{c.Time, c.ID}
+}
+
+// KeysetOrder 键集分页的排序子句，时间和ID同方向排序以便使用复合索引
+func KeysetOrder(timeColumn, idColumn string, desc bool) string {
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("%s %s, %s %s", timeColumn, direction, idColumn, direction)
+}
+
+// NewCursorResponse 根据多取一条 (limit+1) 的查询结果数量生成分页响应
+// last 返回本页最后一条记录的排序时间和ID，仅在还有更多数据时调用
+func NewCursorResponse(fetched, limit int, last func() (time.Time, string)) CursorResponse {
+	resp := CursorResponse{Limit: limit}
+	if fetched > limit {
+		t, id := last()
+		resp.HasMore = true
+		resp.NextCursor = EncodeCursor(t, id)
+	}
+	return resp
+}
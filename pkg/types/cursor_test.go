@@ -0,0 +1,59 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	at := time.Date(2025, 3, 1, 8, 30, 15, 123456000, time.FixedZone("CST", 8*3600))
+	encoded := EncodeCursor(at, "7f1c1b4e-0000-4000-8000-000000000001")
+	assert.NotContains(t, encoded, "=")
+
+	cursor, err := DecodeCursor(encoded)
+	assert.NoError(t, err)
+	assert.True(t, at.Equal(cursor.Time))
+	assert.Equal(t, "7f1c1b4e-0000-4000-8000-000000000001", cursor.ID)
+
+	cursor, err = DecodeCursor("")
+	assert.NoError(t, err)
+	assert.Nil(t, cursor)
+
+	for _, invalid := range []string{"not base64!", "e30", EncodeCursor(time.Time{}, "x")} {
+		_, err = DecodeCursor(invalid)
+		assert.ErrorIs(t, err, ErrInvalidCursor, invalid)
+	}
+}
+
+func TestCursorCondition(t *testing.T) {
+	at := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	cursor := &Cursor{Time: at, ID: "a"}
+
+	cond, args := cursor.Condition("ae.created_at", "ae.id", true)
+	assert.Equal(t, "(ae.created_at, ae.id) < (?, ?)", cond)
+	assert.Equal(t, []interface{}{at, "a"}, args)
+
+	cond, _ = cursor.Condition("created_at", "id", false)
+	assert.Equal(t, "(created_at, id) > (?, ?)", cond)
+
+	assert.Equal(t, "created_at DESC, id DESC", KeysetOrder("created_at", "id", true))
+}
+
+func TestNewCursorResponse(t *testing.T) {
+	at := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	last := func() (time.Time, string) { return at, "b" }
+
+	resp := NewCursorResponse(21, 20, last)
+	assert.True(t, resp.HasMore)
+	assert.Equal(t, EncodeCursor(at, "b"), resp.NextCursor)
+
+	resp = NewCursorResponse(20, 20, func() (time.Time, string) {
+		t.Fatal("last should not be called without more data")
+		return time.Time{}, ""
+	})
+	assert.False(t, resp.HasMore)
+	assert.Empty(t, resp.NextCursor)
+	assert.Equal(t, 20, resp.Limit)
+}